@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/beego/i18n"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/migrations"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagehash"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagevalidator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+
+	apiKeyRepository "github.com/radyatamaa/technical-test-aichat/internal/api_key/repository"
+	apiKeyUsecase "github.com/radyatamaa/technical-test-aichat/internal/api_key/usecase"
+	campaignRepository "github.com/radyatamaa/technical-test-aichat/internal/campaign/repository"
+	campaignUsecase "github.com/radyatamaa/technical-test-aichat/internal/campaign/usecase"
+	campaignRuleRepository "github.com/radyatamaa/technical-test-aichat/internal/campaign_rule/repository"
+	currencyRateRepository "github.com/radyatamaa/technical-test-aichat/internal/currency_rate/repository"
+	currencyRateUsecase "github.com/radyatamaa/technical-test-aichat/internal/currency_rate/usecase"
+	customerVoucherRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher/repository"
+	customerVoucherUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher/usecase"
+	customerVoucherBookRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/repository"
+	customerVoucherBookUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/usecase"
+	customerVoucherBookAttemptRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book_attempt/repository"
+	customerVoucherBookEventRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book_event/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/eligibility"
+	"github.com/radyatamaa/technical-test-aichat/internal/faceverification"
+	idempotencyKeyRepository "github.com/radyatamaa/technical-test-aichat/internal/idempotency_key/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	purchaseTransactionRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/repository"
+	purchaseTransactionUsecase "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/usecase"
+	purchaseTransactionImportRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction_import/repository"
+	purchaseTransactionRefundRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction_refund/repository"
+	roleRepository "github.com/radyatamaa/technical-test-aichat/internal/role/repository"
+	roleUsecase "github.com/radyatamaa/technical-test-aichat/internal/role/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/seeder"
+	"github.com/radyatamaa/technical-test-aichat/internal/storage"
+
+	customerRepository "github.com/radyatamaa/technical-test-aichat/internal/customer/repository"
+	customerUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer/usecase"
+)
+
+// application configuration, database, logger and use cases shared by every command
+type application struct {
+	// global execution timeout
+	serverTimeout int64
+	// app version
+	appVersion string
+	// init data
+	initData string
+	// sample data of the seed command and init data
+	seedConfig seeder.Config
+	// campaign used by the legacy voucher endpoints
+	defaultCampaignId int
+	// interval in second of releasing expired voucher bookings, 0 disable the job
+	releaseExpiredBookingInterval int
+	// bookings released per batch
+	releaseExpiredBookingBatch int
+	// api key of the admin endpoints
+	adminApiKey string
+	// bearer token validation of the customer endpoints
+	jwtConfig middlewares.JwtConfig
+	// time in second given to running requests and jobs on shutdown
+	shutdownTimeout int
+	// request limits per client ip, per customer of the customer endpoints and per api key, disabled when the limit is 0
+	ipRateLimit       middlewares.RateLimitConfig
+	customerRateLimit middlewares.RateLimitConfig
+	apiKeyRateLimit   middlewares.RateLimitConfig
+	// responses replayed to the retries of requests with an Idempotency-Key header
+	idempotencyConfig middlewares.IdempotencyConfig
+	// interval in second of deleting expired idempotency keys of the database store, 0 disable the job
+	deleteExpiredIdempotencyKeyInterval int
+	// idempotency keys deleted per batch
+	deleteExpiredIdempotencyKeyBatch int
+
+	db       *gorm.DB
+	redis    *redis.Client
+	zapLog   zaplogger.Logger
+	migrator *database.Migrator
+
+	customerUcase            domain.CustomerUseCase
+	campaignUcase            domain.CampaignUseCase
+	customerVoucherUcase     domain.CustomerVoucherUseCase
+	customerVoucherBookUcase domain.CustomerVoucherBookUseCase
+	purchaseTransactionUcase domain.PurchaseTransactionUseCase
+	currencyRateUcase        domain.CurrencyRateUseCase
+	apiKeyUcase              domain.ApiKeyUseCase
+	roleUcase                domain.RoleUseCase
+
+	idempotencyKeyRepo domain.MysqlIdempotencyKeyRepository
+}
+
+// newApplication load conf/app.ini, connect the database and build the use cases
+func newApplication() *application {
+	err := beego.LoadAppConfig("ini", "conf/app.ini")
+	if err != nil {
+		panic(err)
+	}
+	app := &application{}
+	// global execution timeout
+	app.serverTimeout = beego.AppConfig.DefaultInt64("serverTimeout", 60)
+	// global execution timeout
+	requestTimeout := beego.AppConfig.DefaultInt("executionTimeout", 5)
+	// web hook to slack error log
+	slackWebHookUrl := beego.AppConfig.DefaultString("slackWebhookUrlLog", "")
+	// app version
+	app.appVersion = beego.AppConfig.DefaultString("version", "1")
+	// log path
+	logPath := beego.AppConfig.DefaultString("logPath", "./logs/api.log")
+	// init data
+	app.initData = beego.AppConfig.DefaultString("initData", "true")
+	// sample data of the seed command and init data
+	app.seedConfig = seeder.DefaultConfig()
+	app.seedConfig.RandomSeed = beego.AppConfig.DefaultInt64("seed::randomSeed", app.seedConfig.RandomSeed)
+	if value := beego.AppConfig.DefaultString("seed::referenceTime", ""); value != "" {
+		referenceTime, err := time.ParseInLocation(helper.DateTimeFormatDefault, value, time.Local)
+		if err != nil {
+			panic(fmt.Sprintf("seed::referenceTime: %v", err))
+		}
+		app.seedConfig.ReferenceTime = referenceTime
+	}
+	app.seedConfig.BatchSize = beego.AppConfig.DefaultInt("seed::batchSize", app.seedConfig.BatchSize)
+	app.seedConfig.Customers = beego.AppConfig.DefaultInt("seed::customersPerScenario", app.seedConfig.Customers)
+	app.seedConfig.Vouchers = beego.AppConfig.DefaultInt("seed::vouchers", app.seedConfig.Vouchers)
+	app.seedConfig.Scenarios = strings.Split(beego.AppConfig.DefaultString("seed::scenarios", strings.Join(app.seedConfig.Scenarios, "|")), "|")
+	// campaign used by the legacy voucher endpoints
+	app.defaultCampaignId = beego.AppConfig.DefaultInt("defaultCampaignId", 1)
+	// eligibility rules of campaigns without their own rule set
+	defaultEligibilityRules, err := eligibility.ParseRules(beego.AppConfig.DefaultString("eligibility::rules", "purchase_count:3:30|spend_sum:100:0:USD"))
+	if err != nil {
+		panic(err)
+	}
+	// interval in second of releasing expired voucher bookings, 0 disable the job
+	app.releaseExpiredBookingInterval = beego.AppConfig.DefaultInt("scheduler::releaseExpiredBookingInterval", 60)
+	// bookings released per batch
+	app.releaseExpiredBookingBatch = beego.AppConfig.DefaultInt("scheduler::releaseExpiredBookingBatch", 100)
+	// limits of the uploaded verification photo
+	imageConfig := imagevalidator.DefaultConfig()
+	imageConfig.MaxBytes = beego.AppConfig.DefaultInt64("image::maxBytes", imageConfig.MaxBytes)
+	imageConfig.MinWidth = beego.AppConfig.DefaultInt("image::minWidth", imageConfig.MinWidth)
+	imageConfig.MinHeight = beego.AppConfig.DefaultInt("image::minHeight", imageConfig.MinHeight)
+	imageConfig.MaxWidth = beego.AppConfig.DefaultInt("image::maxWidth", imageConfig.MaxWidth)
+	imageConfig.MaxHeight = beego.AppConfig.DefaultInt("image::maxHeight", imageConfig.MaxHeight)
+	imageConfig.AllowedTypes = strings.Split(beego.AppConfig.DefaultString("image::allowedTypes", strings.Join(imageConfig.AllowedTypes, "|")), "|")
+	// face verification provider, local heuristic or external http service
+	faceVerifier, err := faceverification.NewFaceVerifier(faceverification.Config{
+		Provider:              beego.AppConfig.DefaultString("faceVerification::provider", faceverification.ProviderLocal),
+		MinSkinRatio:          beego.AppConfig.DefaultFloat("faceVerification::minSkinRatio", 0.25),
+		InconclusiveSkinRatio: beego.AppConfig.DefaultFloat("faceVerification::inconclusiveSkinRatio", 0.1),
+		Url:                   beego.AppConfig.DefaultString("faceVerification::url", ""),
+		ApiKey:                beego.AppConfig.DefaultString("faceVerification::apiKey", ""),
+		Timeout:               time.Duration(beego.AppConfig.DefaultInt("faceVerification::timeout", 10)) * time.Second,
+		MinConfidence:         beego.AppConfig.DefaultFloat("faceVerification::minConfidence", 0.8),
+	})
+	if err != nil {
+		panic(err)
+	}
+	// storage of uploaded files, local filesystem or s3 compatible service
+	fileStorage, err := storage.NewStorage(storage.Config{
+		Driver:    beego.AppConfig.DefaultString("storage::driver", storage.DriverLocal),
+		Path:      beego.AppConfig.DefaultString("storage::path", "./storage"),
+		Endpoint:  beego.AppConfig.DefaultString("storage::endpoint", ""),
+		Bucket:    beego.AppConfig.DefaultString("storage::bucket", ""),
+		Region:    beego.AppConfig.DefaultString("storage::region", ""),
+		AccessKey: beego.AppConfig.DefaultString("storage::accessKey", ""),
+		SecretKey: beego.AppConfig.DefaultString("storage::secretKey", ""),
+		Timeout:   time.Duration(beego.AppConfig.DefaultInt("storage::timeout", 30)) * time.Second,
+	})
+	if err != nil {
+		panic(err)
+	}
+	// photo verification attempts of a booking and duplicate photo detection
+	photoVerificationConfig := customerUsecase.PhotoVerificationConfig{
+		MaxAttempts:          beego.AppConfig.DefaultInt("maxVerificationAttempts", 3),
+		DuplicateMaxDistance: beego.AppConfig.DefaultInt("duplicatePhoto::maxDistance", 6),
+		DuplicateAction:      beego.AppConfig.DefaultString("duplicatePhoto::action", customerUsecase.DuplicatePhotoReject),
+	}
+	// the duplicate check reads the photos sharing a byte of the hash, it misses none below imagehash.Bands bits
+	if photoVerificationConfig.DuplicateMaxDistance >= imagehash.Bands {
+		panic(fmt.Sprintf("duplicatePhoto::maxDistance must be below %d", imagehash.Bands))
+	}
+	importConfig := purchaseTransactionUsecase.ImportConfig{
+		BatchSize:  beego.AppConfig.DefaultInt("import::batchSize", 500),
+		Timeout:    time.Duration(beego.AppConfig.DefaultInt("import::timeout", 600)) * time.Second,
+		StaleAfter: time.Duration(beego.AppConfig.DefaultInt("import::staleAfter", 300)) * time.Second,
+	}
+	// api key of the admin endpoints
+	app.adminApiKey = beego.AppConfig.DefaultString("adminApiKey", "")
+	// bearer token validation of the customer endpoints, the subject of the token is the customer id.
+	// Tokens are verified with the public key when one is configured, with the secret otherwise, and
+	// the api refuses to start with neither
+	app.jwtConfig = middlewares.JwtConfig{
+		Algorithm:    beego.AppConfig.DefaultString("jwt::algorithm", middlewares.JwtAlgorithmHS256),
+		Secret:       []byte(beego.AppConfig.DefaultString("jwt::secret", "")),
+		Issuer:       beego.AppConfig.DefaultString("jwt::issuer", ""),
+		Audience:     beego.AppConfig.DefaultString("jwt::audience", ""),
+		SubjectParam: ":id",
+	}
+	if publicKeyPath := beego.AppConfig.DefaultString("jwt::publicKeyPath", ""); publicKeyPath != "" {
+		publicKey, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			panic(err)
+		}
+		app.jwtConfig.PublicKey, err = jwt.ParseRSAPublicKeyFromPEM(publicKey)
+		if err != nil {
+			panic(err)
+		}
+		app.jwtConfig.Algorithm = middlewares.JwtAlgorithmRS256
+	}
+	// time in second an instance waits for another one migrating the schema
+	migrationLockTimeout := beego.AppConfig.DefaultInt("database::migrationLockTimeout", 60)
+	// time in second given to running requests and jobs on shutdown
+	app.shutdownTimeout = beego.AppConfig.DefaultInt("shutdownTimeout", 30)
+
+	// database initialization
+	db := database.DB()
+	app.db = db
+	// unique and foreign key validation rules query the database
+	validator.Validate.SetDatabaseConnection(db)
+
+	// language
+	lang := beego.AppConfig.DefaultString("lang", "en|id")
+	languages := strings.Split(lang, "|")
+	for _, value := range languages {
+		if err := i18n.SetMessage(value, "./conf/"+value+".ini"); err != nil {
+			panic("Failed to set message file for l10n")
+		}
+	}
+
+	// global execution timeout to second
+	timeoutContext := time.Duration(requestTimeout) * time.Second
+
+	// zap logger
+	zapLog := zaplogger.NewZapLogger(logPath, slackWebHookUrl)
+	app.zapLog = zapLog
+
+	// redis shared by the instances, connected on first use
+	app.redis = redis.NewClient(&redis.Options{
+		Addr:     beego.AppConfig.DefaultString("redis::addr", "localhost:6379"),
+		Password: beego.AppConfig.DefaultString("redis::password", ""),
+		DB:       beego.AppConfig.DefaultInt("redis::db", 0),
+	})
+
+	// rate limits, the buckets are kept in memory of the instance or in redis
+	var rateLimitStore middlewares.RateLimitStore
+	switch store := beego.AppConfig.DefaultString("rateLimit::store", "memory"); store {
+	case "memory":
+		rateLimitStore = middlewares.NewMemoryRateLimitStore()
+	case "redis":
+		rateLimitStore = middlewares.NewRedisRateLimitStore(app.redis, "ratelimit:")
+	default:
+		panic(fmt.Errorf("unknown rate limit store %q", store))
+	}
+	rateLimitKeyByIP := middlewares.RateLimitKeyByIP
+	if beego.AppConfig.DefaultBool("rateLimit::trustForwardedFor", false) {
+		rateLimitKeyByIP = middlewares.RateLimitKeyByForwardedIP
+	}
+	app.ipRateLimit = middlewares.RateLimitConfig{
+		Name:    "ip",
+		Store:   rateLimitStore,
+		KeyFunc: rateLimitKeyByIP,
+		Limit:   beego.AppConfig.DefaultInt("rateLimit::ipLimit", 120),
+		Period:  time.Duration(beego.AppConfig.DefaultInt("rateLimit::ipPeriod", 60)) * time.Second,
+		Logger:  zapLog,
+	}
+	app.customerRateLimit = middlewares.RateLimitConfig{
+		Name:    "customer",
+		Store:   rateLimitStore,
+		KeyFunc: middlewares.RateLimitKeyByCustomer(":id"),
+		Limit:   beego.AppConfig.DefaultInt("rateLimit::customerLimit", 20),
+		Period:  time.Duration(beego.AppConfig.DefaultInt("rateLimit::customerPeriod", 60)) * time.Second,
+		Logger:  zapLog,
+	}
+	app.apiKeyRateLimit = middlewares.RateLimitConfig{
+		Name:    "apikey",
+		Store:   rateLimitStore,
+		KeyFunc: middlewares.RateLimitKeyByApiKey("X-API-KEY"),
+		Limit:   beego.AppConfig.DefaultInt("rateLimit::apiKeyLimit", 600),
+		Period:  time.Duration(beego.AppConfig.DefaultInt("rateLimit::apiKeyPeriod", 60)) * time.Second,
+		Logger:  zapLog,
+	}
+
+	// versioned schema migrations of the configured driver
+	app.migrator, err = database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		panic(err)
+	}
+	app.migrator.LockTimeout = time.Duration(migrationLockTimeout) * time.Second
+
+	// init repository
+	customerRepo := customerRepository.NewMysqlCustomerRepository(db, zapLog)
+	customerVoucherRepo := customerVoucherRepository.NewMysqlCustomerVoucherRepository(db, zapLog)
+	customerVoucherBookRepo := customerVoucherBookRepository.NewMysqlCCustomerVoucherBookRepository(db, zapLog)
+	purchaseTransactionRepo := purchaseTransactionRepository.NewPurchaseTransactionRepository(db, zapLog)
+	campaignRepo := campaignRepository.NewMysqlCampaignRepository(db, zapLog)
+	campaignRuleRepo := campaignRuleRepository.NewMysqlCampaignRuleRepository(db, zapLog)
+	customerVoucherBookEventRepo := customerVoucherBookEventRepository.NewMysqlCustomerVoucherBookEventRepository(db, zapLog)
+	customerVoucherBookAttemptRepo := customerVoucherBookAttemptRepository.NewMysqlCustomerVoucherBookAttemptRepository(db, zapLog)
+	purchaseTransactionImportRepo := purchaseTransactionImportRepository.NewMysqlPurchaseTransactionImportRepository(db, zapLog)
+	purchaseTransactionRefundRepo := purchaseTransactionRefundRepository.NewMysqlPurchaseTransactionRefundRepository(db, zapLog)
+	currencyRateRepo := currencyRateRepository.NewMysqlCurrencyRateRepository(db, zapLog)
+	apiKeyRepo := apiKeyRepository.NewMysqlApiKeyRepository(db, zapLog)
+	roleRepo := roleRepository.NewMysqlRoleRepository(db, zapLog)
+	app.idempotencyKeyRepo = idempotencyKeyRepository.NewMysqlIdempotencyKeyRepository(db, zapLog)
+
+	// idempotency keys, the responses are kept in the database or in redis
+	app.idempotencyConfig = middlewares.IdempotencyConfig{
+		LockTimeout: time.Duration(beego.AppConfig.DefaultInt64("idempotency::lockTimeout", app.serverTimeout)) * time.Second,
+		Expiration:  time.Duration(beego.AppConfig.DefaultInt("idempotency::expiration", 86400)) * time.Second,
+		MaxBytes:    beego.AppConfig.DefaultInt64("idempotency::maxBytes", imageConfig.MaxBytes+1<<20),
+		Logger:      zapLog,
+	}
+	switch store := beego.AppConfig.DefaultString("idempotency::store", "database"); store {
+	case "database":
+		app.idempotencyConfig.Store = middlewares.NewDatabaseIdempotencyStore(app.idempotencyKeyRepo)
+		app.deleteExpiredIdempotencyKeyInterval = beego.AppConfig.DefaultInt("idempotency::deleteExpiredInterval", 3600)
+		app.deleteExpiredIdempotencyKeyBatch = beego.AppConfig.DefaultInt("idempotency::deleteExpiredBatch", 1000)
+	case "redis":
+		app.idempotencyConfig.Store = middlewares.NewRedisIdempotencyStore(app.redis, "idempotency:")
+	default:
+		panic(fmt.Errorf("unknown idempotency store %q", store))
+	}
+
+	// currency rates convert purchases to the campaign currency in the eligibility engine
+	app.currencyRateUcase = currencyRateUsecase.NewCurrencyRateUseCase(timeoutContext, currencyRateRepo, zapLog)
+
+	// init eligibility engine
+	eligibilityEngine := eligibility.NewEligibilityEngine(defaultEligibilityRules,
+		campaignRuleRepo,
+		purchaseTransactionRepo,
+		customerVoucherRepo,
+		app.currencyRateUcase)
+
+	// init usecase
+	app.customerVoucherBookUcase = customerVoucherBookUsecase.NewCustomerVoucherBookUseCase(timeoutContext,
+		customerRepo,
+		customerVoucherRepo,
+		customerVoucherBookRepo,
+		customerVoucherBookEventRepo,
+		customerVoucherBookAttemptRepo,
+		fileStorage,
+		zapLog)
+	app.customerUcase = customerUsecase.NewCustomerUseCase(timeoutContext,
+		customerRepo,
+		customerVoucherRepo,
+		customerVoucherBookRepo,
+		purchaseTransactionRepo,
+		campaignRepo,
+		eligibilityEngine,
+		app.customerVoucherBookUcase,
+		imagevalidator.NewValidator(imageConfig),
+		faceVerifier,
+		fileStorage,
+		customerVoucherBookAttemptRepo,
+		photoVerificationConfig,
+		zapLog)
+	app.campaignUcase = campaignUsecase.NewCampaignUseCase(timeoutContext, campaignRepo, campaignRuleRepo, zapLog)
+	app.customerVoucherUcase = customerVoucherUsecase.NewCustomerVoucherUseCase(timeoutContext, campaignRepo, customerVoucherRepo, zapLog)
+	app.purchaseTransactionUcase = purchaseTransactionUsecase.NewPurchaseTransactionUseCase(timeoutContext,
+		importConfig,
+		customerRepo,
+		purchaseTransactionRepo,
+		purchaseTransactionImportRepo,
+		purchaseTransactionRefundRepo,
+		zapLog)
+	app.apiKeyUcase = apiKeyUsecase.NewApiKeyUseCase(timeoutContext, apiKeyRepo, roleRepo, zapLog)
+	app.roleUcase = roleUsecase.NewRoleUseCase(timeoutContext, roleRepo, apiKeyRepo, zapLog)
+
+	return app
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// sweepBookings release the expired voucher bookings once, the job the server runs periodically
+func sweepBookings(args []string) int {
+	flags := newFlagSet("bookings sweep")
+	batch := flags.Int("batch", 0, "bookings released per batch, scheduler::releaseExpiredBookingBatch when 0")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *batch < 0 {
+		return usageError("bookings sweep")
+	}
+	app := newApplication()
+	if *batch == 0 {
+		*batch = app.releaseExpiredBookingBatch
+	}
+
+	released, err := app.customerVoucherBookUcase.ReleaseExpiredBookings(context.Background(), *batch)
+	fmt.Printf("%d expired bookings released\n", released)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
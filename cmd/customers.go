@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// exportCustomers write the customers as csv to the output file or stdout, the summary goes to stderr
+// so the csv can be piped
+func exportCustomers(args []string) int {
+	flags := newFlagSet("customers export")
+	output := flags.String("output", "", "csv file written, stdout when empty")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return usageError("customers export")
+	}
+	app := newApplication()
+
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	total, err := app.customerUcase.ExportCustomers(context.Background(), writer)
+	fmt.Fprintf(os.Stderr, "%d customers exported\n", total)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,94 @@
+// Package cmd command line of the service. serve starts the http api, the other commands run
+// maintenance tasks with the same configuration, database connection and logger
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// @title Api Gateway V1
+// @version v1
+// @contact.name radyatama
+// @contact.email mohradyatama24@gmail.com
+// @description api "API Gateway v1"
+// @BasePath /api
+// @query.collection.format multi
+
+// command subcommand of the binary, name is one or two words and run returns the exit code
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "serve", usage: "serve", description: "start the http api and the background jobs (default command)", run: serve},
+		{name: "migrate", usage: "migrate up|down [steps]|status", description: "apply, revert or list the schema migrations", run: migrate},
+		{name: "seed", usage: "seed [-scenarios <a,b>] [-seed <n>] [-time <datetime>]", description: "store the sample data scenarios not stored yet", run: seed},
+		{name: "vouchers generate", usage: "vouchers generate -campaign <id> -count <n> [-length <n>]", description: "store random voucher codes of a campaign", run: generateVouchers},
+		{name: "vouchers import", usage: "vouchers import -campaign <id> <file.csv>", description: "store the voucher_code column of a csv file as vouchers of a campaign", run: importVouchers},
+		{name: "bookings sweep", usage: "bookings sweep [-batch <n>]", description: "release the expired voucher bookings", run: sweepBookings},
+		{name: "customers export", usage: "customers export [-output <file.csv>]", description: "write the customers as csv, to stdout by default", run: exportCustomers},
+		{name: "purchase-transactions import", usage: "purchase-transactions import <file.csv>", description: "import a csv file of purchase transactions", run: importPurchaseTransactions},
+	}
+}
+
+// Execute run the command named by the arguments, without arguments the api is served. Returns the exit code,
+// 2 for a usage error
+func Execute(args []string) int {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd.run(args[len(words):])
+		}
+	}
+
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-60s %s\n", cmd.usage, cmd.description)
+	}
+}
+
+// usageError print the usage of the command, the returned exit code is 2
+func usageError(name string) int {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			fmt.Fprintln(os.Stderr, "usage: "+cmd.usage)
+		}
+	}
+	return 2
+}
+
+// newFlagSet flags of the command, errors are reported to stderr
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		usageError(name)
+		flags.PrintDefaults()
+	}
+	return flags
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// migrate run a migration command, up applies the pending migrations, down reverts the last
+// steps migrations (one by default) and status lists the migrations with the time they were applied
+func migrate(args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		return usageError("migrate")
+	}
+	steps := 1
+	switch args[0] {
+	case "up", "status":
+		if len(args) != 1 {
+			return usageError("migrate")
+		}
+	case "down":
+		if len(args) == 2 {
+			value, err := strconv.Atoi(args[1])
+			if err != nil || value < 1 {
+				return usageError("migrate")
+			}
+			steps = value
+		}
+	default:
+		return usageError("migrate")
+	}
+	app := newApplication()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := app.migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		reverted, err := app.migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		statuses, err := app.migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	}
+	return 0
+}
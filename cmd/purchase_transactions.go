@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// importPurchaseTransactions import the csv file given as argument, the exit code is non zero when the import fails
+func importPurchaseTransactions(args []string) int {
+	if len(args) != 1 {
+		return usageError("purchase-transactions import")
+	}
+	app := newApplication()
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	result, err := app.purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), filepath.Base(args[0]), file)
+	if result != nil {
+		fmt.Printf("import %d %s: %d imported, %d duplicate, %d failed\n",
+			result.ID, result.Status, result.ImportedRows, result.DuplicateRows, result.FailedRows)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
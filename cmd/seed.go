@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/seeder"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+)
+
+// seed store the sample data scenarios not stored yet, the configured scenarios unless -scenarios is given
+func seed(args []string) int {
+	flags := newFlagSet("seed")
+	scenarios := flags.String("scenarios", "", "comma separated scenarios, "+strings.Join(seeder.Scenarios, ", "))
+	randomSeed := flags.Int64("seed", 0, "random seed of the generated data, seed::randomSeed when 0")
+	referenceTime := flags.String("time", "", "reference time of the generated dates as 2006-01-02 15:04:05, seed::referenceTime when empty")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return usageError("seed")
+	}
+	app := newApplication()
+
+	config := app.seedConfig
+	if *scenarios != "" {
+		config.Scenarios = strings.Split(*scenarios, ",")
+	}
+	if *randomSeed != 0 {
+		config.RandomSeed = *randomSeed
+	}
+	if *referenceTime != "" {
+		value, err := time.ParseInLocation(helper.DateTimeFormatDefault, *referenceTime, time.Local)
+		if err != nil {
+			return usageError("seed")
+		}
+		config.ReferenceTime = value
+	}
+
+	seeded, err := seeder.Seed(context.Background(), app.db, config)
+	for _, name := range seeded {
+		fmt.Printf("seeded %s\n", name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(seeded) == 0 {
+		fmt.Println("sample data is already stored")
+	}
+	return 0
+}
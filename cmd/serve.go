@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/beego/beego/v2/server/web/filter/cors"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/internal/seeder"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/scheduler"
+
+	apiKeyHandler "github.com/radyatamaa/technical-test-aichat/internal/api_key/delivery/http/v1"
+	campaignHandler "github.com/radyatamaa/technical-test-aichat/internal/campaign/delivery/http/v1"
+	currencyRateHandler "github.com/radyatamaa/technical-test-aichat/internal/currency_rate/delivery/http/v1"
+	customerHandler "github.com/radyatamaa/technical-test-aichat/internal/customer/delivery/http/v1"
+	customerVoucherBookHandler "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/delivery/http/v1"
+	purchaseTransactionHandler "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/delivery/http/v1"
+	roleHandler "github.com/radyatamaa/technical-test-aichat/internal/role/delivery/http/v1"
+)
+
+// serve start the http api and the background jobs until SIGINT or SIGTERM
+func serve(args []string) int {
+	if len(args) != 0 {
+		return usageError("serve")
+	}
+	app := newApplication()
+
+	// beego config
+	beego.BConfig.Log.AccessLogs = false
+	beego.BConfig.Log.EnableStaticLogs = false
+	beego.BConfig.Listen.ServerTimeOut = app.serverTimeout
+
+	if beego.BConfig.RunMode == "dev" {
+		// apply pending migrations on start in dev environment
+		if _, err := app.migrator.Up(context.Background()); err != nil {
+			panic(err)
+		}
+	}
+
+	if app.initData == "true" {
+		// scenarios already stored are skipped, a failure leaves the api running without sample data
+		if _, err := seeder.Seed(context.Background(), app.db, app.seedConfig); err != nil {
+			app.zapLog.Errorf("seed data: %v", err)
+		}
+	}
+	if beego.BConfig.RunMode != "prod" {
+		// static files swagger
+		beego.BConfig.WebConfig.DirectoryIndex = true
+		beego.BConfig.WebConfig.StaticDir["/swagger"] = "swagger"
+	}
+
+	// middleware init
+	beego.InsertFilter("*", beego.BeforeRouter, cors.Allow(&cors.Options{
+		AllowMethods:    []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowAllOrigins: true,
+	}))
+
+	beego.InsertFilterChain("*", middlewares.RequestID())
+	beego.InsertFilterChain("/api/*", middlewares.BodyDumpWithConfig(middlewares.NewAccessLogMiddleware(app.zapLog, app.appVersion).Logger()))
+	// a client ip is limited before it is authenticated, a customer and an api key after
+	if app.ipRateLimit.Limit > 0 {
+		beego.InsertFilterChain("/api/*", middlewares.RateLimitWithConfig(app.ipRateLimit))
+	}
+	// admin and partner endpoints, the admin api key is granted every permission and a registered api key
+	// the permissions of its scopes and roles, the routes check the permissions they require
+	apiKeyConfig := middlewares.ApiKeyConfig{
+		ApiKey:        app.adminApiKey,
+		Scopes:        []string{middlewares.PermissionAll},
+		Authenticator: app.apiKeyUcase.Authenticate,
+	}
+	apiKey := middlewares.ApiKeyWithConfig(apiKeyConfig)
+	apiKeyPatterns := []string{
+		"/api/v1/admin/*",
+		// partner integrations
+		"/api/v1/customers",
+		"/api/v1/customers/:id",
+		"/api/v1/customers/:id/purchase-transactions",
+		"/api/v1/customers/:id/purchase-transactions/*",
+	}
+	for _, pattern := range apiKeyPatterns {
+		beego.InsertFilterChain(pattern, apiKey)
+	}
+	// campaigns are listed to everyone and changed by the admin
+	apiKeyConfig.Skipper = middlewares.MethodSkipper(http.MethodGet)
+	campaignApiKey := middlewares.ApiKeyWithConfig(apiKeyConfig)
+	beego.InsertFilterChain("/api/v1/campaigns", campaignApiKey)
+	beego.InsertFilterChain("/api/v1/campaigns/:id", campaignApiKey)
+	if app.apiKeyRateLimit.Limit > 0 {
+		apiKeyRateLimit := middlewares.RateLimitWithConfig(app.apiKeyRateLimit)
+		for _, pattern := range append(apiKeyPatterns, "/api/v1/campaigns", "/api/v1/campaigns/:id") {
+			beego.InsertFilterChain(pattern, apiKeyRateLimit)
+		}
+	}
+	// retries of the POST requests with the same Idempotency-Key and api key are replayed
+	apiKeyIdempotencyConfig := app.idempotencyConfig
+	apiKeyIdempotencyConfig.Client = middlewares.RateLimitKeyByApiKey("X-API-KEY")
+	// the issued api keys are only returned once and never stored
+	apiKeyIdempotencyConfig.Skipper = func(ctx *beegoContext.Context) bool {
+		return strings.HasPrefix(ctx.Request.URL.Path, "/api/v1/admin/api-keys")
+	}
+	apiKeyIdempotency := middlewares.IdempotencyWithConfig(apiKeyIdempotencyConfig)
+	for _, pattern := range append(apiKeyPatterns, "/api/v1/campaigns") {
+		beego.InsertFilterChain(pattern, apiKeyIdempotency)
+	}
+	// customer endpoints, the bearer token subject must be the customer id of the path
+	customerJwt := middlewares.JwtWithConfig(app.jwtConfig)
+	customerWritePatterns := []string{
+		"/api/v1/verify-photo/:id",
+		"/api/v1/link-voucher/:id",
+		"/api/v1/campaigns/:campaignId/verify-photo/:id",
+		"/api/v1/campaigns/:campaignId/link-voucher/:id",
+	}
+	for _, pattern := range customerWritePatterns {
+		beego.InsertFilterChain(pattern, customerJwt)
+	}
+	// support and audit staff read the eligibility and the bookings of any customer with an api key
+	// granted customers:read or reviews:read instead of a token
+	customerReadJwtConfig := app.jwtConfig
+	customerReadJwtConfig.Skipper = middlewares.ApiKeySkipper(apiKeyConfig, domain.PermissionCustomersRead, domain.PermissionReviewsRead)
+	customerReadJwt := middlewares.JwtWithConfig(customerReadJwtConfig)
+	customerReadPatterns := []string{
+		"/api/v1/customers/:id/eligibility",
+		"/api/v1/customers/:id/bookings",
+	}
+	for _, pattern := range customerReadPatterns {
+		beego.InsertFilterChain(pattern, customerReadJwt)
+	}
+	customerPatterns := append(customerWritePatterns, customerReadPatterns...)
+	if app.customerRateLimit.Limit > 0 {
+		customerRateLimit := middlewares.RateLimitWithConfig(app.customerRateLimit)
+		for _, pattern := range customerPatterns {
+			beego.InsertFilterChain(pattern, customerRateLimit)
+		}
+	}
+	// retries of the POST requests with the same Idempotency-Key and customer are replayed
+	customerIdempotencyConfig := app.idempotencyConfig
+	customerIdempotencyConfig.Client = middlewares.RateLimitKeyByCustomer(":id")
+	customerIdempotency := middlewares.IdempotencyWithConfig(customerIdempotencyConfig)
+	for _, pattern := range customerPatterns {
+		beego.InsertFilterChain(pattern, customerIdempotency)
+	}
+
+	// health check
+	beego.Get("/health", func(ctx *beegoContext.Context) {
+		ctx.Output.SetStatus(http.StatusOK)
+		ctx.Output.JSON(beego.M{"status": "alive"}, beego.BConfig.RunMode != "prod", false)
+	})
+
+	// default error handler
+	beego.ErrorController(&response.ErrorController{})
+
+	// init handler
+	customerHandler.NewCustomerHandler(app.customerUcase, app.defaultCampaignId, app.zapLog)
+	campaignHandler.NewCampaignHandler(app.campaignUcase, app.zapLog)
+	customerVoucherBookHandler.NewCustomerVoucherBookHandler(app.customerVoucherBookUcase, app.zapLog)
+	purchaseTransactionHandler.NewPurchaseTransactionHandler(app.purchaseTransactionUcase, app.zapLog)
+	currencyRateHandler.NewCurrencyRateHandler(app.currencyRateUcase, app.zapLog)
+	apiKeyHandler.NewApiKeyHandler(app.apiKeyUcase, app.zapLog)
+	roleHandler.NewRoleHandler(app.roleUcase, app.zapLog)
+
+	// background jobs
+	jobScheduler := scheduler.NewScheduler(app.zapLog)
+	jobScheduler.Register(scheduler.Job{
+		Name:     "release-expired-booking",
+		Interval: time.Duration(app.releaseExpiredBookingInterval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := app.customerVoucherBookUcase.ReleaseExpiredBookings(ctx, app.releaseExpiredBookingBatch)
+			return err
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "delete-expired-idempotency-key",
+		Interval: time.Duration(app.deleteExpiredIdempotencyKeyInterval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := app.idempotencyKeyRepo.DeleteExpired(ctx, time.Now(), app.deleteExpiredIdempotencyKeyBatch)
+			return err
+		},
+	})
+	jobScheduler.Start()
+
+	// graceful shutdown, beego.Run returns once the http server is closed
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.shutdownTimeout)*time.Second)
+		defer cancel()
+		if err := beego.BeeApp.Server.Shutdown(ctx); err != nil {
+			app.zapLog.Errorf("shutdown http server: %v", err)
+		}
+	}()
+
+	beego.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.shutdownTimeout)*time.Second)
+	defer cancel()
+	if err := jobScheduler.Stop(ctx); err != nil {
+		app.zapLog.Errorf("stop jobs: %v", err)
+		return 1
+	}
+	return 0
+}
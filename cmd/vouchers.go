@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	customerVoucherUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher/usecase"
+)
+
+// generateVouchers store random voucher codes as available vouchers of the campaign
+func generateVouchers(args []string) int {
+	flags := newFlagSet("vouchers generate")
+	campaignId := flags.Int("campaign", 0, "campaign id of the vouchers")
+	count := flags.Int("count", 0, "number of vouchers")
+	length := flags.Int("length", 10, fmt.Sprintf("length of the voucher codes, at least %d", customerVoucherUsecase.MinVoucherCodeLength))
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *campaignId < 1 || *count < 1 {
+		return usageError("vouchers generate")
+	}
+	app := newApplication()
+
+	created, err := app.customerVoucherUcase.GenerateVouchers(context.Background(), *campaignId, *count, *length)
+	fmt.Printf("campaign %d: %d vouchers generated\n", *campaignId, created)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// importVouchers store the voucher codes of the csv file as available vouchers of the campaign,
+// the exit code is non zero when a row of the file is not stored
+func importVouchers(args []string) int {
+	flags := newFlagSet("vouchers import")
+	campaignId := flags.Int("campaign", 0, "campaign id of the vouchers")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 || *campaignId < 1 {
+		return usageError("vouchers import")
+	}
+	app := newApplication()
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	result, err := app.customerVoucherUcase.ImportVouchers(context.Background(), *campaignId, file)
+	if result != nil {
+		for _, message := range result.Errors {
+			fmt.Fprintln(os.Stderr, message)
+		}
+		fmt.Printf("campaign %d: %d imported, %d duplicate, %d failed\n", *campaignId, result.Created, result.Duplicates, len(result.Errors))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
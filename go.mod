@@ -5,6 +5,7 @@ go 1.16
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/Unknwon/goconfig v1.0.0 // indirect
+	github.com/alicebob/miniredis/v2 v2.31.0
 	github.com/avast/retry-go v3.0.0+incompatible
 	github.com/beego/beego/v2 v2.0.4
 	github.com/beego/i18n v0.0.0-20161101132742-e9308947f407
@@ -28,12 +29,14 @@ require (
 	github.com/swaggo/swag v1.8.3
 	go.mongodb.org/mongo-driver v1.9.1
 	go.uber.org/zap v1.21.0
+	golang.org/x/image v0.12.0
 	google.golang.org/grpc v1.47.0
 	google.golang.org/protobuf v1.28.0
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 	gorm.io/driver/mysql v1.3.4
 	gorm.io/driver/postgres v1.3.7
+	gorm.io/driver/sqlite v1.3.6
 	gorm.io/driver/sqlserver v1.3.2
 	gorm.io/gorm v1.23.7
 )
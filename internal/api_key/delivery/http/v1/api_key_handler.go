@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type ApiKeyHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	ApiKeyUsecase domain.ApiKeyUseCase
+}
+
+func NewApiKeyHandler(apiKeyUsecase domain.ApiKeyUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &ApiKeyHandler{
+		ZapLogger:     zapLogger,
+		ApiKeyUsecase: apiKeyUsecase,
+	}
+	beego.Router("/api/v1/admin/api-keys", pHandler, "get:GetApiKeys;post:CreateApiKey")
+	beego.Router("/api/v1/admin/api-keys/:id/rotate", pHandler, "post:RotateApiKey")
+	beego.Router("/api/v1/admin/api-keys/:id/revoke", pHandler, "post:RevokeApiKey")
+	beego.InsertFilterChain("/api/v1/admin/api-keys", middlewares.RequirePermission(domain.PermissionApiKeysRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/admin/api-keys", middlewares.RequirePermission(domain.PermissionApiKeysWrite, http.MethodPost))
+	beego.InsertFilterChain("/api/v1/admin/api-keys/:id/rotate", middlewares.RequirePermission(domain.PermissionApiKeysWrite))
+	beego.InsertFilterChain("/api/v1/admin/api-keys/:id/revoke", middlewares.RequirePermission(domain.PermissionApiKeysWrite))
+}
+
+func (h *ApiKeyHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetApiKeys
+// @Title GetApiKeys
+// @Tags Admin
+// @Summary GetApiKeys
+// @Description api keys of the partner integrations without the keys themselves, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.ApiKeyListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/admin/api-keys [get]
+func (h *ApiKeyHandler) GetApiKeys() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.ApiKeyUsecase.GetApiKeys(h.Ctx, page, limit)
+	if err != nil {
+		h.responseApiKeyError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreateApiKey
+// @Title CreateApiKey
+// @Tags Admin
+// @Summary CreateApiKey
+// @Description issue an api key to a partner, the key is only returned in this response. Requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.ApiKeySecretResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    body body domain.ApiKeyRequest true "request payload"
+// @Router /v1/admin/api-keys [post]
+func (h *ApiKeyHandler) CreateApiKey() {
+	var request domain.ApiKeyRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.ApiKeyUsecase.CreateApiKey(h.Ctx, request)
+	if err != nil {
+		h.responseApiKeyError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// RotateApiKey
+// @Title RotateApiKey
+// @Tags Admin
+// @Summary RotateApiKey
+// @Description replace the key of an api key, the previous key stops working at once. Requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.ApiKeySecretResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id api key"
+// @Router /v1/admin/api-keys/{id}/rotate [post]
+func (h *ApiKeyHandler) RotateApiKey() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.ApiKeyUsecase.RotateApiKey(h.Ctx, pathParam)
+	if err != nil {
+		h.responseApiKeyError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// RevokeApiKey
+// @Title RevokeApiKey
+// @Tags Admin
+// @Summary RevokeApiKey
+// @Description requests with a revoked key are rejected, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id api key"
+// @Router /v1/admin/api-keys/{id}/revoke [post]
+func (h *ApiKeyHandler) RevokeApiKey() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.ApiKeyUsecase.RevokeApiKey(h.Ctx, pathParam); err != nil {
+		h.responseApiKeyError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *ApiKeyHandler) responseApiKeyError(err error) {
+	if errors.Is(err, response.ErrApiKeyRevoked) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiKeyRevoked, response.ErrorCodeText(response.ApiKeyRevoked, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrInvalidApiKeyExpiresAt) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.InvalidApiKeyExpiresAt, response.ErrorCodeText(response.InvalidApiKeyExpiresAt, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrUnknownPermission) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.UnknownPermission, response.ErrorCodeText(response.UnknownPermission, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyPrefix prefix of every issued key, tells the key apart from other secrets
+	apiKeyPrefix = "ak_"
+	// apiKeyRandomBytes random bytes of a key, hex encoded after the prefix
+	apiKeyRandomBytes = 24
+	// apiKeyDisplayLength characters of the key kept to identify it
+	apiKeyDisplayLength = 11
+	// apiKeyLastUsedInterval the last used date of a key is written at most once per interval
+	apiKeyLastUsedInterval = time.Minute
+)
+
+type apiKeyUseCase struct {
+	zapLogger             zaplogger.Logger
+	contextTimeout        time.Duration
+	mysqlApiKeyRepository domain.MysqlApiKeyRepository
+	mysqlRoleRepository   domain.MysqlRoleRepository
+}
+
+func NewApiKeyUseCase(timeout time.Duration,
+	mysqlApiKeyRepository domain.MysqlApiKeyRepository,
+	mysqlRoleRepository domain.MysqlRoleRepository,
+	zapLogger zaplogger.Logger) domain.ApiKeyUseCase {
+	return &apiKeyUseCase{
+		mysqlApiKeyRepository: mysqlApiKeyRepository,
+		mysqlRoleRepository:   mysqlRoleRepository,
+		contextTimeout:        timeout,
+		zapLogger:             zapLogger,
+	}
+}
+
+// QUERY API KEY
+func (r apiKeyUseCase) singleApiKeyWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.ApiKey, error) {
+	var entity domain.ApiKey
+	if err := r.mysqlApiKeyRepository.SingleWithFilter(
+		ctx,
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&entity, args...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r apiKeyUseCase) fetchApiKeyWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.ApiKey, error) {
+
+	if apiKey, err := r.mysqlApiKeyRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"id DESC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.ApiKey{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := apiKey.(*[]domain.ApiKey); !ok {
+			return []domain.ApiKey{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// newApiKey random key with its display prefix and hash
+func newApiKey() (key, prefix, hash string, err error) {
+	random := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + hex.EncodeToString(random)
+	return key, key[:apiKeyDisplayLength], hashApiKey(key), nil
+}
+
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r apiKeyUseCase) GetApiKeys(beegoCtx *beegoContext.Context, page, limit int) (*domain.ApiKeyListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlApiKeyRepository.CountFilter(c, []string{}, &domain.ApiKey{}, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	apiKeys, err := r.fetchApiKeyWithFilter(c, limit, (page-1)*limit, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]domain.ApiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		result = append(result, domain.NewApiKeyResponse(apiKey, now))
+	}
+
+	return &domain.ApiKeyListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+// CreateApiKey issue a key to the owner, the key is only returned in the response
+func (r apiKeyUseCase) CreateApiKey(beegoCtx *beegoContext.Context, request domain.ApiKeyRequest) (*domain.ApiKeySecretResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var expiresAt *time.Time
+	if request.ExpiresAt != "" {
+		value, err := time.ParseInLocation(helper.DateTimeFormatDefault, request.ExpiresAt, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		if !value.After(now) {
+			return nil, response.ErrInvalidApiKeyExpiresAt
+		}
+		expiresAt = &value
+	}
+
+	scopes := make([]string, 0, len(request.Scopes))
+	for _, scope := range request.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !domain.IsPermission(scope) {
+			return nil, fmt.Errorf("%w: %s", response.ErrUnknownPermission, scope)
+		}
+		if !helper.ItemExists(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key, prefix, hash, err := newApiKey()
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	apiKey, err := r.mysqlApiKeyRepository.Store(c, domain.ApiKey{
+		Owner:     request.Owner,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    strings.Join(scopes, domain.ApiKeyScopeSeparator),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	return &domain.ApiKeySecretResponse{
+		ApiKeyResponse: domain.NewApiKeyResponse(apiKey, now),
+		Key:            key,
+	}, nil
+}
+
+// RotateApiKey replace the key keeping its owner, scopes and expiry, the previous key stops working at once
+func (r apiKeyUseCase) RotateApiKey(beegoCtx *beegoContext.Context, id int) (*domain.ApiKeySecretResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	apiKey, err := r.singleApiKeyWithFilter(c, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, response.ErrApiKeyRevoked
+	}
+
+	key, prefix, hash, err := newApiKey()
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := r.mysqlApiKeyRepository.UpdateSelectedField(c,
+		[]string{"prefix", "key_hash", "last_used_at", "updated_at"},
+		map[string]interface{}{
+			"prefix":       prefix,
+			"key_hash":     hash,
+			"last_used_at": nil,
+			"updated_at":   now,
+		}, apiKey.ID); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = hash
+	apiKey.LastUsedAt = nil
+
+	return &domain.ApiKeySecretResponse{
+		ApiKeyResponse: domain.NewApiKeyResponse(*apiKey, now),
+		Key:            key,
+	}, nil
+}
+
+func (r apiKeyUseCase) RevokeApiKey(beegoCtx *beegoContext.Context, id int) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	apiKey, err := r.singleApiKeyWithFilter(c, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	if apiKey.RevokedAt != nil {
+		return response.ErrApiKeyRevoked
+	}
+
+	now := time.Now()
+	if err := r.mysqlApiKeyRepository.UpdateSelectedField(c,
+		[]string{"revoked_at", "updated_at"},
+		map[string]interface{}{
+			"revoked_at": now,
+			"updated_at": now,
+		}, apiKey.ID); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	return nil
+}
+
+// Authenticate scopes of the key with the permissions of its roles, ErrApiKeyNotRegistered when no key has the hash of the key and
+// ErrApiKeyRevoked or ErrApiKeyExpired when the key is no longer active
+func (r apiKeyUseCase) Authenticate(ctx context.Context, key string) ([]string, error) {
+	c, cancel := context.WithTimeout(ctx, r.contextTimeout)
+	defer cancel()
+
+	apiKey, err := r.singleApiKeyWithFilter(c, []string{"key_hash = ?"}, hashApiKey(key))
+	if err == gorm.ErrRecordNotFound {
+		return nil, response.ErrApiKeyNotRegistered
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch apiKey.Status(now) {
+	case domain.ApiKeyStatusRevoked:
+		return nil, response.ErrApiKeyRevoked
+	case domain.ApiKeyStatusExpired:
+		return nil, response.ErrApiKeyExpired
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		// a failed usage update does not reject the request
+		if err := r.mysqlApiKeyRepository.UpdateSelectedField(c, []string{"last_used_at"}, map[string]interface{}{"last_used_at": now}, apiKey.ID); err != nil {
+			r.zapLogger.Errorf("api key %d: %v", apiKey.ID, err)
+		}
+	}
+
+	permissions, err := r.mysqlRoleRepository.FetchApiKeyPermissions(c, apiKey.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := apiKey.ScopeList()
+	for _, permission := range permissions {
+		if !helper.ItemExists(result, permission) {
+			result = append(result, permission)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,271 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	apiKeyRepository "github.com/radyatamaa/technical-test-aichat/internal/api_key/repository"
+	apiKeyUsecase "github.com/radyatamaa/technical-test-aichat/internal/api_key/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	roleRepository "github.com/radyatamaa/technical-test-aichat/internal/role/repository"
+	roleUsecase "github.com/radyatamaa/technical-test-aichat/internal/role/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"gorm.io/gorm"
+)
+
+func newApiKeyUseCase(t *testing.T, db *gorm.DB) domain.ApiKeyUseCase {
+	t.Helper()
+
+	apiKeyUcase, _ := newUseCases(t, db)
+	return apiKeyUcase
+}
+
+// newUseCases api key and role usecases on the database
+func newUseCases(t *testing.T, db *gorm.DB) (domain.ApiKeyUseCase, domain.RoleUseCase) {
+	t.Helper()
+
+	zapLog := testutil.NewLogger(t)
+	apiKeyRepo := apiKeyRepository.NewMysqlApiKeyRepository(db, zapLog)
+	roleRepo := roleRepository.NewMysqlRoleRepository(db, zapLog)
+	return apiKeyUsecase.NewApiKeyUseCase(30*time.Second, apiKeyRepo, roleRepo, zapLog),
+		roleUsecase.NewRoleUseCase(30*time.Second, roleRepo, apiKeyRepo, zapLog)
+}
+
+// createApiKey issues a key with the scopes to the owner
+func createApiKey(t *testing.T, apiKeyUcase domain.ApiKeyUseCase, owner string, scopes ...string) *domain.ApiKeySecretResponse {
+	t.Helper()
+
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/admin/api-keys", nil))
+	apiKey, err := apiKeyUcase.CreateApiKey(ctx, domain.ApiKeyRequest{Owner: owner, Scopes: scopes})
+	if err != nil {
+		t.Fatalf("create api key of %s: %v", owner, err)
+	}
+	return apiKey
+}
+
+func TestCreateApiKeyStoresOnlyTheHash(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	apiKeyUcase := newApiKeyUseCase(t, db)
+
+	apiKey := createApiKey(t, apiKeyUcase, "partner", domain.PermissionCustomersRead)
+
+	var stored domain.ApiKey
+	if err := db.First(&stored, apiKey.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(apiKey.Key))
+	if stored.KeyHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("stored hash = %s, want the sha256 of the key", stored.KeyHash)
+	}
+	var count int64
+	if err := db.Model(&domain.ApiKey{}).Where("key_hash = ? OR prefix = ? OR scopes = ?", apiKey.Key, apiKey.Key, apiKey.Key).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("the key is stored in plain text")
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	apiKeyUcase := newApiKeyUseCase(t, db)
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/admin/api-keys", nil))
+
+	active := createApiKey(t, apiKeyUcase, "active", domain.PermissionCustomersRead, domain.PermissionPurchaseTransactionsWrite)
+	sum := sha256.Sum256([]byte(active.Key))
+	activeHash := hex.EncodeToString(sum[:])
+	revoked := createApiKey(t, apiKeyUcase, "revoked", domain.PermissionCustomersRead)
+	if err := apiKeyUcase.RevokeApiKey(ctx, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	expired := createApiKey(t, apiKeyUcase, "expired", domain.PermissionCustomersRead)
+	if err := db.Model(&domain.ApiKey{}).Where("id = ?", expired.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	rotated := createApiKey(t, apiKeyUcase, "rotated", domain.PermissionCustomersRead)
+	replacement, err := apiKeyUcase.RotateApiKey(ctx, rotated.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		wantScopes []string
+		wantErr    error
+	}{
+		{
+			name:       "active key",
+			key:        active.Key,
+			wantScopes: []string{domain.PermissionCustomersRead, domain.PermissionPurchaseTransactionsWrite},
+		},
+		{
+			name:    "hash of an active key",
+			key:     activeHash,
+			wantErr: response.ErrApiKeyNotRegistered,
+		},
+		{
+			name:    "unknown key",
+			key:     "ak_unknown",
+			wantErr: response.ErrApiKeyNotRegistered,
+		},
+		{
+			name:    "revoked key",
+			key:     revoked.Key,
+			wantErr: response.ErrApiKeyRevoked,
+		},
+		{
+			name:    "expired key",
+			key:     expired.Key,
+			wantErr: response.ErrApiKeyExpired,
+		},
+		{
+			name:    "key replaced by a rotation",
+			key:     rotated.Key,
+			wantErr: response.ErrApiKeyNotRegistered,
+		},
+		{
+			name:       "key issued by a rotation",
+			key:        replacement.Key,
+			wantScopes: []string{domain.PermissionCustomersRead},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, err := apiKeyUcase.Authenticate(context.Background(), tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestAuthenticatePermissionsOfRoles(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	apiKeyUcase, roleUcase := newUseCases(t, db)
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/admin/roles", nil))
+
+	reviewer, err := roleUcase.CreateRole(ctx, domain.RoleRequest{
+		Name:        "reviewer",
+		Permissions: []string{domain.PermissionReviewsRead, domain.PermissionReviewsWrite},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	support, err := roleUcase.CreateRole(ctx, domain.RoleRequest{
+		Name:        "support",
+		Permissions: []string{domain.PermissionCustomersRead, domain.PermissionReviewsRead},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scoped := createApiKey(t, apiKeyUcase, "scoped", domain.PermissionPurchaseTransactionsWrite)
+	withRoles := createApiKey(t, apiKeyUcase, "with roles", domain.PermissionCustomersRead)
+	if err := roleUcase.SetApiKeyRoles(ctx, withRoles.ID, domain.ApiKeyRoleRequest{RoleIDs: []int{reviewer.ID, support.ID}}); err != nil {
+		t.Fatal(err)
+	}
+	removedRoles := createApiKey(t, apiKeyUcase, "removed roles")
+	if err := roleUcase.SetApiKeyRoles(ctx, removedRoles.ID, domain.ApiKeyRoleRequest{RoleIDs: []int{reviewer.ID}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := roleUcase.SetApiKeyRoles(ctx, removedRoles.ID, domain.ApiKeyRoleRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the admin routes check the permission of the key authenticated by the api key middleware
+	chain := func(permission string) func(*beegoContext.Context) {
+		handler := func(ctx *beegoContext.Context) {
+			ctx.Output.SetStatus(http.StatusOK)
+		}
+		handler = middlewares.RequirePermission(permission)(handler)
+		return middlewares.ApiKeyWithConfig(middlewares.ApiKeyConfig{Authenticator: apiKeyUcase.Authenticate})(handler)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		permission string
+		wantStatus int
+	}{
+		{
+			name:       "permission of a scope",
+			key:        scoped.Key,
+			permission: domain.PermissionPurchaseTransactionsWrite,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "scope without the permission",
+			key:        scoped.Key,
+			permission: domain.PermissionReviewsWrite,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "permission of a role",
+			key:        withRoles.Key,
+			permission: domain.PermissionReviewsWrite,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "permission of a scope and a role",
+			key:        withRoles.Key,
+			permission: domain.PermissionCustomersRead,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "roles without the permission",
+			key:        withRoles.Key,
+			permission: domain.PermissionCampaignsWrite,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "permission of a removed role",
+			key:        removedRoles.Key,
+			permission: domain.PermissionReviewsWrite,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reviews/1/approve", nil)
+			request.Header.Set("X-API-KEY", tt.key)
+			ctx := beegoContext.NewContext()
+			ctx.Reset(recorder, request)
+			chain(tt.permission)(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	scopes, err := apiKeyUcase.Authenticate(context.Background(), withRoles.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{domain.PermissionCustomersRead, domain.PermissionReviewsRead, domain.PermissionReviewsWrite}
+	if len(scopes) != len(want) {
+		t.Errorf("scopes = %v, want %v once each", scopes, want)
+	}
+	for _, permission := range want {
+		if !middlewares.HasPermission(scopes, permission) {
+			t.Errorf("scopes = %v, want %s", scopes, permission)
+		}
+	}
+}
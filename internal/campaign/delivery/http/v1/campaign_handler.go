@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type CampaignHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	CampaignUsecase domain.CampaignUseCase
+}
+
+func NewCampaignHandler(campaignUsecase domain.CampaignUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &CampaignHandler{
+		ZapLogger:       zapLogger,
+		CampaignUsecase: campaignUsecase,
+	}
+	beego.Router("/api/v1/campaigns", pHandler, "get:GetCampaigns;post:CreateCampaign")
+	beego.Router("/api/v1/campaigns/:id", pHandler, "get:GetCampaign;put:UpdateCampaign;delete:DeleteCampaign")
+	beego.InsertFilterChain("/api/v1/campaigns", middlewares.RequirePermission(domain.PermissionCampaignsWrite, http.MethodPost))
+	beego.InsertFilterChain("/api/v1/campaigns/:id", middlewares.RequirePermission(domain.PermissionCampaignsWrite, http.MethodPut, http.MethodDelete))
+}
+
+func (h *CampaignHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetCampaigns
+// @Title GetCampaigns
+// @Tags Campaign
+// @Summary GetCampaigns
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CampaignListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/campaigns [get]
+func (h *CampaignHandler) GetCampaigns() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CampaignUsecase.GetCampaigns(h.Ctx, page, limit)
+	if err != nil {
+		h.responseCampaignError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetCampaign
+// @Title GetCampaign
+// @Tags Campaign
+// @Summary GetCampaign
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CampaignResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id campaign"
+// @Router /v1/campaigns/{id} [get]
+func (h *CampaignHandler) GetCampaign() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CampaignUsecase.GetCampaignByID(h.Ctx, pathParam)
+	if err != nil {
+		h.responseCampaignError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreateCampaign
+// @Title CreateCampaign
+// @Tags Campaign
+// @Summary CreateCampaign
+// @Description rules is the eligibility rule set of the campaign, an empty list saves a campaign without rules and the default rules of the config apply when rules is not sent
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "admin api key or api key with the campaigns:write permission"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CampaignResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    body body domain.CampaignRequest true "request payload"
+// @Router /v1/campaigns [post]
+func (h *CampaignHandler) CreateCampaign() {
+	var request domain.CampaignRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CampaignUsecase.CreateCampaign(h.Ctx, request)
+	if err != nil {
+		h.responseCampaignError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// UpdateCampaign
+// @Title UpdateCampaign
+// @Tags Campaign
+// @Summary UpdateCampaign
+// @Description rules replaces the eligibility rule set of the campaign, an empty list leaves the campaign without rules and the current rule set is kept when rules is not sent
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key or api key with the campaigns:write permission"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CampaignResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id campaign"
+// @Param    body body domain.CampaignRequest true "request payload"
+// @Router /v1/campaigns/{id} [put]
+func (h *CampaignHandler) UpdateCampaign() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.CampaignRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CampaignUsecase.UpdateCampaign(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responseCampaignError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// DeleteCampaign
+// @Title DeleteCampaign
+// @Tags Campaign
+// @Summary DeleteCampaign
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key or api key with the campaigns:write permission"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id campaign"
+// @Router /v1/campaigns/{id} [delete]
+func (h *CampaignHandler) DeleteCampaign() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.CampaignUsecase.DeleteCampaign(h.Ctx, pathParam); err != nil {
+		h.responseCampaignError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *CampaignHandler) responseCampaignError(err error) {
+	if errors.Is(err, response.ErrInvalidActiveEndDate) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.InvalidActiveEndDate, response.ErrorCodeText(response.InvalidActiveEndDate, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
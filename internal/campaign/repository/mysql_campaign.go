@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlCampaignRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlCampaignRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlCampaignRepository {
+	return &mysqlCampaignRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlCampaignRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlCampaignRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlCampaignRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlCampaignRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c mysqlCampaignRepository) Update(ctx context.Context, data domain.Campaign) error {
+
+	err := c.db.WithContext(ctx).Updates(&data).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c mysqlCampaignRepository) UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error {
+
+	return c.db.WithContext(ctx).Table(domain.Campaign{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+func (c mysqlCampaignRepository) Store(ctx context.Context, data domain.Campaign) (domain.Campaign, error) {
+
+	err := c.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+func (c mysqlCampaignRepository) Delete(ctx context.Context, id int) (int, error) {
+
+	err := c.db.WithContext(ctx).Exec("delete from "+domain.Campaign{}.TableName()+" where id =?", id).Error
+	if err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (c mysqlCampaignRepository) SoftDelete(ctx context.Context, id int) (int, error) {
+	var data domain.Campaign
+
+	err := c.db.WithContext(ctx).Where("id = ?", id).Delete(&data).Error
+	if err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (c mysqlCampaignRepository) UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error {
+
+	return tx.WithContext(ctx).Table(domain.Campaign{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+func (c mysqlCampaignRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.Campaign) (int, error) {
+
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
+
+// LockWithTx locks the campaign row so concurrent bookings of the same campaign are serialized.
+func (c mysqlCampaignRepository) LockWithTx(ctx context.Context, tx *gorm.DB, id int) (domain.Campaign, error) {
+	var data domain.Campaign
+
+	err := database.LockForUpdate(tx.WithContext(ctx), data.TableName()).Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
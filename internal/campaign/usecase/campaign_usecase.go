@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type campaignUseCase struct {
+	zapLogger                   zaplogger.Logger
+	contextTimeout              time.Duration
+	mysqlCampaignRepository     domain.MysqlCampaignRepository
+	mysqlCampaignRuleRepository domain.MysqlCampaignRuleRepository
+}
+
+func NewCampaignUseCase(timeout time.Duration,
+	mysqlCampaignRepository domain.MysqlCampaignRepository,
+	mysqlCampaignRuleRepository domain.MysqlCampaignRuleRepository,
+	zapLogger zaplogger.Logger) domain.CampaignUseCase {
+	return &campaignUseCase{
+		mysqlCampaignRepository:     mysqlCampaignRepository,
+		mysqlCampaignRuleRepository: mysqlCampaignRuleRepository,
+		contextTimeout:              timeout,
+		zapLogger:                   zapLogger,
+	}
+}
+
+// QUERY CAMPAIGN
+func (r campaignUseCase) singleCampaignWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.Campaign, error) {
+	var entity domain.Campaign
+	if err := r.mysqlCampaignRepository.SingleWithFilter(
+		ctx,
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&entity, args...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r campaignUseCase) fetchCampaignWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.Campaign, error) {
+
+	if campaign, err := r.mysqlCampaignRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"id DESC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.Campaign{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := campaign.(*[]domain.Campaign); !ok {
+			return []domain.Campaign{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// QUERY CAMPAIGN RULE
+func (r campaignUseCase) fetchCampaignRuleWithFilter(ctx context.Context, filter []string, args ...interface{}) ([]domain.CampaignRule, error) {
+
+	if campaignRule, err := r.mysqlCampaignRuleRepository.FetchWithFilter(
+		ctx,
+		100,
+		0,
+		"id ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.CampaignRule{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := campaignRule.(*[]domain.CampaignRule); !ok {
+			return []domain.CampaignRule{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// replaceCampaignRules swap the whole rule set of the campaign, an empty rule set leaves the campaign without rules
+func (r campaignUseCase) replaceCampaignRules(ctx context.Context, tx *gorm.DB, campaignId int, requests []domain.CampaignRuleRequest) ([]domain.CampaignRule, error) {
+	if err := r.mysqlCampaignRuleRepository.DeleteByCampaignWithTx(ctx, tx, campaignId); err != nil {
+		return nil, err
+	}
+
+	rules := make([]domain.CampaignRule, 0, len(requests))
+	for _, request := range requests {
+		rule := domain.CampaignRule{
+			CampaignID: campaignId,
+			RuleType:   request.RuleType,
+			Threshold:  request.Threshold,
+			WindowDays: request.WindowDays,
+			Currency:   request.Currency,
+		}
+		id, err := r.mysqlCampaignRuleRepository.StoreWithTx(ctx, tx, rule)
+		if err != nil {
+			return nil, err
+		}
+		rule.ID = id
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r campaignUseCase) requestToEntity(request domain.CampaignRequest) (domain.Campaign, error) {
+	startDate, err := time.ParseInLocation(helper.DateTimeFormatDefault, request.StartDate, time.Local)
+	if err != nil {
+		return domain.Campaign{}, err
+	}
+	endDate, err := time.ParseInLocation(helper.DateTimeFormatDefault, request.EndDate, time.Local)
+	if err != nil {
+		return domain.Campaign{}, err
+	}
+	if startDate.After(endDate) {
+		return domain.Campaign{}, response.ErrInvalidActiveEndDate
+	}
+	currency := request.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	return domain.Campaign{
+		Name:                      request.Name,
+		StartDate:                 startDate,
+		EndDate:                   endDate,
+		Budget:                    request.Budget,
+		PerCustomerLimit:          request.PerCustomerLimit,
+		PhotoVerificationRequired: request.PhotoVerificationRequired,
+		Currency:                  currency,
+	}, nil
+}
+
+func (r campaignUseCase) GetCampaigns(beegoCtx *beegoContext.Context, page, limit int) (*domain.CampaignListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlCampaignRepository.CountFilter(c, []string{}, &domain.Campaign{}, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	campaigns, err := r.fetchCampaignWithFilter(c, limit, (page-1)*limit, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.CampaignResponse, 0, len(campaigns))
+	for i := range campaigns {
+		result = append(result, domain.NewCampaignResponse(campaigns[i]))
+	}
+
+	return &domain.CampaignListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r campaignUseCase) GetCampaignByID(beegoCtx *beegoContext.Context, id int) (*domain.CampaignResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	campaign, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	rules, err := r.fetchCampaignRuleWithFilter(c, []string{"campaign_id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewCampaignResponse(*campaign, rules...)
+	return &result, nil
+}
+
+func (r campaignUseCase) CreateCampaign(beegoCtx *beegoContext.Context, request domain.CampaignRequest) (*domain.CampaignResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	entity, err := r.requestToEntity(request)
+	if err != nil {
+		return nil, err
+	}
+	// an empty rule set is kept as a campaign without rules, the default rules apply when none was sent
+	entity.RulesConfigured = request.Rules != nil
+
+	var rules []domain.CampaignRule
+	err = r.mysqlCampaignRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		entity.ID, err = r.mysqlCampaignRepository.StoreWithTx(c, tx, entity)
+		if err != nil {
+			return err
+		}
+		rules, err = r.replaceCampaignRules(c, tx, entity.ID, request.Rules)
+		return err
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewCampaignResponse(entity, rules...)
+	return &result, nil
+}
+
+func (r campaignUseCase) UpdateCampaign(beegoCtx *beegoContext.Context, id int, request domain.CampaignRequest) (*domain.CampaignResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	current, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	entity, err := r.requestToEntity(request)
+	if err != nil {
+		return nil, err
+	}
+	entity.RulesConfigured = current.RulesConfigured || request.Rules != nil
+
+	var rules []domain.CampaignRule
+	err = r.mysqlCampaignRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := r.mysqlCampaignRepository.UpdateSelectedFieldWithTx(c, tx,
+			[]string{"name", "start_date", "end_date", "budget", "per_customer_limit", "photo_verification_required", "currency", "rules_configured", "updated_at"},
+			map[string]interface{}{
+				"name":                        entity.Name,
+				"start_date":                  entity.StartDate,
+				"end_date":                    entity.EndDate,
+				"budget":                      entity.Budget,
+				"per_customer_limit":          entity.PerCustomerLimit,
+				"photo_verification_required": entity.PhotoVerificationRequired,
+				"currency":                    entity.Currency,
+				"rules_configured":            entity.RulesConfigured,
+				"updated_at":                  time.Now(),
+			},
+			id)
+		if err != nil {
+			return err
+		}
+
+		// keep the current rule set when the request does not send rules
+		if request.Rules == nil {
+			return nil
+		}
+		rules, err = r.replaceCampaignRules(c, tx, id, request.Rules)
+		return err
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	if request.Rules == nil {
+		if rules, err = r.fetchCampaignRuleWithFilter(c, []string{"campaign_id = ?"}, id); err != nil {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+			return nil, err
+		}
+	}
+
+	entity.ID = id
+	result := domain.NewCampaignResponse(entity, rules...)
+	return &result, nil
+}
+
+func (r campaignUseCase) DeleteCampaign(beegoCtx *beegoContext.Context, id int) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if _, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+
+	if _, err := r.mysqlCampaignRepository.SoftDelete(c, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlCampaignRuleRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlCampaignRuleRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlCampaignRuleRepository {
+	return &mysqlCampaignRuleRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlCampaignRuleRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlCampaignRuleRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlCampaignRuleRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c mysqlCampaignRuleRepository) Update(ctx context.Context, data domain.CampaignRule) error {
+
+	err := c.db.WithContext(ctx).Updates(&data).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c mysqlCampaignRuleRepository) UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error {
+
+	return c.db.WithContext(ctx).Table(domain.CampaignRule{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+func (c mysqlCampaignRuleRepository) Store(ctx context.Context, data domain.CampaignRule) (domain.CampaignRule, error) {
+
+	err := c.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+func (c mysqlCampaignRuleRepository) Delete(ctx context.Context, id int) (int, error) {
+
+	err := c.db.WithContext(ctx).Exec("delete from "+domain.CampaignRule{}.TableName()+" where id =?", id).Error
+	if err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (c mysqlCampaignRuleRepository) SoftDelete(ctx context.Context, id int) (int, error) {
+	var data domain.CampaignRule
+
+	err := c.db.WithContext(ctx).Where("id = ?", id).Delete(&data).Error
+	if err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (c mysqlCampaignRuleRepository) UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error {
+
+	return tx.WithContext(ctx).Table(domain.CampaignRule{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+func (c mysqlCampaignRuleRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.CampaignRule) (int, error) {
+
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
+
+// DeleteByCampaignWithTx removes the whole rule set of a campaign.
+func (c mysqlCampaignRuleRepository) DeleteByCampaignWithTx(ctx context.Context, tx *gorm.DB, campaignId int) error {
+
+	return tx.WithContext(ctx).Where("campaign_id = ?", campaignId).Delete(&domain.CampaignRule{}).Error
+}
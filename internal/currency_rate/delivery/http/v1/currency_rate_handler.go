@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type CurrencyRateHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	CurrencyRateUsecase domain.CurrencyRateUseCase
+}
+
+func NewCurrencyRateHandler(currencyRateUsecase domain.CurrencyRateUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &CurrencyRateHandler{
+		ZapLogger:           zapLogger,
+		CurrencyRateUsecase: currencyRateUsecase,
+	}
+	beego.Router("/api/v1/admin/currency-rates", pHandler, "get:GetCurrencyRates;put:SaveCurrencyRate")
+	beego.Router("/api/v1/admin/currency-rates/:id", pHandler, "delete:DeleteCurrencyRate")
+	beego.InsertFilterChain("/api/v1/admin/currency-rates", middlewares.RequirePermission(domain.PermissionCurrencyRatesRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/admin/currency-rates", middlewares.RequirePermission(domain.PermissionCurrencyRatesWrite, http.MethodPut))
+	beego.InsertFilterChain("/api/v1/admin/currency-rates/:id", middlewares.RequirePermission(domain.PermissionCurrencyRatesWrite))
+}
+
+func (h *CurrencyRateHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetCurrencyRates
+// @Title GetCurrencyRates
+// @Tags Admin
+// @Summary GetCurrencyRates
+// @Description exchange rates used to convert purchases to the campaign currency, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CurrencyRateListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/admin/currency-rates [get]
+func (h *CurrencyRateHandler) GetCurrencyRates() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CurrencyRateUsecase.GetCurrencyRates(h.Ctx, page, limit)
+	if err != nil {
+		h.responseCurrencyRateError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// SaveCurrencyRate
+// @Title SaveCurrencyRate
+// @Tags Admin
+// @Summary SaveCurrencyRate
+// @Description store the exchange rate of a currency pair, the current rate of the pair is replaced. Requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CurrencyRateResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    body body domain.CurrencyRateRequest true "request payload"
+// @Router /v1/admin/currency-rates [put]
+func (h *CurrencyRateHandler) SaveCurrencyRate() {
+	var request domain.CurrencyRateRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CurrencyRateUsecase.SaveCurrencyRate(h.Ctx, request)
+	if err != nil {
+		h.responseCurrencyRateError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// DeleteCurrencyRate
+// @Title DeleteCurrencyRate
+// @Tags Admin
+// @Summary DeleteCurrencyRate
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id currency rate"
+// @Router /v1/admin/currency-rates/{id} [delete]
+func (h *CurrencyRateHandler) DeleteCurrencyRate() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.CurrencyRateUsecase.DeleteCurrencyRate(h.Ctx, pathParam); err != nil {
+		h.responseCurrencyRateError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *CurrencyRateHandler) responseCurrencyRateError(err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
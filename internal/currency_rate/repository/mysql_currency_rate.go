@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mysqlCurrencyRateRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlCurrencyRateRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlCurrencyRateRepository {
+	return &mysqlCurrencyRateRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlCurrencyRateRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlCurrencyRateRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlCurrencyRateRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlCurrencyRateRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Upsert store the rate of the currency pair, the rate of a pair already stored is replaced
+func (c mysqlCurrencyRateRepository) Upsert(ctx context.Context, data domain.CurrencyRate) (domain.CurrencyRate, error) {
+
+	err := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"rate": data.Rate, "updated_at": time.Now()}),
+	}).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+
+	// the id of an updated pair is not returned by every driver
+	var stored domain.CurrencyRate
+	if err := c.db.WithContext(ctx).Where("base_currency = ? AND quote_currency = ?", data.BaseCurrency, data.QuoteCurrency).First(&stored).Error; err != nil {
+		return data, err
+	}
+	return stored, nil
+}
+
+func (c mysqlCurrencyRateRepository) Delete(ctx context.Context, id int) (int, error) {
+
+	err := c.db.WithContext(ctx).Exec("delete from "+domain.CurrencyRate{}.TableName()+" where id =?", id).Error
+	if err != nil {
+		return id, err
+	}
+	return id, nil
+}
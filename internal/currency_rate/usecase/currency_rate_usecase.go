@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type currencyRateUseCase struct {
+	zapLogger                   zaplogger.Logger
+	contextTimeout              time.Duration
+	mysqlCurrencyRateRepository domain.MysqlCurrencyRateRepository
+}
+
+func NewCurrencyRateUseCase(timeout time.Duration,
+	mysqlCurrencyRateRepository domain.MysqlCurrencyRateRepository,
+	zapLogger zaplogger.Logger) domain.CurrencyRateUseCase {
+	return &currencyRateUseCase{
+		mysqlCurrencyRateRepository: mysqlCurrencyRateRepository,
+		contextTimeout:              timeout,
+		zapLogger:                   zapLogger,
+	}
+}
+
+// QUERY CURRENCY RATE
+func (r currencyRateUseCase) singleCurrencyRateWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CurrencyRate, error) {
+	var entity domain.CurrencyRate
+	if err := r.mysqlCurrencyRateRepository.SingleWithFilter(
+		ctx,
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&entity, args...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r currencyRateUseCase) fetchCurrencyRateWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.CurrencyRate, error) {
+
+	if rate, err := r.mysqlCurrencyRateRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"base_currency ASC, quote_currency ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.CurrencyRate{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := rate.(*[]domain.CurrencyRate); !ok {
+			return []domain.CurrencyRate{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// Convert amount from one currency to another with the rate of the pair, or the inverse of the opposite pair,
+// rounded to the minor unit of the currency converted to
+func (r currencyRateUseCase) Convert(ctx context.Context, amount money.Amount, from, to string) (money.Amount, error) {
+	if from == to || amount == 0 {
+		return amount, nil
+	}
+
+	rate, err := r.singleCurrencyRateWithFilter(ctx, []string{"base_currency = ?", "quote_currency = ?"}, from, to)
+	if err == nil {
+		return amount.Convert(rate.Rate).Round(to), nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+
+	rate, err = r.singleCurrencyRateWithFilter(ctx, []string{"base_currency = ?", "quote_currency = ?"}, to, from)
+	if err == nil {
+		return amount.ConvertInverse(rate.Rate).Round(to), nil
+	}
+	if err == gorm.ErrRecordNotFound {
+		return 0, fmt.Errorf("%w: %s to %s", response.ErrCurrencyRateNotFound, from, to)
+	}
+	return 0, err
+}
+
+func (r currencyRateUseCase) GetCurrencyRates(beegoCtx *beegoContext.Context, page, limit int) (*domain.CurrencyRateListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlCurrencyRateRepository.CountFilter(c, []string{}, &domain.CurrencyRate{}, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	rates, err := r.fetchCurrencyRateWithFilter(c, limit, (page-1)*limit, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.CurrencyRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		result = append(result, domain.NewCurrencyRateResponse(rate))
+	}
+
+	return &domain.CurrencyRateListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+// SaveCurrencyRate store the rate of the pair, replacing the current rate of the pair
+func (r currencyRateUseCase) SaveCurrencyRate(beegoCtx *beegoContext.Context, request domain.CurrencyRateRequest) (*domain.CurrencyRateResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	rate, err := r.mysqlCurrencyRateRepository.Upsert(c, domain.CurrencyRate{
+		BaseCurrency:  request.BaseCurrency,
+		QuoteCurrency: request.QuoteCurrency,
+		Rate:          request.Rate,
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewCurrencyRateResponse(rate)
+	return &result, nil
+}
+
+func (r currencyRateUseCase) DeleteCurrencyRate(beegoCtx *beegoContext.Context, id int) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if _, err := r.singleCurrencyRateWithFilter(c, []string{"id = ?"}, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+
+	if _, err := r.mysqlCurrencyRateRepository.Delete(c, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
@@ -9,7 +10,9 @@ import (
 	beego "github.com/beego/beego/v2/server/web"
 	"github.com/radyatamaa/technical-test-aichat/internal"
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
 	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
 )
@@ -18,35 +21,60 @@ type CustomerHandler struct {
 	ZapLogger zaplogger.Logger
 	internal.BaseController
 	response.ApiResponse
-	CustomerUsecase domain.CustomerUseCase
+	CustomerUsecase   domain.CustomerUseCase
+	DefaultCampaignID int
 }
 
-func NewCustomerHandler(customerUsecase domain.CustomerUseCase, zapLogger zaplogger.Logger) {
+func NewCustomerHandler(customerUsecase domain.CustomerUseCase, defaultCampaignId int, zapLogger zaplogger.Logger) {
 	pHandler := &CustomerHandler{
-		ZapLogger:       zapLogger,
-		CustomerUsecase: customerUsecase,
+		ZapLogger:         zapLogger,
+		CustomerUsecase:   customerUsecase,
+		DefaultCampaignID: defaultCampaignId,
 	}
 	beego.Router("/api/v1/verify-photo/:id", pHandler, "post:VerifyPhoto")
 	beego.Router("/api/v1/link-voucher/:id", pHandler, "get:GetLinkVoucher")
+	beego.Router("/api/v1/campaigns/:campaignId/verify-photo/:id", pHandler, "post:VerifyPhoto")
+	beego.Router("/api/v1/campaigns/:campaignId/link-voucher/:id", pHandler, "get:GetLinkVoucher")
+	beego.Router("/api/v1/customers/:id/eligibility", pHandler, "get:GetEligibility")
+	beego.Router("/api/v1/customers", pHandler, "get:GetCustomers;post:CreateCustomer")
+	beego.Router("/api/v1/customers/:id", pHandler, "get:GetCustomer;put:UpdateCustomer;delete:DeleteCustomer")
+	beego.InsertFilterChain("/api/v1/customers", middlewares.RequirePermission(domain.PermissionCustomersRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/customers", middlewares.RequirePermission(domain.PermissionCustomersWrite, http.MethodPost))
+	beego.InsertFilterChain("/api/v1/customers/:id", middlewares.RequirePermission(domain.PermissionCustomersRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/customers/:id", middlewares.RequirePermission(domain.PermissionCustomersWrite, http.MethodPut, http.MethodDelete))
 }
 
 func (h *CustomerHandler) Prepare() {
-	// check user access when needed
+	// verify-photo, link-voucher and eligibility routes are checked by the jwt filter, the others by the partner api key filter
 	h.SetLangVersion()
 }
 
+// campaignIdParam campaign from path parameter, the legacy routes use campaign_id query or the default campaign
+func (h *CustomerHandler) campaignIdParam() (int, error) {
+	if param := h.Ctx.Input.Param(":campaignId"); param != "" {
+		return strconv.Atoi(param)
+	}
+	return h.GetInt("campaign_id", h.DefaultCampaignID)
+}
+
 // VerifyPhoto
 // @Title VerifyPhoto
 // @Tags Customer
 // @Summary VerifyPhoto
+// @Description voucher code when the photo is verified, status pending_review when the photo is inconclusive and waits for a reviewer
 // @Produce json
 // @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param Authorization header string true "bearer token of the customer, Bearer {token}"
 // @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerVerifyPhotoResponse}
-// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=domain.CustomerVerifyPhotoResponse}
 // @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
 // @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
 // @Param        file   formData  file    true  "file"
 // @Param    id path int true "id customer"
+// @Param    campaign_id query int false "id campaign, default campaign when empty"
 // @Router /v1/verify-photo/{id} [post]
 func (h *CustomerHandler) VerifyPhoto() {
 	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
@@ -55,6 +83,11 @@ func (h *CustomerHandler) VerifyPhoto() {
 		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
 		return
 	}
+	campaignId, err := h.campaignIdParam()
+	if err != nil || campaignId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
 	_, fileHeader, err := h.GetFile("file")
 	if err != nil {
 		h.Ctx.Input.SetData("stackTrace", h.ZapLogger.SetMessageLog(err))
@@ -62,33 +95,69 @@ func (h *CustomerHandler) VerifyPhoto() {
 		return
 	}
 
-	result, err := h.CustomerUsecase.VerifyPhotoCustomer(h.Ctx, pathParam, fileHeader)
+	result, err := h.CustomerUsecase.VerifyPhotoCustomer(h.Ctx, campaignId, pathParam, fileHeader)
 	if err != nil {
 		if errors.Is(err, response.ErrCustomerAlreadyGetVoucher) {
-			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CustomerAlreadyGetVoucher, response.ErrorCodeText(response.CustomerAlreadyGetVoucher, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.CustomerAlreadyGetVoucher, response.ErrorCodeText(response.CustomerAlreadyGetVoucher, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrVerificationAttemptsExceeded) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.VerificationAttemptsExceeded, response.ErrorCodeText(response.VerificationAttemptsExceeded, h.Locale.Lang), result, err)
 			return
 		}
 		if errors.Is(err, response.ErrCustomerVerifyImage) {
-			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CustomerVerifyImage, response.ErrorCodeText(response.CustomerVerifyImage, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.CustomerVerifyImage, response.ErrorCodeText(response.CustomerVerifyImage, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImagePayloadTooLarge) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImagePayloadTooLarge, response.ErrorCodeText(response.ImagePayloadTooLarge, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImageMimeTypeNotAllowed) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImageMimeTypeNotAllowed, response.ErrorCodeText(response.ImageMimeTypeNotAllowed, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImageDimensionTooSmall) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImageDimensionTooSmall, response.ErrorCodeText(response.ImageDimensionTooSmall, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImageDimensionTooLarge) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImageDimensionTooLarge, response.ErrorCodeText(response.ImageDimensionTooLarge, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImageCorrupt) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImageCorrupt, response.ErrorCodeText(response.ImageCorrupt, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrDuplicatePhoto) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.DuplicatePhoto, response.ErrorCodeText(response.DuplicatePhoto, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrBookingPendingReview) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.BookingPendingReview, response.ErrorCodeText(response.BookingPendingReview, h.Locale.Lang), result, err)
 			return
 		}
 		if errors.Is(err, response.ErrCustomerNotYetBookVoucher) {
-			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CustomerNotYetBookVoucher, response.ErrorCodeText(response.CustomerNotYetBookVoucher, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.CustomerNotYetBookVoucher, response.ErrorCodeText(response.CustomerNotYetBookVoucher, h.Locale.Lang), result, err)
 			return
 		}
 		if errors.Is(err, response.ErrCustomerBookVoucherExpired) {
-			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CustomerBookVoucherExpired, response.ErrorCodeText(response.CustomerBookVoucherExpired, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.CustomerBookVoucherExpired, response.ErrorCodeText(response.CustomerBookVoucherExpired, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrVoucherNotAvailable) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.VoucherNotAvailable, response.ErrorCodeText(response.VoucherNotAvailable, h.Locale.Lang), result, err)
 			return
 		}
 		if errors.Is(err, context.DeadlineExceeded) {
-			h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), result, err)
 			return
 		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), result, err)
 			return
 		}
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+		h.ResponseErrorWithData(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), result, err)
 		return
 	}
 	h.Ok(h.Ctx, h.Tr("message.success"), result)
@@ -101,10 +170,12 @@ func (h *CustomerHandler) VerifyPhoto() {
 // @Summary GetLinkVoucher
 // @Produce json
 // @Param Accept-Language header string false "lang"
+// @Param Authorization header string true "bearer token of the customer, Bearer {token}"
 // @Success 200 {object} swagger.BaseResponse{data=[]domain.CustomerVoucherBookResponse,errors=[]object}
 // @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
 // @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
 // @Param    id path int true "id customer"
+// @Param    campaign_id query int false "id campaign, default campaign when empty"
 // @router /v1/link-voucher/{id} [get]
 func (h *CustomerHandler) GetLinkVoucher() {
 	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
@@ -113,9 +184,27 @@ func (h *CustomerHandler) GetLinkVoucher() {
 		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
 		return
 	}
+	campaignId, err := h.campaignIdParam()
+	if err != nil || campaignId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
 
-	result, err := h.CustomerUsecase.GetVoucherByCustomerId(h.Ctx, pathParam)
+	result, err := h.CustomerUsecase.GetVoucherByCustomerId(h.Ctx, campaignId, pathParam)
 	if err != nil {
+		var ruleErrors response.RuleErrors
+		if errors.As(err, &ruleErrors) && len(ruleErrors) > 0 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, ruleErrors[0].Code, response.ErrorCodeText(ruleErrors[0].Code, h.Locale.Lang), err)
+			return
+		}
+		if errors.Is(err, response.ErrCampaignNotActive) {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CampaignNotActive, response.ErrorCodeText(response.CampaignNotActive, h.Locale.Lang), err)
+			return
+		}
+		if errors.Is(err, response.ErrCampaignBudgetExhausted) {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CampaignBudgetExhausted, response.ErrorCodeText(response.CampaignBudgetExhausted, h.Locale.Lang), err)
+			return
+		}
 		if errors.Is(err, response.ErrVoucherNotAvailable) {
 			h.ResponseError(h.Ctx, http.StatusBadRequest, response.VoucherNotAvailable, response.ErrorCodeText(response.VoucherNotAvailable, h.Locale.Lang), err)
 			return
@@ -136,6 +225,60 @@ func (h *CustomerHandler) GetLinkVoucher() {
 			h.ResponseError(h.Ctx, http.StatusBadRequest, response.CustomerAlreadyGetVoucher, response.ErrorCodeText(response.CustomerAlreadyGetVoucher, h.Locale.Lang), err)
 			return
 		}
+		if errors.Is(err, response.ErrCurrencyRateNotFound) {
+			h.ResponseError(h.Ctx, http.StatusInternalServerError, response.CurrencyRateNotFound, response.ErrorCodeText(response.CurrencyRateNotFound, h.Locale.Lang), err)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+			return
+		}
+		h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetEligibility
+// @Title GetEligibility
+// @Tags Customer
+// @Summary GetEligibility
+// @Description read only report of the checks done by link-voucher, no voucher is booked
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Authorization header string false "bearer token of the customer, Bearer {token}"
+// @Param X-API-KEY header string false "api key granted customers:read or reviews:read, instead of the bearer token"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerEligibilityResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    campaign_id query int false "id campaign, default campaign when empty"
+// @Router /v1/customers/{id}/eligibility [get]
+func (h *CustomerHandler) GetEligibility() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	campaignId, err := h.campaignIdParam()
+	if err != nil || campaignId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerUsecase.GetEligibilityByCustomerId(h.Ctx, campaignId, pathParam)
+	if err != nil {
+		if errors.Is(err, response.ErrCurrencyRateNotFound) {
+			h.ResponseError(h.Ctx, http.StatusInternalServerError, response.CurrencyRateNotFound, response.ErrorCodeText(response.CurrencyRateNotFound, h.Locale.Lang), err)
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
 			return
@@ -147,6 +290,209 @@ func (h *CustomerHandler) GetLinkVoucher() {
 		h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
 		return
 	}
+
+	for i := range result.Checks {
+		result.Checks[i].Description = h.Tr("eligibility." + result.Checks[i].Check)
+		if !result.Checks[i].Passed {
+			result.Checks[i].Message = response.ErrorCodeText(result.Checks[i].Code, h.Locale.Lang)
+		}
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetCustomers
+// @Title GetCustomers
+// @Tags Customer
+// @Summary GetCustomers
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/customers [get]
+func (h *CustomerHandler) GetCustomers() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerUsecase.GetCustomers(h.Ctx, page, limit)
+	if err != nil {
+		h.responseCustomerError(err)
+		return
+	}
 	h.Ok(h.Ctx, h.Tr("message.success"), result)
 	return
 }
+
+// GetCustomer
+// @Title GetCustomer
+// @Tags Customer
+// @Summary GetCustomer
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Router /v1/customers/{id} [get]
+func (h *CustomerHandler) GetCustomer() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerUsecase.GetCustomerByID(h.Ctx, pathParam)
+	if err != nil {
+		h.responseCustomerError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreateCustomer
+// @Title CreateCustomer
+// @Tags Customer
+// @Summary CreateCustomer
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    body body domain.CustomerRequest true "request payload"
+// @Router /v1/customers [post]
+func (h *CustomerHandler) CreateCustomer() {
+	var request domain.CustomerRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerUsecase.CreateCustomer(h.Ctx, request)
+	if err != nil {
+		h.responseCustomerError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// UpdateCustomer
+// @Title UpdateCustomer
+// @Tags Customer
+// @Summary UpdateCustomer
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    body body domain.CustomerUpdateRequest true "request payload"
+// @Router /v1/customers/{id} [put]
+func (h *CustomerHandler) UpdateCustomer() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.CustomerUpdateRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	request.ID = pathParam
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerUsecase.UpdateCustomer(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responseCustomerError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// DeleteCustomer
+// @Title DeleteCustomer
+// @Tags Customer
+// @Summary DeleteCustomer
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Router /v1/customers/{id} [delete]
+func (h *CustomerHandler) DeleteCustomer() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.CustomerUsecase.DeleteCustomer(h.Ctx, pathParam); err != nil {
+		h.responseCustomerError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *CustomerHandler) responseCustomerError(err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
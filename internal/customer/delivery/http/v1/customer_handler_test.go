@@ -0,0 +1,100 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	beego "github.com/beego/beego/v2/server/web"
+	v1 "github.com/radyatamaa/technical-test-aichat/internal/customer/delivery/http/v1"
+	customerUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+func TestVerifyPhoto(t *testing.T) {
+	tests := []struct {
+		name       string
+		skinRatio  float64
+		wantStatus int
+		wantCode   string
+		wantBook   string
+		wantRedeem bool
+	}{
+		{
+			name:       "face verified",
+			skinRatio:  1,
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusText(http.StatusOK),
+			wantBook:   domain.CustomerVoucherBookStatusVerified,
+			wantRedeem: true,
+		},
+		{
+			name:       "face rejected",
+			skinRatio:  0,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   response.CustomerVerifyImage,
+		},
+		{
+			name:       "face inconclusive",
+			skinRatio:  0.2,
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusText(http.StatusOK),
+			wantBook:   domain.CustomerVoucherBookStatusPendingReview,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.NewSQLiteDB(t)
+			useCases := testutil.NewUseCases(t, db, customerUsecase.PhotoVerificationConfig{
+				MaxAttempts:          3,
+				DuplicateMaxDistance: 5,
+				DuplicateAction:      customerUsecase.DuplicatePhotoReject,
+			})
+			campaign := testutil.CreateCampaign(t, db, 10, 10, true)
+			customerId := testutil.CreateCustomers(t, db, 1)[0]
+			ctx := testutil.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/link-voucher", nil))
+			if _, err := useCases.Customer.GetVoucherByCustomerId(ctx, campaign.ID, customerId); err != nil {
+				t.Fatal(err)
+			}
+
+			// the routes are registered on the global beego application
+			beego.BeeApp.Handlers = beego.NewControllerRegister()
+			v1.NewCustomerHandler(useCases.Customer, campaign.ID, testutil.NewLogger(t))
+			beego.BeeApp.Handlers.Init()
+
+			recorder := httptest.NewRecorder()
+			request := testutil.NewMultipartRequest(t, http.MethodPost,
+				fmt.Sprintf("/api/v1/verify-photo/%d?campaign_id=%d", customerId, campaign.ID),
+				"file", "photo.png", testutil.NewPhoto(t, tt.skinRatio))
+			beego.BeeApp.Handlers.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			var body struct {
+				Code string                             `json:"code"`
+				Data domain.CustomerVerifyPhotoResponse `json:"data"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", body.Code, tt.wantCode)
+			}
+			if body.Data.Attempts != 1 {
+				t.Errorf("attempts = %d, want 1", body.Data.Attempts)
+			}
+			if tt.wantBook != "" && body.Data.Status != tt.wantBook {
+				t.Errorf("booking status = %s, want %s", body.Data.Status, tt.wantBook)
+			}
+			if (body.Data.VoucherCode != "") != tt.wantRedeem {
+				t.Errorf("voucher code = %q, want redeemed %v", body.Data.VoucherCode, tt.wantRedeem)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
 	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
@@ -26,9 +27,32 @@ func (c mysqlCustomerRepository) DB() *gorm.DB {
 	return c.db
 }
 
+func (c mysqlCustomerRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 func (c mysqlCustomerRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
 	p := paginator.NewPaginator(c.db, offset, limit, model)
-	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args).Select(strings.Join(fields, ",")).Error; err != nil {
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
 		return nil, err
 	}
 	return model, nil
@@ -114,3 +138,14 @@ func (c mysqlCustomerRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, d
 	}
 	return data.ID, nil
 }
+
+// LockWithTx locks the customer row so concurrent requests of the same customer are serialized.
+func (c mysqlCustomerRepository) LockWithTx(ctx context.Context, tx *gorm.DB, id int) (domain.Customer, error) {
+	var data domain.Customer
+
+	err := database.LockForUpdate(tx.WithContext(ctx), data.TableName()).Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -2,25 +2,55 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"mime/multipart"
-	"strings"
+	"strconv"
 	"time"
 
 	beegoContext "github.com/beego/beego/v2/server/web/context"
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
 	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagehash"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagevalidator"
 	"github.com/radyatamaa/technical-test-aichat/pkg/response"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
 )
 
 type customerUseCase struct {
-	zapLogger                          zaplogger.Logger
-	contextTimeout                     time.Duration
-	mysqlCustomerRepository            domain.MysqlCustomerRepository
-	mysqlCustomerVoucherRepository     domain.MysqlCustomerVoucherRepository
-	mysqlCustomerVoucherBookRepository domain.MysqlCustomerVoucherBookRepository
-	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository
+	zapLogger                                 zaplogger.Logger
+	contextTimeout                            time.Duration
+	mysqlCustomerRepository                   domain.MysqlCustomerRepository
+	mysqlCustomerVoucherRepository            domain.MysqlCustomerVoucherRepository
+	mysqlCustomerVoucherBookRepository        domain.MysqlCustomerVoucherBookRepository
+	mysqlPurchaseTransactionRepository        domain.MysqlPurchaseTransactionRepository
+	mysqlCampaignRepository                   domain.MysqlCampaignRepository
+	eligibilityEngine                         domain.EligibilityEngine
+	customerVoucherBookUseCase                domain.CustomerVoucherBookUseCase
+	imageValidator                            *imagevalidator.Validator
+	faceVerifier                              domain.FaceVerifier
+	storage                                   domain.Storage
+	mysqlCustomerVoucherBookAttemptRepository domain.MysqlCustomerVoucherBookAttemptRepository
+	photoVerificationConfig                   PhotoVerificationConfig
+}
+
+// duplicate photo actions
+const (
+	DuplicatePhotoReject = "reject"
+	DuplicatePhotoFlag   = "flag"
+)
+
+// PhotoVerificationConfig limits of the photo verification, a photo within DuplicateMaxDistance bits of the
+// photo hash of another customer is rejected or flagged by DuplicateAction
+type PhotoVerificationConfig struct {
+	MaxAttempts          int
+	DuplicateMaxDistance int
+	DuplicateAction      string
 }
 
 func NewCustomerUseCase(timeout time.Duration,
@@ -28,14 +58,30 @@ func NewCustomerUseCase(timeout time.Duration,
 	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository,
 	mysqlCustomerVoucherBookRepository domain.MysqlCustomerVoucherBookRepository,
 	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository,
+	mysqlCampaignRepository domain.MysqlCampaignRepository,
+	eligibilityEngine domain.EligibilityEngine,
+	customerVoucherBookUseCase domain.CustomerVoucherBookUseCase,
+	imageValidator *imagevalidator.Validator,
+	faceVerifier domain.FaceVerifier,
+	storage domain.Storage,
+	mysqlCustomerVoucherBookAttemptRepository domain.MysqlCustomerVoucherBookAttemptRepository,
+	photoVerificationConfig PhotoVerificationConfig,
 	zapLogger zaplogger.Logger) domain.CustomerUseCase {
 	return &customerUseCase{
-		mysqlCustomerRepository:            mysqlCustomerRepository,
-		mysqlCustomerVoucherRepository:     mysqlCustomerVoucherRepository,
-		mysqlPurchaseTransactionRepository: mysqlPurchaseTransactionRepository,
-		contextTimeout:                     timeout,
-		zapLogger:                          zapLogger,
-		mysqlCustomerVoucherBookRepository: mysqlCustomerVoucherBookRepository,
+		mysqlCustomerRepository:                   mysqlCustomerRepository,
+		mysqlCustomerVoucherRepository:            mysqlCustomerVoucherRepository,
+		mysqlPurchaseTransactionRepository:        mysqlPurchaseTransactionRepository,
+		contextTimeout:                            timeout,
+		zapLogger:                                 zapLogger,
+		mysqlCustomerVoucherBookRepository:        mysqlCustomerVoucherBookRepository,
+		mysqlCampaignRepository:                   mysqlCampaignRepository,
+		eligibilityEngine:                         eligibilityEngine,
+		customerVoucherBookUseCase:                customerVoucherBookUseCase,
+		imageValidator:                            imageValidator,
+		faceVerifier:                              faceVerifier,
+		storage:                                   storage,
+		mysqlCustomerVoucherBookAttemptRepository: mysqlCustomerVoucherBookAttemptRepository,
+		photoVerificationConfig:                   photoVerificationConfig,
 	}
 }
 
@@ -55,10 +101,10 @@ func (r customerUseCase) singleCustomerWithFilter(ctx context.Context, filter []
 	return &entity, nil
 }
 
-// QUERY CUSTOMER VOUCHER BOOK
-func (r customerUseCase) singleCustomerVoucherBookWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CustomerVoucherBook, error) {
-	var entity domain.CustomerVoucherBook
-	if err := r.mysqlCustomerVoucherBookRepository.SingleWithFilter(
+// QUERY CAMPAIGN
+func (r customerUseCase) singleCampaignWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.Campaign, error) {
+	var entity domain.Campaign
+	if err := r.mysqlCampaignRepository.SingleWithFilter(
 		ctx,
 		[]string{
 			"*",
@@ -71,33 +117,33 @@ func (r customerUseCase) singleCustomerVoucherBookWithFilter(ctx context.Context
 	return &entity, nil
 }
 
-// QUERY CUSTOMER VOUCHER
-func (r customerUseCase) fetchCustomerVoucherWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.CustomerVoucher, error) {
+// QUERY CUSTOMER VOUCHER BOOK
+func (r customerUseCase) latestCustomerVoucherBookWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CustomerVoucherBook, error) {
 
-	if purchaseTransaction, err := r.mysqlCustomerVoucherRepository.FetchWithFilter(
+	if voucherBook, err := r.mysqlCustomerVoucherBookRepository.FetchWithFilter(
 		ctx,
-		limit,
-		offset,
-		"RAND()",
+		1,
+		0,
+		"id DESC",
 		[]string{
 			"*",
 		},
 		[]string{},
 		filter,
-		&[]domain.CustomerVoucher{}, args); err != nil {
+		&[]domain.CustomerVoucherBook{}, args...); err != nil {
 		return nil, err
 	} else {
-		if result, ok := purchaseTransaction.(*[]domain.CustomerVoucher); !ok {
-			return []domain.CustomerVoucher{}, nil
+		if result, ok := voucherBook.(*[]domain.CustomerVoucherBook); !ok || len(*result) == 0 {
+			return nil, gorm.ErrRecordNotFound
 		} else {
-			return *result, nil
+			return &(*result)[0], nil
 		}
 	}
 }
 
-func (r customerUseCase) singleCustomerVoucherWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CustomerVoucher, error) {
-	var entity domain.CustomerVoucher
-	if err := r.mysqlCustomerVoucherRepository.SingleWithFilter(
+func (r customerUseCase) singleCustomerVoucherBookWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CustomerVoucherBook, error) {
+	var entity domain.CustomerVoucherBook
+	if err := r.mysqlCustomerVoucherBookRepository.SingleWithFilter(
 		ctx,
 		[]string{
 			"*",
@@ -110,37 +156,25 @@ func (r customerUseCase) singleCustomerVoucherWithFilter(ctx context.Context, fi
 	return &entity, nil
 }
 
-// QUERY PURCHASE TRANSACTION
-func (r customerUseCase) summaryPurchaseTransactionWithFilter(ctx context.Context, filter []string, args ...interface{}) ([]domain.PurchaseTransaction, error) {
-
-	if purchaseTransaction, err := r.mysqlPurchaseTransactionRepository.FetchWithFilter(
+// QUERY CUSTOMER VOUCHER BOOK ATTEMPT
+func (r customerUseCase) countCustomerVoucherBookAttemptWithFilter(ctx context.Context, filter []string, args ...interface{}) (int, error) {
+	var entity domain.CustomerVoucherBookAttempt
+	result, err := r.mysqlCustomerVoucherBookAttemptRepository.CountFilter(
 		ctx,
-		1,
-		0,
-		"transaction_at DESC",
-		[]string{
-			"SUM(total_spent) AS total_spent",
-			"SUM(total_saving) AS total_saving",
-		},
 		[]string{},
+		&entity,
 		filter,
-		&[]domain.PurchaseTransaction{}, args); err != nil {
-		//beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
-		//r.zapLogger.SetMessageLog2(err)
-		return nil, err
-	} else {
-		if result, ok := purchaseTransaction.(*[]domain.PurchaseTransaction); !ok {
-			return []domain.PurchaseTransaction{}, nil
-		} else {
-			return *result, nil
-		}
+		args...)
+	if err != nil {
+		return 0, err
 	}
+	return result, nil
 }
 
-func (r customerUseCase) countPurchaseTransactionWithFilter(ctx context.Context, filter []string, args ...interface{}) (int, error) {
-	var entity domain.PurchaseTransaction
-	var result int
-	result, err := r.mysqlPurchaseTransactionRepository.CountFilter(
+// QUERY CUSTOMER VOUCHER
+func (r customerUseCase) countCustomerVoucherWithFilter(ctx context.Context, filter []string, args ...interface{}) (int, error) {
+	var entity domain.CustomerVoucher
+	result, err := r.mysqlCustomerVoucherRepository.CountFilter(
 		ctx,
 		[]string{},
 		&entity,
@@ -152,24 +186,443 @@ func (r customerUseCase) countPurchaseTransactionWithFilter(ctx context.Context,
 	return result, nil
 }
 
-func (r customerUseCase) VerifyPhotoCustomer(beegoCtx *beegoContext.Context, customerId int, file *multipart.FileHeader) (*domain.CustomerVerifyPhotoResponse, error) {
+func (r customerUseCase) singleCustomerVoucherWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.CustomerVoucher, error) {
+	var entity domain.CustomerVoucher
+	if err := r.mysqlCustomerVoucherRepository.SingleWithFilter(
+		ctx,
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&entity, args...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// evaluateEligibility runs every check done before a voucher is booked without side effects:
+// campaign period, vouchers already received, the campaign rule set and an active booking.
+func (r customerUseCase) evaluateEligibility(ctx context.Context, customer domain.Customer, campaign domain.Campaign, now time.Time) ([]domain.EligibilityResult, error) {
+	results := make([]domain.EligibilityResult, 0)
+
+	campaignActive := campaign.IsActive(now)
+	results = append(results, newCheckResult(domain.EligibilityCheckCampaignActive, campaignActive, boolToFloat(campaignActive), 1, response.CampaignNotActive))
+
+	redeemed, err := r.countCustomerVoucherWithFilter(ctx,
+		[]string{"customer_id = ?", "campaign_id = ?", "is_redeem = ?"},
+		customer.ID,
+		campaign.ID,
+		true)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, newCheckResult(domain.EligibilityCheckExistingVoucher, redeemed < campaign.PerCustomerLimit, float64(redeemed), float64(campaign.PerCustomerLimit), response.CustomerAlreadyGetVoucher))
+
+	ruleResults, err := r.eligibilityEngine.Evaluate(ctx, domain.EligibilityInput{
+		Customer: customer,
+		Campaign: campaign,
+		Now:      now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, ruleResults...)
+
+	activeBooking := true
+	if _, err := r.singleCustomerVoucherBookWithFilter(ctx,
+		[]string{
+			"customer_id = ?",
+			"campaign_id = ?",
+			"status = ?",
+			"expired_date > ?"},
+		customer.ID,
+		campaign.ID,
+		domain.CustomerVoucherBookStatusBooked,
+		now); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		activeBooking = false
+	}
+	if !activeBooking {
+		// a booking waiting for review stays active until the reviewer decides
+		if _, err := r.singleCustomerVoucherBookWithFilter(ctx,
+			[]string{
+				"customer_id = ?",
+				"campaign_id = ?",
+				"status = ?"},
+			customer.ID,
+			campaign.ID,
+			domain.CustomerVoucherBookStatusPendingReview); err == nil {
+			activeBooking = true
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+	results = append(results, newCheckResult(domain.EligibilityCheckActiveBooking, !activeBooking, boolToFloat(activeBooking), 0, response.CustomerAlreadyBookVoucher))
+
+	return results, nil
+}
+
+// eligibilityError first failed check as its sentinel error, failed campaign rules are returned together in response.RuleErrors
+func eligibilityError(results []domain.EligibilityResult) error {
+	var ruleErrors response.RuleErrors
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		switch result.Rule {
+		case domain.EligibilityCheckCampaignActive:
+			return response.ErrCampaignNotActive
+		case domain.EligibilityCheckExistingVoucher:
+			return response.ErrCustomerAlreadyGetVoucher
+		case domain.EligibilityCheckActiveBooking:
+			if len(ruleErrors) == 0 {
+				return response.ErrCustomerAlreadyBookVoucher
+			}
+		default:
+			ruleErrors = append(ruleErrors, response.RuleError{Rule: result.Rule, Code: result.ErrorCode})
+		}
+	}
+	if len(ruleErrors) > 0 {
+		return ruleErrors
+	}
+	return nil
+}
+
+// photoCheck outcome of checkPhoto
+type photoCheck struct {
+	hash           string
+	path           string
+	perceptualHash int64
+	flagReason     string
+}
+
+// checkPhoto validate, store and verify the photo of the booking, a rejected photo is returned as response error
+// with the hash of the uploaded file so the attempt can be recorded, the booking is only updated with the outcome of the attempt
+func (r customerUseCase) checkPhoto(ctx context.Context, customerId int, file *multipart.FileHeader) (photoCheck, error) {
+	var check photoCheck
+
+	// VALIDATE IMAGE CONTENT
+	photo, err := r.imageValidator.ValidateFile(file)
+	if err != nil {
+		hash, hashErr := fileHash(file)
+		if hashErr != nil {
+			return check, hashErr
+		}
+		check.hash = hash
+		return check, imageValidationError(err)
+	}
+
+	// STORE PHOTO AS EVIDENCE OF THE VERIFICATION
+	hash, path, err := r.storePhoto(ctx, customerId, photo)
+	if err != nil {
+		return check, err
+	}
+	check.hash = hash
+	check.path = path
+
+	// VERIFY FACE ON PHOTO
+	verification, err := r.faceVerifier.Verify(ctx, domain.FaceVerificationInput{
+		CustomerID: customerId,
+		MimeType:   photo.MimeType,
+		Data:       photo.Data,
+		Image:      photo.Decoded,
+	})
+	if err != nil {
+		return check, err
+	}
+	if !verification.Verified && !verification.Inconclusive {
+		return check, response.ErrCustomerVerifyImage
+	}
+
+	// DUPLICATE PHOTO OF ANOTHER CUSTOMER
+	check.perceptualHash = int64(imagehash.DHash(photo.Decoded))
+	similar, err := r.mysqlCustomerVoucherBookRepository.FetchSimilarPhotoHash(ctx, customerId, check.perceptualHash, r.photoVerificationConfig.DuplicateMaxDistance, 1)
+	if err != nil {
+		return check, err
+	}
+	if len(similar) > 0 {
+		if r.photoVerificationConfig.DuplicateAction != DuplicatePhotoFlag {
+			return check, response.ErrDuplicatePhoto
+		}
+		check.flagReason = fmt.Sprintf("photo similar to booking %d of customer %d", similar[0].ID, similar[0].CustomerID)
+	}
+
+	// INCONCLUSIVE PHOTO IS LEFT TO A REVIEWER
+	if verification.Inconclusive {
+		return check, response.ErrFaceVerificationInconclusive
+	}
+	return check, nil
+}
+
+// updatePhotoCheckWithTx keep the photo, perceptual hash and duplicate flag of an accepted photo on the booking,
+// the path of a rejected photo is only kept on its attempt
+func (r customerUseCase) updatePhotoCheckWithTx(ctx context.Context, tx *gorm.DB, bookId int, check photoCheck) error {
+	fields := []string{"photo_hash", "photo_path", "photo_phash", "flagged", "flag_reason"}
+	values := map[string]interface{}{
+		"photo_hash":  check.hash,
+		"photo_path":  check.path,
+		"photo_phash": check.perceptualHash,
+		"flagged":     check.flagReason != "",
+		"flag_reason": check.flagReason,
+	}
+	// bytes of the hash searched by the duplicate check
+	for i, band := range imagehash.Split(uint64(check.perceptualHash)) {
+		fields = append(fields, domain.PhotoPerceptualHashBandColumns[i])
+		values[domain.PhotoPerceptualHashBandColumns[i]] = band
+	}
+	return r.mysqlCustomerVoucherBookRepository.UpdateSelectedFieldWithTx(ctx, tx, fields, values, bookId)
+}
+
+// storePhoto save the photo addressed by its content hash, the same photo uploaded twice is stored once
+func (r customerUseCase) storePhoto(ctx context.Context, customerId int, photo *imagevalidator.Image) (string, string, error) {
+	hash := sha256Hex(photo.Data)
+
+	extension := ".jpg"
+	switch photo.MimeType {
+	case imagevalidator.MimeTypePNG:
+		extension = ".png"
+	case imagevalidator.MimeTypeWebP:
+		extension = ".webp"
+	}
+
+	path := fmt.Sprintf("verification-photos/%d/%s%s", customerId, hash, extension)
+	if err := r.storage.Put(ctx, path, photo.Data, photo.MimeType); err != nil {
+		return "", "", err
+	}
+	return hash, path, nil
+}
+
+func fileHash(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// imageValidationError map the image validator error to its response error
+func imageValidationError(err error) error {
+	switch {
+	case errors.Is(err, imagevalidator.ErrPayloadTooLarge):
+		return response.ErrImagePayloadTooLarge
+	case errors.Is(err, imagevalidator.ErrMimeTypeNotAllowed):
+		return response.ErrImageMimeTypeNotAllowed
+	case errors.Is(err, imagevalidator.ErrDimensionTooSmall):
+		return response.ErrImageDimensionTooSmall
+	case errors.Is(err, imagevalidator.ErrDimensionTooLarge):
+		return response.ErrImageDimensionTooLarge
+	case errors.Is(err, imagevalidator.ErrCorrupt):
+		return response.ErrImageCorrupt
+	default:
+		return err
+	}
+}
+
+// photoRejectionCode error code of a rejected photo, false for any other error
+func photoRejectionCode(err error) (string, bool) {
+	switch {
+	case err == nil:
+		return "", false
+	case errors.Is(err, response.ErrImagePayloadTooLarge):
+		return response.ImagePayloadTooLarge, true
+	case errors.Is(err, response.ErrImageMimeTypeNotAllowed):
+		return response.ImageMimeTypeNotAllowed, true
+	case errors.Is(err, response.ErrImageDimensionTooSmall):
+		return response.ImageDimensionTooSmall, true
+	case errors.Is(err, response.ErrImageDimensionTooLarge):
+		return response.ImageDimensionTooLarge, true
+	case errors.Is(err, response.ErrImageCorrupt):
+		return response.ImageCorrupt, true
+	case errors.Is(err, response.ErrFaceVerificationInconclusive):
+		return response.FaceVerificationInconclusive, true
+	case errors.Is(err, response.ErrCustomerVerifyImage):
+		return response.CustomerVerifyImage, true
+	case errors.Is(err, response.ErrDuplicatePhoto):
+		return response.DuplicatePhoto, true
+	default:
+		return "", false
+	}
+}
+
+func newCheckResult(check string, passed bool, value, threshold float64, errorCode string) domain.EligibilityResult {
+	result := domain.EligibilityResult{
+		Rule:      check,
+		Passed:    passed,
+		Value:     value,
+		Threshold: threshold,
+	}
+	if !passed {
+		result.ErrorCode = errorCode
+	}
+	return result
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// bookVoucher claims one available voucher of the campaign for the customer inside a single transaction.
+// The customer and campaign rows are locked first so parallel requests are serialized for the
+// per-customer and budget checks, then a voucher is reserved with a conditional update so it can
+// never be booked twice. Campaigns without photo verification redeem the voucher right away.
+func (r customerUseCase) bookVoucher(ctx context.Context, campaignId, customerId int, expiredDate time.Time) (*domain.CustomerVoucherBook, *domain.CustomerVoucher, error) {
+	var book domain.CustomerVoucherBook
+	var voucher domain.CustomerVoucher
+
+	err := r.mysqlCustomerVoucherRepository.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := r.mysqlCustomerRepository.LockWithTx(ctx, tx, customerId); err != nil {
+			return err
+		}
+		campaign, err := r.mysqlCampaignRepository.LockWithTx(ctx, tx, campaignId)
+		if err != nil {
+			return err
+		}
+
+		var activeBook domain.CustomerVoucherBook
+		err = r.mysqlCustomerVoucherBookRepository.SingleWithFilterWithTx(ctx, tx,
+			[]string{"*"},
+			[]string{},
+			[]string{"customer_id = ?", "campaign_id = ?", "status = ?", "expired_date > ?"},
+			&activeBook,
+			customerId,
+			campaignId,
+			domain.CustomerVoucherBookStatusBooked,
+			time.Now())
+		if err == nil {
+			return response.ErrCustomerAlreadyBookVoucher
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		err = r.mysqlCustomerVoucherBookRepository.SingleWithFilterWithTx(ctx, tx,
+			[]string{"*"},
+			[]string{},
+			[]string{"customer_id = ?", "campaign_id = ?", "status = ?"},
+			&activeBook,
+			customerId,
+			campaignId,
+			domain.CustomerVoucherBookStatusPendingReview)
+		if err == nil {
+			return response.ErrCustomerAlreadyBookVoucher
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		redeemed, err := r.mysqlCustomerVoucherRepository.CountFilterWithTx(ctx, tx,
+			[]string{},
+			&domain.CustomerVoucher{},
+			[]string{"customer_id = ?", "campaign_id = ?", "is_redeem = ?"},
+			customerId,
+			campaignId,
+			true)
+		if err != nil {
+			return err
+		}
+		if redeemed >= campaign.PerCustomerLimit {
+			return response.ErrCustomerAlreadyGetVoucher
+		}
+
+		used, err := r.mysqlCustomerVoucherRepository.CountFilterWithTx(ctx, tx,
+			[]string{},
+			&domain.CustomerVoucher{},
+			[]string{"campaign_id = ?", "(is_redeem = @redeemed OR reserved_until > @now OR id IN (SELECT customer_voucher_id FROM customer_voucher_books WHERE status = @pendingReview))"},
+			campaignId,
+			map[string]interface{}{"redeemed": true, "now": time.Now(), "pendingReview": domain.CustomerVoucherBookStatusPendingReview})
+		if err != nil {
+			return err
+		}
+		if used >= campaign.Budget {
+			return response.ErrCampaignBudgetExhausted
+		}
+
+		voucher, err = r.mysqlCustomerVoucherRepository.ClaimAvailableWithTx(ctx, tx, campaignId, time.Now(), expiredDate)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return response.ErrVoucherNotAvailable
+			}
+			return err
+		}
+
+		book, err = r.customerVoucherBookUseCase.CreateWithTx(ctx, tx, domain.CustomerVoucherBook{
+			CustomerID:        customerId,
+			CustomerVoucherID: voucher.ID,
+			CampaignID:        campaignId,
+			ExpiredDate:       expiredDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !campaign.PhotoVerificationRequired {
+			voucher.CustomerID = &customerId
+			voucher.IsRedeem = true
+			if err := r.mysqlCustomerVoucherRepository.UpdateSelectedFieldWithTx(ctx, tx,
+				[]string{"customer_id", "is_redeem"},
+				map[string]interface{}{
+					"customer_id": customerId,
+					"is_redeem":   true,
+				},
+				voucher.ID); err != nil {
+				return err
+			}
+			return r.customerVoucherBookUseCase.TransitionWithTx(ctx, tx, &book, domain.CustomerVoucherBookStatusVerified, "photo verification not required")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &book, &voucher, nil
+}
+
+func (r customerUseCase) VerifyPhotoCustomer(beegoCtx *beegoContext.Context, campaignId, customerId int, file *multipart.FileHeader) (*domain.CustomerVerifyPhotoResponse, error) {
 	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
 	defer cancel()
 
-	first, err := r.singleCustomerVoucherWithFilter(c, []string{"customer_id"}, customerId)
-	if err != nil && err != gorm.ErrRecordNotFound{
+	campaign, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, campaignId)
+	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	if first != nil{
+	redeemed, err := r.countCustomerVoucherWithFilter(c,
+		[]string{"customer_id = ?", "campaign_id = ?", "is_redeem = ?"},
+		customerId,
+		campaign.ID,
+		true)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	if redeemed >= campaign.PerCustomerLimit {
 		return nil, response.ErrCustomerAlreadyGetVoucher
 	}
 
-	voucherBookCheckCustomer, err := r.singleCustomerVoucherBookWithFilter(c,
+	voucherBookCheckCustomer, err := r.latestCustomerVoucherBookWithFilter(c,
 		[]string{
-			"customer_id"},
-		customerId)
+			"customer_id = ?",
+			"campaign_id = ?"},
+		customerId,
+		campaign.ID)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
@@ -179,41 +632,164 @@ func (r customerUseCase) VerifyPhotoCustomer(beegoCtx *beegoContext.Context, cus
 		return nil, response.ErrCustomerNotYetBookVoucher
 	}
 
-	if time.Now().After(voucherBookCheckCustomer.ExpiredDate) {
+	if voucherBookCheckCustomer.Status == domain.CustomerVoucherBookStatusExpired ||
+		(voucherBookCheckCustomer.Status == domain.CustomerVoucherBookStatusReleased && time.Now().After(voucherBookCheckCustomer.ExpiredDate)) {
 		return nil, response.ErrCustomerBookVoucherExpired
 	}
+	if voucherBookCheckCustomer.Status == domain.CustomerVoucherBookStatusPendingReview {
+		return nil, response.ErrBookingPendingReview
+	}
+	if voucherBookCheckCustomer.Status != domain.CustomerVoucherBookStatusBooked &&
+		voucherBookCheckCustomer.Status != domain.CustomerVoucherBookStatusLocked {
+		return nil, response.ErrCustomerNotYetBookVoucher
+	}
 
-	//VALIDATE IMAGE BY SIZE
-	sizeKb := float64(file.Size / 1024)
-	if !strings.Contains(file.Filename, "face") || (sizeKb < 50) {
-		return nil, response.ErrCustomerVerifyImage
+	// VALIDATION VERIFICATION ATTEMPTS, COUNTED AGAIN UNDER THE BOOKING LOCK WHEN THE ATTEMPT IS RECORDED
+	attempts, err := r.countCustomerVoucherBookAttemptWithFilter(c, []string{"customer_voucher_book_id = ?"}, voucherBookCheckCustomer.ID)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
 	}
 
+	if voucherBookCheckCustomer.Status == domain.CustomerVoucherBookStatusLocked || attempts >= r.photoVerificationConfig.MaxAttempts {
+		return domain.NewCustomerVerifyPhotoResponse(attempts, r.photoVerificationConfig.MaxAttempts), response.ErrVerificationAttemptsExceeded
+	}
 
+	if time.Now().After(voucherBookCheckCustomer.ExpiredDate) {
+		err = r.mysqlCustomerVoucherBookRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+			return r.customerVoucherBookUseCase.TransitionWithTx(c, tx, voucherBookCheckCustomer, domain.CustomerVoucherBookStatusExpired, "photo verification timeout")
+		})
+		if err != nil && !errors.Is(err, response.ErrInvalidBookStatusTransition) {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+			return nil, err
+		}
+		return nil, response.ErrCustomerBookVoucherExpired
+	}
 
-	err = r.mysqlCustomerVoucherRepository.UpdateSelectedField(c,
-		[]string{"customer_id", "is_redeem"},
-		map[string]interface{}{
-			"customer_id": customerId,
-			"is_redeem":   true,
-		},
-		voucherBookCheckCustomer.CustomerVoucherID,
-	)
+	bookedVoucher, err := r.singleCustomerVoucherWithFilter(c, []string{"id = ?"}, voucherBookCheckCustomer.CustomerVoucherID)
 	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	first, err = r.singleCustomerVoucherWithFilter(c, []string{"id"}, voucherBookCheckCustomer.CustomerVoucherID)
+	check, checkErr := r.checkPhoto(c, customerId, file)
+	reasonCode, rejected := photoRejectionCode(checkErr)
+	if checkErr != nil && !rejected {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(checkErr))
+		return nil, checkErr
+	}
+
+	attempt := domain.CustomerVoucherBookAttempt{
+		CustomerVoucherBookID: voucherBookCheckCustomer.ID,
+		CustomerID:            customerId,
+		Outcome:               domain.VerificationAttemptVerified,
+		ReasonCode:            reasonCode,
+		PhotoHash:             check.hash,
+		PhotoPath:             check.path,
+	}
+	switch {
+	case errors.Is(checkErr, response.ErrFaceVerificationInconclusive):
+		attempt.Outcome = domain.VerificationAttemptInconclusive
+	case rejected:
+		attempt.Outcome = domain.VerificationAttemptRejected
+	}
+
+	// RECORD THE ATTEMPT WITH ITS OUTCOME, THE BOOKING IS LOCKED SO CONCURRENT ATTEMPTS ARE COUNTED ONE AFTER ANOTHER
+	var result *domain.CustomerVerifyPhotoResponse
+	err = r.mysqlCustomerVoucherBookRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		book, err := r.mysqlCustomerVoucherBookRepository.LockWithTx(c, tx, voucherBookCheckCustomer.ID)
+		if err != nil {
+			return err
+		}
+		attempts, err := r.mysqlCustomerVoucherBookAttemptRepository.CountFilterWithTx(c, tx,
+			[]string{},
+			&domain.CustomerVoucherBookAttempt{},
+			[]string{"customer_voucher_book_id = ?"},
+			book.ID)
+		if err != nil {
+			return err
+		}
+
+		switch book.Status {
+		case domain.CustomerVoucherBookStatusBooked:
+			// THE VOUCHER OF AN EXPIRED BOOKING MAY BE CLAIMED BY ANOTHER CUSTOMER ALREADY
+			if time.Now().After(book.ExpiredDate) {
+				return response.ErrCustomerBookVoucherExpired
+			}
+		case domain.CustomerVoucherBookStatusLocked:
+			result = domain.NewCustomerVerifyPhotoResponse(attempts, r.photoVerificationConfig.MaxAttempts)
+			return response.ErrVerificationAttemptsExceeded
+		case domain.CustomerVoucherBookStatusPendingReview:
+			return response.ErrBookingPendingReview
+		case domain.CustomerVoucherBookStatusExpired, domain.CustomerVoucherBookStatusReleased:
+			return response.ErrCustomerBookVoucherExpired
+		default:
+			return response.ErrCustomerNotYetBookVoucher
+		}
+		if attempts >= r.photoVerificationConfig.MaxAttempts {
+			result = domain.NewCustomerVerifyPhotoResponse(attempts, r.photoVerificationConfig.MaxAttempts)
+			return response.ErrVerificationAttemptsExceeded
+		}
+
+		attempts++
+		result = domain.NewCustomerVerifyPhotoResponse(attempts, r.photoVerificationConfig.MaxAttempts)
+		if _, err := r.mysqlCustomerVoucherBookAttemptRepository.StoreWithTx(c, tx, attempt); err != nil {
+			return err
+		}
+
+		switch attempt.Outcome {
+		// INCONCLUSIVE PHOTO, THE BOOKING WAITS FOR A REVIEWER
+		case domain.VerificationAttemptInconclusive:
+			if err := r.customerVoucherBookUseCase.TransitionWithTx(c, tx, &book, domain.CustomerVoucherBookStatusPendingReview, "photo verification inconclusive"); err != nil {
+				return err
+			}
+			if err := r.updatePhotoCheckWithTx(c, tx, book.ID, check); err != nil {
+				return err
+			}
+			result.Status = book.Status
+		// REJECTED PHOTO, LOCK THE BOOKING ON THE LAST ATTEMPT
+		case domain.VerificationAttemptRejected:
+			if attempts >= r.photoVerificationConfig.MaxAttempts {
+				if err := r.customerVoucherBookUseCase.TransitionWithTx(c, tx, &book, domain.CustomerVoucherBookStatusLocked, "maximum photo verification attempts reached"); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := r.updatePhotoCheckWithTx(c, tx, book.ID, check); err != nil {
+				return err
+			}
+			if err := r.customerVoucherBookUseCase.RedeemWithTx(c, tx, &book, "photo verified"); err != nil {
+				return err
+			}
+			result.VoucherCode = bookedVoucher.VoucherCode
+			result.Status = book.Status
+		}
+		return nil
+	})
 	if err != nil {
+		if errors.Is(err, response.ErrVerificationAttemptsExceeded) {
+			return result, err
+		}
+		if errors.Is(err, response.ErrBookingPendingReview) ||
+			errors.Is(err, response.ErrCustomerBookVoucherExpired) ||
+			errors.Is(err, response.ErrCustomerNotYetBookVoucher) ||
+			errors.Is(err, response.ErrVoucherNotAvailable) {
+			return nil, err
+		}
+		if errors.Is(err, response.ErrInvalidBookStatusTransition) {
+			return nil, response.ErrCustomerNotYetBookVoucher
+		}
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
-	return &domain.CustomerVerifyPhotoResponse{VoucherCode: first.VoucherCode}, nil
 
+	if attempt.Outcome == domain.VerificationAttemptRejected {
+		return result, checkErr
+	}
+	return result, nil
 }
 
-func (r customerUseCase) GetVoucherByCustomerId(beegoCtx *beegoContext.Context, customerId int) (*domain.CustomerVoucherBookResponse, error) {
+func (r customerUseCase) GetVoucherByCustomerId(beegoCtx *beegoContext.Context, campaignId, customerId int) (*domain.CustomerVoucherBookResponse, error) {
 	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
 	defer cancel()
 
@@ -223,117 +799,251 @@ func (r customerUseCase) GetVoucherByCustomerId(beegoCtx *beegoContext.Context,
 		return nil, err
 	}
 
-	// VALIDATION CUSTOMER ALREADY GET VOUCHER
-	firstCV, err := r.singleCustomerVoucherWithFilter(c, []string{"customer_id =?"}, customerId)
-	if err != nil && err != gorm.ErrRecordNotFound {
+	campaign, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, campaignId)
+	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	if firstCV != nil {
-		return nil, response.ErrCustomerAlreadyGetVoucher
+	results, err := r.evaluateEligibility(c, *first, *campaign, time.Now())
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	if err := eligibilityError(results); err != nil {
+		return nil, err
 	}
 
-	// VALIDATION MIN 3 COMPLETE TRANSACTION
-	startDate := time.Now()
-	endDate := startDate.AddDate(0, 0, 30)
+	expiredDate := time.Now().Add(time.Minute * 10)
 
-	countPurchaseTransaction, err := r.countPurchaseTransactionWithFilter(c,
-		[]string{"customer_id", "DATE(transaction_at) >= DATE(?)", "DATE(transaction_at) <= DATE(?)"},
-		first.ID,
-		startDate,
-		endDate)
+	_, voucher, err := r.bookVoucher(c, campaign.ID, first.ID, expiredDate)
 	if err != nil {
-		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		if !errors.Is(err, response.ErrVoucherNotAvailable) &&
+			!errors.Is(err, response.ErrCustomerAlreadyBookVoucher) &&
+			!errors.Is(err, response.ErrCustomerAlreadyGetVoucher) &&
+			!errors.Is(err, response.ErrCampaignBudgetExhausted) {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
 		return nil, err
 	}
 
-	if countPurchaseTransaction < 3 {
-		return nil, response.ErrTransactionCompletePurchase30Days
+	result := &domain.CustomerVoucherBookResponse{Expired: expiredDate.Format(helper.DateTimeFormatDefault)}
+	if voucher.IsRedeem {
+		result.VoucherCode = voucher.VoucherCode
 	}
+	return result, nil
+}
+
+func (r customerUseCase) GetEligibilityByCustomerId(beegoCtx *beegoContext.Context, campaignId, customerId int) (*domain.CustomerEligibilityResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
 
-	// VALIDATION TRANSACTION MIN 100$
-	summary, err := r.summaryPurchaseTransactionWithFilter(c, []string{"customer_id = ?"}, first.ID)
+	customer, err := r.singleCustomerWithFilter(c, []string{"id =?"}, customerId)
 	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	if len(summary) == 0 {
-		return nil, response.ErrTransactionMinimum
+	campaign, err := r.singleCampaignWithFilter(c, []string{"id = ?"}, campaignId)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
 	}
 
-	if summary[0].TotalSpent < 100 {
-		return nil, response.ErrTransactionMinimum
+	results, err := r.evaluateEligibility(c, *customer, *campaign, time.Now())
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
 	}
 
-	// VALIDATION ALREADY BOOK VOUCHER
-	voucherBookCheckCustomer, err := r.singleCustomerVoucherBookWithFilter(c,
+	return domain.NewCustomerEligibilityResponse(customer.ID, campaign.ID, results), nil
+}
+
+func (r customerUseCase) fetchCustomerWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.Customer, error) {
+
+	if customer, err := r.mysqlCustomerRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"id DESC",
 		[]string{
-			"customer_id",
-			"expired_date > ?"},
-		customerId,
-		time.Now())
-	if err != nil && err != gorm.ErrRecordNotFound {
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.Customer{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := customer.(*[]domain.Customer); !ok {
+			return []domain.Customer{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+func (r customerUseCase) GetCustomers(beegoCtx *beegoContext.Context, page, limit int) (*domain.CustomerListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlCustomerRepository.CountFilter(c, []string{}, &domain.Customer{}, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	customers, err := r.fetchCustomerWithFilter(c, limit, (page-1)*limit, []string{})
+	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	if voucherBookCheckCustomer != nil {
-		return nil, response.ErrCustomerAlreadyBookVoucher
+	result := make([]domain.CustomerResponse, 0, len(customers))
+	for i := range customers {
+		result = append(result, domain.NewCustomerResponse(customers[i]))
 	}
 
-	fetchCV, err := r.fetchCustomerVoucherWithFilter(c, 1000, 0, []string{"is_redeem = ?"}, false)
+	return &domain.CustomerListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r customerUseCase) GetCustomerByID(beegoCtx *beegoContext.Context, id int) (*domain.CustomerResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	customer, err := r.singleCustomerWithFilter(c, []string{"id = ?"}, id)
 	if err != nil {
 		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
 		return nil, err
 	}
 
-	customerVoucherId := 0
-	expiredDate := time.Now().Add(time.Minute * 10)
+	result := domain.NewCustomerResponse(*customer)
+	return &result, nil
+}
 
-	for i := range fetchCV {
-		voucherBook, err := r.singleCustomerVoucherBookWithFilter(c, []string{"customer_voucher_id = ?"}, fetchCV[i].ID)
-		if err != nil && err != gorm.ErrRecordNotFound {
-			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
-			return nil, err
-		}
+func (r customerUseCase) CreateCustomer(beegoCtx *beegoContext.Context, request domain.CustomerRequest) (*domain.CustomerResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
 
-		if voucherBook != nil {
-			if time.Now().After(voucherBook.ExpiredDate) {
-				customerVoucherId = fetchCV[i].ID
-				_, err = r.mysqlCustomerVoucherBookRepository.Store(c, domain.CustomerVoucherBook{
-					CustomerID:        first.ID,
-					CustomerVoucherID: fetchCV[i].ID,
-					ExpiredDate:       expiredDate,
-				})
-				if err != nil {
-					beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
-					return nil, err
-				}
-			} else {
-				continue
-			}
+	customer, err := r.mysqlCustomerRepository.Store(c, domain.Customer{
+		FirstName:     request.FirstName,
+		LastName:      request.LastName,
+		Gender:        request.Gender,
+		DateOfBirth:   request.DateOfBirth,
+		ContactNumber: request.ContactNumber,
+		Email:         request.Email,
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
 
-		} else {
-			customerVoucherId = fetchCV[i].ID
-			_, err = r.mysqlCustomerVoucherBookRepository.Store(c, domain.CustomerVoucherBook{
-				CustomerID:        first.ID,
-				CustomerVoucherID: fetchCV[i].ID,
-				ExpiredDate:       expiredDate,
-			})
-			if err != nil {
-				beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
-				return nil, err
-			}
-		}
+	result := domain.NewCustomerResponse(customer)
+	return &result, nil
+}
+
+func (r customerUseCase) UpdateCustomer(beegoCtx *beegoContext.Context, id int, request domain.CustomerUpdateRequest) (*domain.CustomerResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	customer, err := r.singleCustomerWithFilter(c, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	customer.FirstName = request.FirstName
+	customer.LastName = request.LastName
+	customer.Gender = request.Gender
+	customer.DateOfBirth = request.DateOfBirth
+	customer.ContactNumber = request.ContactNumber
+	customer.Email = request.Email
+	customer.UpdatedAt = time.Now()
+
+	err = r.mysqlCustomerRepository.UpdateSelectedField(c,
+		[]string{"first_name", "last_name", "gender", "date_of_birth", "contact_number", "email", "updated_at"},
+		map[string]interface{}{
+			"first_name":     customer.FirstName,
+			"last_name":      customer.LastName,
+			"gender":         customer.Gender,
+			"date_of_birth":  customer.DateOfBirth,
+			"contact_number": customer.ContactNumber,
+			"email":          customer.Email,
+			"updated_at":     customer.UpdatedAt,
+		},
+		id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewCustomerResponse(*customer)
+	return &result, nil
+}
+
+func (r customerUseCase) DeleteCustomer(beegoCtx *beegoContext.Context, id int) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
 
-		break
+	if _, err := r.singleCustomerWithFilter(c, []string{"id = ?"}, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+
+	if _, err := r.mysqlCustomerRepository.SoftDelete(c, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
 	}
+	return nil
+}
+
+// customerExportBatch customers read per query of the export
+const customerExportBatch = 500
 
-	if customerVoucherId == 0 {
-		return nil, response.ErrVoucherNotAvailable
+// ExportCustomers write the customers as csv with a header row, newest first, returns the number of customers written
+func (r customerUseCase) ExportCustomers(ctx context.Context, writer io.Writer) (int, error) {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "contact_number", "email", "created_at", "updated_at"}); err != nil {
+		return 0, err
 	}
 
-	return &domain.CustomerVoucherBookResponse{Expired: expiredDate.Format(helper.DateTimeFormatDefault)}, nil
+	total := 0
+	filter := []string{}
+	args := []interface{}{}
+	for {
+		customers, err := r.fetchCustomerWithFilter(ctx, customerExportBatch, 0, filter, args...)
+		if err != nil {
+			return total, err
+		}
+		for i := range customers {
+			customer := domain.NewCustomerResponse(customers[i])
+			if err := csvWriter.Write([]string{
+				strconv.Itoa(customer.ID),
+				customer.FirstName,
+				customer.LastName,
+				customer.Gender,
+				customer.DateOfBirth,
+				customer.ContactNumber,
+				customer.Email,
+				customer.CreatedAt,
+				customer.UpdatedAt,
+			}); err != nil {
+				return total, err
+			}
+			total++
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return total, err
+		}
+
+		if len(customers) < customerExportBatch {
+			return total, nil
+		}
+		// next page after the last customer, customers are fetched by id descending
+		filter = []string{"id < ?"}
+		args = []interface{}{customers[len(customers)-1].ID}
+	}
 }
@@ -0,0 +1,18 @@
+//go:build integration
+
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+)
+
+// TestGetVoucherByCustomerIdConcurrentIntegration parallel link voucher requests on mysql, postgres or sql server,
+// their transactions run concurrently and only the row locks and the skipped locked vouchers keep a voucher or the
+// budget from being handed out twice
+//
+//	TEST_DATABASE_DRIVER=postgres TEST_DATABASE_DSN="host=localhost user=test dbname=test" go test -tags integration ./internal/customer/usecase/
+func TestGetVoucherByCustomerIdConcurrentIntegration(t *testing.T) {
+	testGetVoucherByCustomerIdConcurrent(t, testutil.NewIntegrationDB)
+}
@@ -0,0 +1,373 @@
+package usecase_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	customerUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"gorm.io/gorm"
+)
+
+var defaultPhotoVerificationConfig = customerUsecase.PhotoVerificationConfig{
+	MaxAttempts:          3,
+	DuplicateMaxDistance: 5,
+	DuplicateAction:      customerUsecase.DuplicatePhotoReject,
+}
+
+// linkVouchers calls the link voucher usecase of every customer in parallel and returns the number of vouchers handed out,
+// the errors of a lost race are expected and any other error fails the test
+func linkVouchers(t *testing.T, useCases testutil.UseCases, campaignId int, customerIds []int) int {
+	t.Helper()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		linked  int
+		failure error
+	)
+	for _, customerId := range customerIds {
+		wg.Add(1)
+		go func(customerId int) {
+			defer wg.Done()
+
+			ctx := testutil.NewContext(httptest.NewRequest("GET", "/api/v1/link-voucher", nil))
+			_, err := useCases.Customer.GetVoucherByCustomerId(ctx, campaignId, customerId)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				linked++
+			case errors.Is(err, response.ErrVoucherNotAvailable),
+				errors.Is(err, response.ErrCampaignBudgetExhausted),
+				errors.Is(err, response.ErrCustomerAlreadyBookVoucher),
+				errors.Is(err, response.ErrCustomerAlreadyGetVoucher):
+			default:
+				failure = err
+			}
+		}(customerId)
+	}
+	wg.Wait()
+
+	if failure != nil {
+		t.Fatalf("link voucher: %v", failure)
+	}
+	return linked
+}
+
+// assertNoDoubleBooking every voucher is held by at most one live booking or customer
+// and every customer holds at most one voucher of the campaign
+func assertNoDoubleBooking(t *testing.T, useCases testutil.UseCases, campaignId, want int) {
+	t.Helper()
+
+	var books []domain.CustomerVoucherBook
+	if err := useCases.DB.Where("campaign_id = ? AND status IN ?", campaignId, []string{
+		domain.CustomerVoucherBookStatusBooked,
+		domain.CustomerVoucherBookStatusPendingReview,
+		domain.CustomerVoucherBookStatusVerified,
+	}).Find(&books).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != want {
+		t.Errorf("live bookings = %d, want %d", len(books), want)
+	}
+
+	vouchers := make(map[int]int)
+	customers := make(map[int]int)
+	for _, book := range books {
+		vouchers[book.CustomerVoucherID]++
+		customers[book.CustomerID]++
+	}
+	for voucherId, count := range vouchers {
+		if count > 1 {
+			t.Errorf("voucher %d booked %d times", voucherId, count)
+		}
+	}
+	for customerId, count := range customers {
+		if count > 1 {
+			t.Errorf("customer %d holds %d bookings", customerId, count)
+		}
+	}
+
+	var redeemed []domain.CustomerVoucher
+	if err := useCases.DB.Where("campaign_id = ? AND is_redeem = ?", campaignId, true).Find(&redeemed).Error; err != nil {
+		t.Fatal(err)
+	}
+	owners := make(map[int]int)
+	for _, voucher := range redeemed {
+		if voucher.CustomerID == nil {
+			t.Errorf("voucher %d redeemed without customer", voucher.ID)
+			continue
+		}
+		owners[*voucher.CustomerID]++
+	}
+	for customerId, count := range owners {
+		if count > 1 {
+			t.Errorf("customer %d redeemed %d vouchers", customerId, count)
+		}
+	}
+}
+
+// TestGetVoucherByCustomerIdConcurrent parallel link voucher requests on sqlite. The requests interleave but sqlite
+// runs their transactions one after another, so it checks the budget, retry and per customer rules, not the row
+// locks. TestGetVoucherByCustomerIdConcurrentIntegration runs the same cases on a database contending for the locks
+func TestGetVoucherByCustomerIdConcurrent(t *testing.T) {
+	testGetVoucherByCustomerIdConcurrent(t, testutil.NewSQLiteDB)
+}
+
+func testGetVoucherByCustomerIdConcurrent(t *testing.T, newDB func(t testing.TB) *gorm.DB) {
+	tests := []struct {
+		name                      string
+		budget                    int
+		vouchers                  int
+		customers                 int
+		callsPerCustomer          int
+		photoVerificationRequired bool
+		want                      int
+	}{
+		{
+			name:             "vouchers run out",
+			budget:           1000,
+			vouchers:         40,
+			customers:        150,
+			callsPerCustomer: 2,
+			want:             40,
+		},
+		{
+			name:                      "budget runs out",
+			budget:                    25,
+			vouchers:                  100,
+			customers:                 300,
+			callsPerCustomer:          1,
+			photoVerificationRequired: true,
+			want:                      25,
+		},
+		{
+			name:             "customers retry redeemed vouchers",
+			budget:           1000,
+			vouchers:         1000,
+			customers:        50,
+			callsPerCustomer: 6,
+			want:             50,
+		},
+		{
+			name:                      "customers retry booked vouchers",
+			budget:                    1000,
+			vouchers:                  1000,
+			customers:                 50,
+			callsPerCustomer:          6,
+			photoVerificationRequired: true,
+			want:                      50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDB(t)
+			useCases := testutil.NewUseCases(t, db, defaultPhotoVerificationConfig)
+			campaign := testutil.CreateCampaign(t, db, tt.budget, tt.vouchers, tt.photoVerificationRequired)
+			customerIds := testutil.CreateCustomers(t, db, tt.customers)
+
+			calls := make([]int, 0, len(customerIds)*tt.callsPerCustomer)
+			for i := 0; i < tt.callsPerCustomer; i++ {
+				calls = append(calls, customerIds...)
+			}
+
+			if linked := linkVouchers(t, useCases, campaign.ID, calls); linked != tt.want {
+				t.Errorf("linked vouchers = %d, want %d", linked, tt.want)
+			}
+			assertNoDoubleBooking(t, useCases, campaign.ID, tt.want)
+		})
+	}
+}
+
+// bookVoucher books a voucher of a campaign requiring photo verification for a new customer
+func bookVoucher(t *testing.T, useCases testutil.UseCases) (domain.Campaign, int) {
+	t.Helper()
+
+	campaign := testutil.CreateCampaign(t, useCases.DB, 10, 10, true)
+	customerId := testutil.CreateCustomers(t, useCases.DB, 1)[0]
+	if linked := linkVouchers(t, useCases, campaign.ID, []int{customerId}); linked != 1 {
+		t.Fatalf("linked vouchers = %d, want 1", linked)
+	}
+	return campaign, customerId
+}
+
+// verifyPhoto uploads a photo with skinRatio skin tone pixels for the booking of the customer
+func verifyPhoto(t *testing.T, useCases testutil.UseCases, campaignId, customerId int, skinRatio float64) (*domain.CustomerVerifyPhotoResponse, error) {
+	t.Helper()
+
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/verify-photo", nil))
+	return useCases.Customer.VerifyPhotoCustomer(ctx, campaignId, customerId, testutil.NewFileHeader(t, "photo.png", testutil.NewPhoto(t, skinRatio)))
+}
+
+func TestVerifyPhotoCustomer(t *testing.T) {
+	tests := []struct {
+		name        string
+		skinRatio   float64
+		wantErr     error
+		wantStatus  string
+		wantOutcome string
+		wantRedeem  bool
+	}{
+		{
+			name:        "face verified",
+			skinRatio:   1,
+			wantStatus:  domain.CustomerVoucherBookStatusVerified,
+			wantOutcome: domain.VerificationAttemptVerified,
+			wantRedeem:  true,
+		},
+		{
+			name:        "face rejected",
+			skinRatio:   0,
+			wantErr:     response.ErrCustomerVerifyImage,
+			wantStatus:  domain.CustomerVoucherBookStatusBooked,
+			wantOutcome: domain.VerificationAttemptRejected,
+		},
+		{
+			name:        "face inconclusive",
+			skinRatio:   0.2,
+			wantStatus:  domain.CustomerVoucherBookStatusPendingReview,
+			wantOutcome: domain.VerificationAttemptInconclusive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCases := testutil.NewUseCases(t, testutil.NewSQLiteDB(t), defaultPhotoVerificationConfig)
+			campaign, customerId := bookVoucher(t, useCases)
+
+			result, err := verifyPhoto(t, useCases, campaign.ID, customerId, tt.skinRatio)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if result == nil {
+				t.Fatal("result = nil")
+			}
+			if result.Attempts != 1 || result.RemainingAttempts != defaultPhotoVerificationConfig.MaxAttempts-1 {
+				t.Errorf("attempts = %d remaining %d, want 1 remaining %d", result.Attempts, result.RemainingAttempts, defaultPhotoVerificationConfig.MaxAttempts-1)
+			}
+			if tt.wantErr == nil && result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if (result.VoucherCode != "") != tt.wantRedeem {
+				t.Errorf("voucher code = %q, want redeemed %v", result.VoucherCode, tt.wantRedeem)
+			}
+
+			var book domain.CustomerVoucherBook
+			if err := useCases.DB.Where("customer_id = ?", customerId).First(&book).Error; err != nil {
+				t.Fatal(err)
+			}
+			if book.Status != tt.wantStatus {
+				t.Errorf("booking status = %s, want %s", book.Status, tt.wantStatus)
+			}
+
+			var attempts []domain.CustomerVoucherBookAttempt
+			if err := useCases.DB.Where("customer_voucher_book_id = ?", book.ID).Find(&attempts).Error; err != nil {
+				t.Fatal(err)
+			}
+			if len(attempts) != 1 || attempts[0].Outcome != tt.wantOutcome {
+				t.Errorf("attempts = %+v, want one %s attempt", attempts, tt.wantOutcome)
+			}
+
+			var voucher domain.CustomerVoucher
+			if err := useCases.DB.First(&voucher, book.CustomerVoucherID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if voucher.IsRedeem != tt.wantRedeem {
+				t.Errorf("voucher redeemed = %v, want %v", voucher.IsRedeem, tt.wantRedeem)
+			}
+		})
+	}
+}
+
+func TestVerifyPhotoCustomerAttemptsExceeded(t *testing.T) {
+	useCases := testutil.NewUseCases(t, testutil.NewSQLiteDB(t), defaultPhotoVerificationConfig)
+	campaign, customerId := bookVoucher(t, useCases)
+
+	for i := 0; i < defaultPhotoVerificationConfig.MaxAttempts; i++ {
+		if _, err := verifyPhoto(t, useCases, campaign.ID, customerId, 0); !errors.Is(err, response.ErrCustomerVerifyImage) {
+			t.Fatalf("attempt %d error = %v, want %v", i+1, err, response.ErrCustomerVerifyImage)
+		}
+	}
+
+	result, err := verifyPhoto(t, useCases, campaign.ID, customerId, 1)
+	if !errors.Is(err, response.ErrVerificationAttemptsExceeded) {
+		t.Fatalf("error = %v, want %v", err, response.ErrVerificationAttemptsExceeded)
+	}
+	if result == nil || result.RemainingAttempts != 0 {
+		t.Errorf("result = %+v, want no remaining attempts", result)
+	}
+
+	var book domain.CustomerVoucherBook
+	if err := useCases.DB.Where("customer_id = ?", customerId).First(&book).Error; err != nil {
+		t.Fatal(err)
+	}
+	if book.Status != domain.CustomerVoucherBookStatusLocked {
+		t.Errorf("booking status = %s, want %s", book.Status, domain.CustomerVoucherBookStatusLocked)
+	}
+}
+
+func TestVerifyPhotoCustomerConcurrentAttempts(t *testing.T) {
+	useCases := testutil.NewUseCases(t, testutil.NewSQLiteDB(t), defaultPhotoVerificationConfig)
+	campaign, customerId := bookVoucher(t, useCases)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		rejected int
+		exceeded int
+		failure  error
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := verifyPhoto(t, useCases, campaign.ID, customerId, 0)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, response.ErrCustomerVerifyImage):
+				rejected++
+			case errors.Is(err, response.ErrVerificationAttemptsExceeded):
+				exceeded++
+			default:
+				failure = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failure != nil {
+		t.Fatalf("verify photo: %v", failure)
+	}
+	if rejected != defaultPhotoVerificationConfig.MaxAttempts || exceeded != 10-defaultPhotoVerificationConfig.MaxAttempts {
+		t.Errorf("rejected = %d exceeded = %d, want %d rejected", rejected, exceeded, defaultPhotoVerificationConfig.MaxAttempts)
+	}
+
+	var book domain.CustomerVoucherBook
+	if err := useCases.DB.Where("customer_id = ?", customerId).First(&book).Error; err != nil {
+		t.Fatal(err)
+	}
+	if book.Status != domain.CustomerVoucherBookStatusLocked {
+		t.Errorf("booking status = %s, want %s", book.Status, domain.CustomerVoucherBookStatusLocked)
+	}
+	if book.PhotoHash != "" || book.PhotoPath != "" {
+		t.Errorf("booking photo = %q %q, want none of the rejected photos", book.PhotoHash, book.PhotoPath)
+	}
+
+	var attempts int64
+	if err := useCases.DB.Model(&domain.CustomerVoucherBookAttempt{}).Where("customer_voucher_book_id = ?", book.ID).Count(&attempts).Error; err != nil {
+		t.Fatal(err)
+	}
+	if int(attempts) != defaultPhotoVerificationConfig.MaxAttempts {
+		t.Errorf("attempts = %d, want %d", attempts, defaultPhotoVerificationConfig.MaxAttempts)
+	}
+}
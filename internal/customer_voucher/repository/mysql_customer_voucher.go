@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
 	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type mysqlCustomerVoucherRepository struct {
@@ -26,9 +28,55 @@ func (c mysqlCustomerVoucherRepository) DB() *gorm.DB {
 	return c.db
 }
 
+func (c mysqlCustomerVoucherRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlCustomerVoucherRepository) CountFilterWithTx(ctx context.Context, tx *gorm.DB, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := tx.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 func (c mysqlCustomerVoucherRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
 	p := paginator.NewPaginator(c.db, offset, limit, model)
-	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args).Select(strings.Join(fields, ",")).Error; err != nil {
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
 		return nil, err
 	}
 	return model, nil
@@ -114,3 +162,80 @@ func (c mysqlCustomerVoucherRepository) StoreWithTx(ctx context.Context, tx *gor
 	}
 	return data.ID, nil
 }
+
+// pendingReviewVouchersQuery vouchers of bookings waiting for a reviewer, they stay taken after their reservation lapsed
+const pendingReviewVouchersQuery = "SELECT customer_voucher_id FROM customer_voucher_books WHERE status = ? AND customer_voucher_id IS NOT NULL"
+
+// ClaimAvailableWithTx locks one voucher of the campaign that is not redeemed, not reserved by an active booking
+// and not waiting for review, then reserves it until reservedUntil. Vouchers locked by another transaction are skipped, with FOR UPDATE SKIP LOCKED
+// on mysql and postgres and the UPDLOCK, READPAST table hints on sql server. The reservation is a conditional update,
+// so a voucher can only be claimed by one transaction even when the lock is not taken.
+// Returns gorm.ErrRecordNotFound when there is no voucher left to claim.
+func (c mysqlCustomerVoucherRepository) ClaimAvailableWithTx(ctx context.Context, tx *gorm.DB, campaignId int, now, reservedUntil time.Time) (domain.CustomerVoucher, error) {
+	var data domain.CustomerVoucher
+
+	db := tx.WithContext(ctx)
+	if db.Dialector.Name() == "sqlserver" {
+		db = db.Table(domain.CustomerVoucher{}.TableName() + " WITH (UPDLOCK, READPAST, ROWLOCK)")
+	} else {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	}
+
+	err := db.
+		Where("campaign_id = ?", campaignId).
+		Where("is_redeem = ?", false).
+		Where("(reserved_until IS NULL OR reserved_until < ?)", now).
+		Where("id NOT IN ("+pendingReviewVouchersQuery+")", domain.CustomerVoucherBookStatusPendingReview).
+		First(&data).Error
+	if err != nil {
+		return data, err
+	}
+
+	result := tx.WithContext(ctx).Table(domain.CustomerVoucher{}.TableName()).
+		Where("id = ?", data.ID).
+		Where("is_redeem = ?", false).
+		Where("(reserved_until IS NULL OR reserved_until < ?)", now).
+		Where("id NOT IN ("+pendingReviewVouchersQuery+")", domain.CustomerVoucherBookStatusPendingReview).
+		Update("reserved_until", reservedUntil)
+	if result.Error != nil {
+		return data, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return data, gorm.ErrRecordNotFound
+	}
+
+	data.ReservedUntil = &reservedUntil
+	return data, nil
+}
+
+// ReleaseReservationWithTx clear the reservation of a booking expiring at reservedUntil. A voucher claimed again
+// after the booking expired is reserved until a later date and keeps its reservation, false is returned then.
+func (c mysqlCustomerVoucherRepository) ReleaseReservationWithTx(ctx context.Context, tx *gorm.DB, id int, reservedUntil time.Time) (bool, error) {
+	result := tx.WithContext(ctx).Table(domain.CustomerVoucher{}.TableName()).
+		Where("id = ?", id).
+		Where("reserved_until <= ?", reservedUntil).
+		Update("reserved_until", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RedeemWithTx redeem the voucher for the customer, the update is conditional so a voucher is never redeemed twice.
+// Returns gorm.ErrRecordNotFound when the voucher was already redeemed.
+func (c mysqlCustomerVoucherRepository) RedeemWithTx(ctx context.Context, tx *gorm.DB, id, customerId int) error {
+	result := tx.WithContext(ctx).Table(domain.CustomerVoucher{}.TableName()).
+		Where("id = ?", id).
+		Where("is_redeem = ?", false).
+		Updates(map[string]interface{}{
+			"customer_id": customerId,
+			"is_redeem":   true,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
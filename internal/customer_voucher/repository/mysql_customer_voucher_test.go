@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"gorm.io/gorm"
+)
+
+func TestClaimAvailableWithTx(t *testing.T) {
+	tests := []struct {
+		name      string
+		typesConn string
+		lock      string
+	}{
+		{
+			name:      "postgres skips locked rows",
+			typesConn: "postgres",
+			lock:      `FOR UPDATE SKIP LOCKED`,
+		},
+		{
+			name:      "sql server skips locked rows with table hints",
+			typesConn: "sql",
+			lock:      `FROM customer_voucher WITH (UPDLOCK, READPAST, ROWLOCK)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := helper.NewMockDB(tt.typesConn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo := NewMysqlCustomerVoucherRepository(db, nil)
+
+			now := time.Now()
+			reservedUntil := now.Add(10 * time.Minute)
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.lock)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "voucher_code", "is_redeem"}).AddRow(7, 1, "VOUCHER", false))
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			voucher, err := repo.ClaimAvailableWithTx(context.Background(), db, 1, now, reservedUntil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if voucher.ID != 7 || voucher.ReservedUntil == nil || !voucher.ReservedUntil.Equal(reservedUntil) {
+				t.Errorf("claimed voucher = %+v", voucher)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestClaimAvailableWithTxClaimedMeanwhile(t *testing.T) {
+	db, mock, err := helper.NewMockDB("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewMysqlCustomerVoucherRepository(db, nil)
+
+	mock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "voucher_code", "is_redeem"}).AddRow(7, 1, "VOUCHER", false))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	_, err = repo.ClaimAvailableWithTx(context.Background(), db, 1, time.Now(), time.Now().Add(10*time.Minute))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("error = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+const (
+	// voucherBatchSize vouchers stored per database transaction
+	voucherBatchSize = 500
+	// voucherGenerateAttempts batches colliding with stored codes before the generation gives up
+	voucherGenerateAttempts = 10
+	// MinVoucherCodeLength shortest generated code
+	MinVoucherCodeLength = 6
+
+	importColumnVoucherCode = "voucher_code"
+)
+
+// voucherCodeLetters letters of generated codes, without the ones read alike (0/O, 1/I)
+const voucherCodeLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type customerVoucherUseCase struct {
+	zapLogger                      zaplogger.Logger
+	contextTimeout                 time.Duration
+	mysqlCampaignRepository        domain.MysqlCampaignRepository
+	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository
+}
+
+func NewCustomerVoucherUseCase(timeout time.Duration,
+	mysqlCampaignRepository domain.MysqlCampaignRepository,
+	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository,
+	zapLogger zaplogger.Logger) domain.CustomerVoucherUseCase {
+	return &customerVoucherUseCase{
+		mysqlCampaignRepository:        mysqlCampaignRepository,
+		mysqlCustomerVoucherRepository: mysqlCustomerVoucherRepository,
+		contextTimeout:                 timeout,
+		zapLogger:                      zapLogger,
+	}
+}
+
+func (r customerVoucherUseCase) checkCampaign(ctx context.Context, campaignId int) error {
+	var campaign domain.Campaign
+	return r.mysqlCampaignRepository.SingleWithFilter(ctx, []string{"id"}, []string{}, []string{"id = ?"}, &campaign, campaignId)
+}
+
+// existingVoucherCodes codes of the list already stored in any campaign
+func (r customerVoucherUseCase) existingVoucherCodes(ctx context.Context, codes []string) (map[string]bool, error) {
+	vouchers, err := r.mysqlCustomerVoucherRepository.FetchWithFilter(
+		ctx,
+		len(codes),
+		0,
+		"id ASC",
+		[]string{
+			"voucher_code",
+		},
+		[]string{},
+		[]string{"voucher_code IN ?"},
+		&[]domain.CustomerVoucher{}, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(codes))
+	if stored, ok := vouchers.(*[]domain.CustomerVoucher); ok {
+		for _, voucher := range *stored {
+			result[voucher.VoucherCode] = true
+		}
+	}
+	return result, nil
+}
+
+// storeVoucherBatch store the codes not stored yet as available vouchers of the campaign in a single
+// database transaction, returns the number of stored vouchers
+func (r customerVoucherUseCase) storeVoucherBatch(ctx context.Context, campaignId int, codes []string) (int, error) {
+	if len(codes) == 0 {
+		return 0, nil
+	}
+
+	c, cancel := context.WithTimeout(ctx, r.contextTimeout)
+	defer cancel()
+
+	existing, err := r.existingVoucherCodes(c, codes)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	err = r.mysqlCustomerVoucherRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		created = 0
+		for _, code := range codes {
+			if existing[code] {
+				continue
+			}
+			if _, err := r.mysqlCustomerVoucherRepository.StoreWithTx(c, tx, domain.CustomerVoucher{
+				CampaignID:  campaignId,
+				VoucherCode: code,
+			}); err != nil {
+				return err
+			}
+			created++
+		}
+		return nil
+	})
+	return created, err
+}
+
+// GenerateVouchers store count random codes of the given length as available vouchers of the campaign,
+// codes colliding with stored vouchers are replaced. Returns the number of stored vouchers
+func (r customerVoucherUseCase) GenerateVouchers(ctx context.Context, campaignId, count, length int) (int, error) {
+	if count < 1 {
+		return 0, errors.New("count must be greater than 0")
+	}
+	if length < MinVoucherCodeLength || length > 255 {
+		return 0, fmt.Errorf("length must be between %d and 255", MinVoucherCodeLength)
+	}
+	if err := r.checkCampaign(ctx, campaignId); err != nil {
+		return 0, err
+	}
+
+	created := 0
+	collisions := 0
+	for created < count {
+		size := count - created
+		if size > voucherBatchSize {
+			size = voucherBatchSize
+		}
+
+		codes := make([]string, 0, size)
+		seen := make(map[string]bool, size)
+		for len(codes) < size {
+			code, err := randomVoucherCode(length)
+			if err != nil {
+				return created, err
+			}
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+
+		stored, err := r.storeVoucherBatch(ctx, campaignId, codes)
+		created += stored
+		if err != nil {
+			return created, err
+		}
+		if stored < len(codes) {
+			collisions++
+			if collisions >= voucherGenerateAttempts {
+				return created, errors.New("generated codes keep colliding with stored vouchers, use a longer code")
+			}
+		}
+	}
+	return created, nil
+}
+
+func randomVoucherCode(length int) (string, error) {
+	max := big.NewInt(int64(len(voucherCodeLetters)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = voucherCodeLetters[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// ImportVouchers store the voucher_code column of the csv file as available vouchers of the campaign.
+// Codes already stored or repeated in the file are counted as duplicates, the vouchers are committed
+// per batch so the stored batches are kept when a later batch fails
+func (r customerVoucherUseCase) ImportVouchers(ctx context.Context, campaignId int, file io.Reader) (*domain.CustomerVoucherImportResult, error) {
+	if err := r.checkCampaign(ctx, campaignId); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid voucher file: %v", err)
+	}
+	column := -1
+	for i, name := range header {
+		if strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) == importColumnVoucherCode {
+			column = i
+		}
+	}
+	if column < 0 {
+		return nil, fmt.Errorf("invalid voucher file: missing column %s", importColumnVoucherCode)
+	}
+
+	result := &domain.CustomerVoucherImportResult{}
+	batch := make([]string, 0, voucherBatchSize)
+	flush := func() error {
+		stored, err := r.storeVoucherBatch(ctx, campaignId, batch)
+		result.Created += stored
+		if err != nil {
+			return err
+		}
+		result.Duplicates += len(batch) - stored
+		batch = batch[:0]
+		return nil
+	}
+
+	seen := map[string]bool{}
+	row := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return result, err
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %v", row, err))
+			continue
+		}
+
+		code := ""
+		if column < len(record) {
+			code = strings.TrimSpace(record[column])
+		}
+		if code == "" || len(code) > 255 {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %s must be between 1 and 255 characters", row, importColumnVoucherCode))
+			continue
+		}
+		if seen[code] {
+			result.Duplicates++
+			continue
+		}
+		seen[code] = true
+
+		batch = append(batch, code)
+		if len(batch) >= voucherBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,375 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type CustomerVoucherBookHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	CustomerVoucherBookUsecase domain.CustomerVoucherBookUseCase
+}
+
+func NewCustomerVoucherBookHandler(customerVoucherBookUsecase domain.CustomerVoucherBookUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &CustomerVoucherBookHandler{
+		ZapLogger:                  zapLogger,
+		CustomerVoucherBookUsecase: customerVoucherBookUsecase,
+	}
+	beego.Router("/api/v1/customers/:id/bookings", pHandler, "get:GetBookings")
+	beego.Router("/api/v1/admin/bookings/:id/photo", pHandler, "get:GetBookingPhoto")
+	beego.Router("/api/v1/admin/bookings/:id/attempts", pHandler, "get:GetBookingAttempts")
+	beego.Router("/api/v1/admin/bookings/:id/attempts/:attemptId/photo", pHandler, "get:GetAttemptPhoto")
+	beego.Router("/api/v1/admin/reviews", pHandler, "get:GetReviews")
+	beego.Router("/api/v1/admin/reviews/:id/approve", pHandler, "post:ApproveReview")
+	beego.Router("/api/v1/admin/reviews/:id/reject", pHandler, "post:RejectReview")
+	beego.InsertFilterChain("/api/v1/admin/bookings/:id/photo", middlewares.RequirePermission(domain.PermissionReviewsRead))
+	beego.InsertFilterChain("/api/v1/admin/bookings/:id/attempts", middlewares.RequirePermission(domain.PermissionReviewsRead))
+	beego.InsertFilterChain("/api/v1/admin/bookings/:id/attempts/:attemptId/photo", middlewares.RequirePermission(domain.PermissionReviewsRead))
+	beego.InsertFilterChain("/api/v1/admin/reviews", middlewares.RequirePermission(domain.PermissionReviewsRead))
+	beego.InsertFilterChain("/api/v1/admin/reviews/:id/approve", middlewares.RequirePermission(domain.PermissionReviewsWrite))
+	beego.InsertFilterChain("/api/v1/admin/reviews/:id/reject", middlewares.RequirePermission(domain.PermissionReviewsWrite))
+}
+
+func (h *CustomerVoucherBookHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetBookings
+// @Title GetBookings
+// @Tags Customer
+// @Summary GetBookings
+// @Description voucher booking history of the customer with every status change
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Authorization header string false "bearer token of the customer, Bearer {token}"
+// @Param X-API-KEY header string false "api key granted customers:read or reviews:read, instead of the bearer token"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerVoucherBookHistoryListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/customers/{id}/bookings [get]
+func (h *CustomerVoucherBookHandler) GetBookings() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.GetBookingsByCustomerId(h.Ctx, pathParam, page, limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+			return
+		}
+		h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetBookingPhoto
+// @Title GetBookingPhoto
+// @Tags Admin
+// @Summary GetBookingPhoto
+// @Description verification photo uploaded for the booking, requires the admin api key
+// @Produce image/jpeg,image/png,image/webp
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {file} binary
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id booking"
+// @Router /v1/admin/bookings/{id}/photo [get]
+func (h *CustomerVoucherBookHandler) GetBookingPhoto() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.GetBookingPhoto(h.Ctx, pathParam)
+	if err != nil {
+		h.responsePhotoError(err)
+		return
+	}
+	h.responsePhoto(result)
+}
+
+// GetBookingAttempts
+// @Title GetBookingAttempts
+// @Tags Admin
+// @Summary GetBookingAttempts
+// @Description photo verification attempts of the booking with the url of their photo, rejected photos included, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=[]domain.CustomerVoucherBookAttemptResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id booking"
+// @Router /v1/admin/bookings/{id}/attempts [get]
+func (h *CustomerVoucherBookHandler) GetBookingAttempts() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.GetBookingAttempts(h.Ctx, pathParam)
+	if err != nil {
+		h.responsePhotoError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetAttemptPhoto
+// @Title GetAttemptPhoto
+// @Tags Admin
+// @Summary GetAttemptPhoto
+// @Description photo uploaded with a verification attempt of the booking, rejected photos included, requires the admin api key
+// @Produce image/jpeg,image/png,image/webp
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {file} binary
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id booking"
+// @Param    attemptId path int true "id attempt"
+// @Router /v1/admin/bookings/{id}/attempts/{attemptId}/photo [get]
+func (h *CustomerVoucherBookHandler) GetAttemptPhoto() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	attemptId, err := strconv.Atoi(h.Ctx.Input.Param(":attemptId"))
+	if err != nil || attemptId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.GetAttemptPhoto(h.Ctx, pathParam, attemptId)
+	if err != nil {
+		h.responsePhotoError(err)
+		return
+	}
+	h.responsePhoto(result)
+}
+
+func (h *CustomerVoucherBookHandler) responsePhoto(photo *domain.CustomerVoucherBookPhoto) {
+	h.Ctx.Output.Header("Content-Type", photo.ContentType)
+	h.Ctx.Output.Header("ETag", `"`+photo.Hash+`"`)
+	h.Ctx.Output.SetStatus(http.StatusOK)
+	h.Ctx.Output.Body(photo.Data)
+}
+
+func (h *CustomerVoucherBookHandler) responsePhotoError(err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
+
+// GetReviews
+// @Title GetReviews
+// @Tags Admin
+// @Summary GetReviews
+// @Description bookings with an inconclusive photo waiting for a reviewer, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerVoucherBookReviewListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/admin/reviews [get]
+func (h *CustomerVoucherBookHandler) GetReviews() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.GetPendingReviews(h.Ctx, page, limit)
+	if err != nil {
+		h.responseReviewError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// ApproveReview
+// @Title ApproveReview
+// @Tags Admin
+// @Summary ApproveReview
+// @Description approve the photo of a booking waiting for review and redeem its voucher, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerVoucherBookReviewResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id booking"
+// @Param    body body domain.CustomerVoucherBookReviewRequest false "request payload"
+// @Router /v1/admin/reviews/{id}/approve [post]
+func (h *CustomerVoucherBookHandler) ApproveReview() {
+	pathParam, request, ok := h.reviewRequest()
+	if !ok {
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.ApproveReview(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responseReviewError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// RejectReview
+// @Title RejectReview
+// @Tags Admin
+// @Summary RejectReview
+// @Description reject the photo of a booking waiting for review and return its voucher to the pool, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.CustomerVoucherBookReviewResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id booking"
+// @Param    body body domain.CustomerVoucherBookReviewRequest false "request payload"
+// @Router /v1/admin/reviews/{id}/reject [post]
+func (h *CustomerVoucherBookHandler) RejectReview() {
+	pathParam, request, ok := h.reviewRequest()
+	if !ok {
+		return
+	}
+
+	result, err := h.CustomerVoucherBookUsecase.RejectReview(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responseReviewError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// reviewRequest booking id and optional body of a review decision, the error response is written when it is invalid
+func (h *CustomerVoucherBookHandler) reviewRequest() (int, domain.CustomerVoucherBookReviewRequest, bool) {
+	var request domain.CustomerVoucherBookReviewRequest
+
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return 0, request, false
+	}
+
+	if len(h.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+			return 0, request, false
+		}
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return 0, request, false
+	}
+	return pathParam, request, true
+}
+
+func (h *CustomerVoucherBookHandler) responseReviewError(err error) {
+	if errors.Is(err, response.ErrInvalidBookStatusTransition) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.InvalidBookStatusTransition, response.ErrorCodeText(response.InvalidBookStatusTransition, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrVoucherNotAvailable) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.VoucherNotAvailable, response.ErrorCodeText(response.VoucherNotAvailable, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
 	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagehash"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
 )
@@ -26,9 +30,32 @@ func (c mysqlCustomerVoucherBookRepository) DB() *gorm.DB {
 	return c.db
 }
 
+func (c mysqlCustomerVoucherBookRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 func (c mysqlCustomerVoucherBookRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
 	p := paginator.NewPaginator(c.db, offset, limit, model)
-	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args).Select(strings.Join(fields, ",")).Error; err != nil {
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
 		return nil, err
 	}
 	return model, nil
@@ -60,6 +87,32 @@ func (c mysqlCustomerVoucherBookRepository) SingleWithFilter(ctx context.Context
 	return nil
 }
 
+func (c mysqlCustomerVoucherBookRepository) SingleWithFilterWithTx(ctx context.Context, tx *gorm.DB, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := tx.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c mysqlCustomerVoucherBookRepository) Update(ctx context.Context, data domain.CustomerVoucherBook) error {
 
 	err := c.db.WithContext(ctx).Updates(&data).Error
@@ -115,3 +168,88 @@ func (c mysqlCustomerVoucherBookRepository) StoreWithTx(ctx context.Context, tx
 	}
 	return data.ID, nil
 }
+
+// UpdateStatusWithTx move the booking to the next status only when it still has the expected status,
+// gorm.ErrRecordNotFound is returned when the booking was changed by another request
+func (c mysqlCustomerVoucherBookRepository) UpdateStatusWithTx(ctx context.Context, tx *gorm.DB, id int, from, to string) error {
+	result := tx.WithContext(ctx).
+		Table(domain.CustomerVoucherBook{}.TableName()).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// LockWithTx locks the booking row so concurrent photo verifications of the same booking are serialized.
+func (c mysqlCustomerVoucherBookRepository) LockWithTx(ctx context.Context, tx *gorm.DB, id int) (domain.CustomerVoucherBook, error) {
+	var data domain.CustomerVoucherBook
+
+	err := database.LockForUpdate(tx.WithContext(ctx), data.TableName()).Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// similarPhotoHashPageSize photo hashes read per query by FetchSimilarPhotoHash
+const similarPhotoHashPageSize = 1000
+
+// FetchSimilarPhotoHash verified and pending review bookings of other customers whose photo hash is within maxDistance bits of hash,
+// newest first. Only the bookings sharing a byte of the hash are read, through the indexes of the band columns, so maxDistance must
+// be below imagehash.Bands. The distance of the candidates is computed here so it works on every dialect.
+func (c mysqlCustomerVoucherBookRepository) FetchSimilarPhotoHash(ctx context.Context, customerId int, hash int64, maxDistance int, limit int) ([]domain.CustomerVoucherBook, error) {
+	if maxDistance >= imagehash.Bands {
+		return nil, fmt.Errorf("photo hash distance %d is not below %d bands", maxDistance, imagehash.Bands)
+	}
+
+	bands := imagehash.Split(uint64(hash))
+	conditions := make([]string, 0, len(bands))
+	args := make([]interface{}, 0, len(bands))
+	for i, band := range bands {
+		conditions = append(conditions, domain.PhotoPerceptualHashBandColumns[i]+" = ?")
+		args = append(args, band)
+	}
+	sameBand := "(" + strings.Join(conditions, " OR ") + ")"
+
+	result := make([]domain.CustomerVoucherBook, 0, limit)
+	lastId := 0
+	for {
+		var candidates []domain.CustomerVoucherBook
+
+		query := c.db.WithContext(ctx).
+			Select("id", "customer_id", "campaign_id", "status", "photo_phash").
+			Where(sameBand, args...).
+			Where("status IN ?", []string{domain.CustomerVoucherBookStatusVerified, domain.CustomerVoucherBookStatusPendingReview}).
+			Where("customer_id <> ?", customerId)
+		if lastId > 0 {
+			query = query.Where("id < ?", lastId)
+		}
+		err := query.Order("id DESC").Limit(similarPhotoHashPageSize).Find(&candidates).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range candidates {
+			if imagehash.Distance(uint64(*candidate.PhotoPerceptualHash), uint64(hash)) > maxDistance {
+				continue
+			}
+			result = append(result, candidate)
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+
+		if len(candidates) < similarPhotoHashPageSize {
+			return result, nil
+		}
+		lastId = candidates[len(candidates)-1].ID
+	}
+}
@@ -0,0 +1,202 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagehash"
+)
+
+func TestFetchSimilarPhotoHash(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	repo := repository.NewMysqlCCustomerVoucherBookRepository(db, nil)
+	campaign := testutil.CreateCampaign(t, db, 10, 0, true)
+	customerIds := testutil.CreateCustomers(t, db, 2)
+
+	var hash int64 = 0x0f0f0f0f0f0f0f0f
+	// withDistance hash differing from hash in the lowest bits
+	withDistance := func(distance int) int64 {
+		return hash ^ int64(1<<uint(distance)-1)
+	}
+	books := []domain.CustomerVoucherBook{
+		{CustomerID: customerIds[0], Status: domain.CustomerVoucherBookStatusVerified},
+		{CustomerID: customerIds[1], Status: domain.CustomerVoucherBookStatusVerified},
+		{CustomerID: customerIds[1], Status: domain.CustomerVoucherBookStatusPendingReview},
+		{CustomerID: customerIds[1], Status: domain.CustomerVoucherBookStatusVerified},
+		{CustomerID: customerIds[1], Status: domain.CustomerVoucherBookStatusReleased},
+		{CustomerID: customerIds[1], Status: domain.CustomerVoucherBookStatusVerified},
+	}
+	books[0].SetPhotoPerceptualHash(withDistance(0))
+	books[1].SetPhotoPerceptualHash(withDistance(3))
+	books[2].SetPhotoPerceptualHash(withDistance(5))
+	books[3].SetPhotoPerceptualHash(withDistance(10))
+	books[4].SetPhotoPerceptualHash(withDistance(0))
+	for i := range books {
+		books[i].CampaignID = campaign.ID
+		if err := db.Create(&books[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		maxDistance int
+		limit       int
+		want        []int
+	}{
+		{
+			name:        "verified and pending review bookings of other customers within the distance",
+			maxDistance: 6,
+			limit:       10,
+			want:        []int{books[2].ID, books[1].ID},
+		},
+		{
+			name:        "closer distance",
+			maxDistance: 4,
+			limit:       10,
+			want:        []int{books[1].ID},
+		},
+		{
+			name:        "limited to the latest booking",
+			maxDistance: 6,
+			limit:       1,
+			want:        []int{books[2].ID},
+		},
+		{
+			name:        "no similar photo",
+			maxDistance: 2,
+			limit:       10,
+			want:        []int{},
+		},
+		{
+			name:        "photo at the max distance",
+			maxDistance: 5,
+			limit:       10,
+			want:        []int{books[2].ID, books[1].ID},
+		},
+		{
+			name:        "photo one bit above the max distance",
+			maxDistance: 4,
+			limit:       10,
+			want:        []int{books[1].ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.FetchSimilarPhotoHash(context.Background(), customerIds[0], hash, tt.maxDistance, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result) != len(tt.want) {
+				t.Fatalf("similar bookings = %d, want %d", len(result), len(tt.want))
+			}
+			for i := range result {
+				if result[i].ID != tt.want[i] {
+					t.Errorf("similar booking %d = %d, want %d", i, result[i].ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFetchSimilarPhotoHashSpreadOverBands(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	repo := repository.NewMysqlCCustomerVoucherBookRepository(db, nil)
+	campaign := testutil.CreateCampaign(t, db, 10, 0, true)
+	customerIds := testutil.CreateCustomers(t, db, 2)
+
+	var hash int64 = 0x0f0f0f0f0f0f0f0f
+	// one bit of each of the first maxDistance bytes differs, the bookings share only the last bytes
+	spread := func(distance int) int64 {
+		similar := hash
+		for i := 0; i < distance; i++ {
+			similar ^= int64(1) << uint(63-8*i)
+		}
+		return similar
+	}
+
+	tests := []struct {
+		name        string
+		distance    int
+		maxDistance int
+		want        bool
+	}{
+		{
+			name:        "differing bytes at the max distance",
+			distance:    imagehash.Bands - 1,
+			maxDistance: imagehash.Bands - 1,
+			want:        true,
+		},
+		{
+			name:        "differing bytes above the max distance",
+			distance:    imagehash.Bands - 1,
+			maxDistance: imagehash.Bands - 2,
+		},
+		{
+			name:        "every byte differs",
+			distance:    imagehash.Bands,
+			maxDistance: imagehash.Bands - 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := domain.CustomerVoucherBook{CustomerID: customerIds[1], CampaignID: campaign.ID, Status: domain.CustomerVoucherBookStatusVerified}
+			book.SetPhotoPerceptualHash(spread(tt.distance))
+			if err := db.Create(&book).Error; err != nil {
+				t.Fatal(err)
+			}
+			defer db.Delete(&book)
+
+			result, err := repo.FetchSimilarPhotoHash(context.Background(), customerIds[0], hash, tt.maxDistance, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found := len(result) == 1 && result[0].ID == book.ID; found != tt.want {
+				t.Errorf("similar booking found = %v, want %v", found, tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.FetchSimilarPhotoHash(context.Background(), customerIds[0], hash, imagehash.Bands, 1); err == nil {
+		t.Errorf("max distance of %d bands is accepted", imagehash.Bands)
+	}
+}
+
+func TestFetchSimilarPhotoHashOlderThanPage(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	repo := repository.NewMysqlCCustomerVoucherBookRepository(db, nil)
+	campaign := testutil.CreateCampaign(t, db, 10, 0, true)
+	customerIds := testutil.CreateCustomers(t, db, 2)
+
+	var hash int64 = 0x0f0f0f0f0f0f0f0f
+	// the newer bookings share the first byte of the hash, they are candidates read page by page
+	other := hash ^ 0x00ffffffffffffff
+
+	reused := domain.CustomerVoucherBook{CustomerID: customerIds[1], CampaignID: campaign.ID, Status: domain.CustomerVoucherBookStatusVerified}
+	reused.SetPhotoPerceptualHash(hash)
+	if err := db.Create(&reused).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	newer := make([]domain.CustomerVoucherBook, 6000)
+	for i := range newer {
+		newer[i] = domain.CustomerVoucherBook{CustomerID: customerIds[1], CampaignID: campaign.ID, Status: domain.CustomerVoucherBookStatusVerified}
+		newer[i].SetPhotoPerceptualHash(other)
+	}
+	if err := db.CreateInBatches(&newer, 500).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := repo.FetchSimilarPhotoHash(context.Background(), customerIds[0], hash, 6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0].ID != reused.ID {
+		t.Fatalf("similar bookings = %+v, want booking %d", result, reused.ID)
+	}
+}
@@ -0,0 +1,448 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type customerVoucherBookUseCase struct {
+	zapLogger                                 zaplogger.Logger
+	contextTimeout                            time.Duration
+	mysqlCustomerRepository                   domain.MysqlCustomerRepository
+	mysqlCustomerVoucherRepository            domain.MysqlCustomerVoucherRepository
+	mysqlCustomerVoucherBookRepository        domain.MysqlCustomerVoucherBookRepository
+	mysqlCustomerVoucherBookEventRepository   domain.MysqlCustomerVoucherBookEventRepository
+	mysqlCustomerVoucherBookAttemptRepository domain.MysqlCustomerVoucherBookAttemptRepository
+	storage                                   domain.Storage
+}
+
+func NewCustomerVoucherBookUseCase(timeout time.Duration,
+	mysqlCustomerRepository domain.MysqlCustomerRepository,
+	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository,
+	mysqlCustomerVoucherBookRepository domain.MysqlCustomerVoucherBookRepository,
+	mysqlCustomerVoucherBookEventRepository domain.MysqlCustomerVoucherBookEventRepository,
+	mysqlCustomerVoucherBookAttemptRepository domain.MysqlCustomerVoucherBookAttemptRepository,
+	storage domain.Storage,
+	zapLogger zaplogger.Logger) domain.CustomerVoucherBookUseCase {
+	return &customerVoucherBookUseCase{
+		mysqlCustomerRepository:                   mysqlCustomerRepository,
+		mysqlCustomerVoucherRepository:            mysqlCustomerVoucherRepository,
+		mysqlCustomerVoucherBookRepository:        mysqlCustomerVoucherBookRepository,
+		mysqlCustomerVoucherBookEventRepository:   mysqlCustomerVoucherBookEventRepository,
+		mysqlCustomerVoucherBookAttemptRepository: mysqlCustomerVoucherBookAttemptRepository,
+		storage:        storage,
+		contextTimeout: timeout,
+		zapLogger:      zapLogger,
+	}
+}
+
+// QUERY CUSTOMER VOUCHER BOOK
+func (r customerVoucherBookUseCase) fetchCustomerVoucherBookWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.CustomerVoucherBook, error) {
+
+	if voucherBook, err := r.mysqlCustomerVoucherBookRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"id DESC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.CustomerVoucherBook{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := voucherBook.(*[]domain.CustomerVoucherBook); !ok {
+			return []domain.CustomerVoucherBook{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// QUERY CUSTOMER VOUCHER BOOK EVENT
+func (r customerVoucherBookUseCase) fetchCustomerVoucherBookEventWithFilter(ctx context.Context, filter []string, args ...interface{}) ([]domain.CustomerVoucherBookEvent, error) {
+
+	if events, err := r.mysqlCustomerVoucherBookEventRepository.FetchWithFilter(
+		ctx,
+		1000,
+		0,
+		"id ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.CustomerVoucherBookEvent{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := events.(*[]domain.CustomerVoucherBookEvent); !ok {
+			return []domain.CustomerVoucherBookEvent{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// QUERY CUSTOMER VOUCHER BOOK ATTEMPT
+func (r customerVoucherBookUseCase) fetchCustomerVoucherBookAttemptWithFilter(ctx context.Context, filter []string, args ...interface{}) ([]domain.CustomerVoucherBookAttempt, error) {
+
+	if attempts, err := r.mysqlCustomerVoucherBookAttemptRepository.FetchWithFilter(
+		ctx,
+		1000,
+		0,
+		"id ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.CustomerVoucherBookAttempt{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := attempts.(*[]domain.CustomerVoucherBookAttempt); !ok {
+			return []domain.CustomerVoucherBookAttempt{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// CreateWithTx store a new booking with status booked and its first history event
+func (r customerVoucherBookUseCase) CreateWithTx(ctx context.Context, tx *gorm.DB, book domain.CustomerVoucherBook) (domain.CustomerVoucherBook, error) {
+	var err error
+
+	book.Status = domain.CustomerVoucherBookStatusBooked
+	book.ID, err = r.mysqlCustomerVoucherBookRepository.StoreWithTx(ctx, tx, book)
+	if err != nil {
+		return book, err
+	}
+
+	_, err = r.mysqlCustomerVoucherBookEventRepository.StoreWithTx(ctx, tx, domain.CustomerVoucherBookEvent{
+		CustomerVoucherBookID: book.ID,
+		CustomerID:            book.CustomerID,
+		ToStatus:              domain.CustomerVoucherBookStatusBooked,
+		Reason:                "voucher booked",
+	})
+	return book, err
+}
+
+// TransitionWithTx move the booking to the next status when the transition is allowed and write the history event,
+// response.ErrInvalidBookStatusTransition is returned when the transition is not allowed or the booking was changed meanwhile
+func (r customerVoucherBookUseCase) TransitionWithTx(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook, to, reason string) error {
+	if !domain.CanTransitionCustomerVoucherBook(book.Status, to) {
+		return response.ErrInvalidBookStatusTransition
+	}
+
+	if err := r.mysqlCustomerVoucherBookRepository.UpdateStatusWithTx(ctx, tx, book.ID, book.Status, to); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return response.ErrInvalidBookStatusTransition
+		}
+		return err
+	}
+
+	if _, err := r.mysqlCustomerVoucherBookEventRepository.StoreWithTx(ctx, tx, domain.CustomerVoucherBookEvent{
+		CustomerVoucherBookID: book.ID,
+		CustomerID:            book.CustomerID,
+		FromStatus:            book.Status,
+		ToStatus:              to,
+		Reason:                reason,
+	}); err != nil {
+		return err
+	}
+
+	book.Status = to
+	return nil
+}
+
+// RedeemWithTx move the booking to verified and redeem its voucher for the customer,
+// response.ErrVoucherNotAvailable is returned when the voucher was redeemed by another booking
+func (r customerVoucherBookUseCase) RedeemWithTx(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook, reason string) error {
+	if err := r.TransitionWithTx(ctx, tx, book, domain.CustomerVoucherBookStatusVerified, reason); err != nil {
+		return err
+	}
+
+	if err := r.mysqlCustomerVoucherRepository.RedeemWithTx(ctx, tx, book.CustomerVoucherID, book.CustomerID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return response.ErrVoucherNotAvailable
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleaseExpiredBookings mark bookings past their expired date as released and return their vouchers
+// to the available pool, bookings are processed in batches until none is left
+func (r customerVoucherBookUseCase) ReleaseExpiredBookings(ctx context.Context, batchSize int) (int, error) {
+	released := 0
+	skipped := 0
+
+	for {
+		books, err := r.fetchCustomerVoucherBookWithFilter(ctx, batchSize, 0,
+			[]string{"status IN ?", "expired_date < ?"},
+			[]string{domain.CustomerVoucherBookStatusBooked, domain.CustomerVoucherBookStatusExpired, domain.CustomerVoucherBookStatusLocked},
+			time.Now())
+		if err != nil {
+			return released, err
+		}
+
+		for i := range books {
+			err := r.mysqlCustomerVoucherBookRepository.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return r.releaseWithTx(ctx, tx, &books[i], "voucher returned to the pool")
+			})
+			if errors.Is(err, response.ErrInvalidBookStatusTransition) {
+				// changed by another request meanwhile
+				skipped++
+				continue
+			}
+			if err != nil {
+				return released, err
+			}
+			released++
+		}
+
+		if len(books) < batchSize {
+			break
+		}
+	}
+
+	if released > 0 || skipped > 0 {
+		r.zapLogger.Infof("release expired voucher bookings: %d released, %d skipped", released, skipped)
+	}
+	return released, nil
+}
+
+// releaseWithTx move the booking to released and return its voucher to the available pool,
+// a voucher already claimed again by another booking keeps the new reservation
+func (r customerVoucherBookUseCase) releaseWithTx(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook, reason string) error {
+	if book.Status == domain.CustomerVoucherBookStatusBooked {
+		if err := r.TransitionWithTx(ctx, tx, book, domain.CustomerVoucherBookStatusExpired, "photo verification timeout"); err != nil {
+			return err
+		}
+	}
+	if err := r.TransitionWithTx(ctx, tx, book, domain.CustomerVoucherBookStatusReleased, reason); err != nil {
+		return err
+	}
+
+	_, err := r.mysqlCustomerVoucherRepository.ReleaseReservationWithTx(ctx, tx, book.CustomerVoucherID, book.ExpiredDate)
+	return err
+}
+
+func (r customerVoucherBookUseCase) GetBookingPhoto(beegoCtx *beegoContext.Context, id int) (*domain.CustomerVoucherBookPhoto, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var book domain.CustomerVoucherBook
+	if err := r.mysqlCustomerVoucherBookRepository.SingleWithFilter(c, []string{"*"}, []string{}, []string{"id = ?"}, &book, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	photo, err := r.getPhoto(c, book.PhotoPath, book.PhotoHash)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+func (r customerVoucherBookUseCase) GetBookingAttempts(beegoCtx *beegoContext.Context, id int) ([]domain.CustomerVoucherBookAttemptResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var book domain.CustomerVoucherBook
+	if err := r.mysqlCustomerVoucherBookRepository.SingleWithFilter(c, []string{"id"}, []string{}, []string{"id = ?"}, &book, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	attempts, err := r.fetchCustomerVoucherBookAttemptWithFilter(c, []string{"customer_voucher_book_id = ?"}, book.ID)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.CustomerVoucherBookAttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		result = append(result, domain.NewCustomerVoucherBookAttemptResponse(attempt))
+	}
+	return result, nil
+}
+
+// GetAttemptPhoto photo uploaded with an attempt of the booking, rejected photos included
+func (r customerVoucherBookUseCase) GetAttemptPhoto(beegoCtx *beegoContext.Context, id, attemptId int) (*domain.CustomerVoucherBookPhoto, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var attempt domain.CustomerVoucherBookAttempt
+	if err := r.mysqlCustomerVoucherBookAttemptRepository.SingleWithFilter(c, []string{"*"}, []string{},
+		[]string{"id = ?", "customer_voucher_book_id = ?"}, &attempt, attemptId, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	photo, err := r.getPhoto(c, attempt.PhotoPath, attempt.PhotoHash)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+// getPhoto read a stored photo, gorm.ErrRecordNotFound when no photo was stored
+func (r customerVoucherBookUseCase) getPhoto(ctx context.Context, path, hash string) (*domain.CustomerVoucherBookPhoto, error) {
+	if path == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	data, err := r.storage.Get(ctx, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.CustomerVoucherBookPhoto{
+		Hash:        hash,
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+	}, nil
+}
+
+func (r customerVoucherBookUseCase) GetBookingsByCustomerId(beegoCtx *beegoContext.Context, customerId, page, limit int) (*domain.CustomerVoucherBookHistoryListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var customer domain.Customer
+	if err := r.mysqlCustomerRepository.SingleWithFilter(c, []string{"id"}, []string{}, []string{"id = ?"}, &customer, customerId); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	total, err := r.mysqlCustomerVoucherBookRepository.CountFilter(c, []string{}, &domain.CustomerVoucherBook{}, []string{"customer_id = ?"}, customerId)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	books, err := r.fetchCustomerVoucherBookWithFilter(c, limit, (page-1)*limit, []string{"customer_id = ?"}, customerId)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	bookIds := make([]int, 0, len(books))
+	for _, book := range books {
+		bookIds = append(bookIds, book.ID)
+	}
+
+	eventsByBook := make(map[int][]domain.CustomerVoucherBookEvent)
+	if len(bookIds) > 0 {
+		events, err := r.fetchCustomerVoucherBookEventWithFilter(c, []string{"customer_voucher_book_id IN ?"}, bookIds)
+		if err != nil {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+			return nil, err
+		}
+		for _, event := range events {
+			eventsByBook[event.CustomerVoucherBookID] = append(eventsByBook[event.CustomerVoucherBookID], event)
+		}
+	}
+
+	result := make([]domain.CustomerVoucherBookHistoryResponse, 0, len(books))
+	for _, book := range books {
+		result = append(result, domain.NewCustomerVoucherBookHistoryResponse(book, eventsByBook[book.ID]))
+	}
+
+	return &domain.CustomerVoucherBookHistoryListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r customerVoucherBookUseCase) GetPendingReviews(beegoCtx *beegoContext.Context, page, limit int) (*domain.CustomerVoucherBookReviewListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlCustomerVoucherBookRepository.CountFilter(c, []string{}, &domain.CustomerVoucherBook{}, []string{"status = ?"}, domain.CustomerVoucherBookStatusPendingReview)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	books, err := r.fetchCustomerVoucherBookWithFilter(c, limit, (page-1)*limit, []string{"status = ?"}, domain.CustomerVoucherBookStatusPendingReview)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.CustomerVoucherBookReviewResponse, 0, len(books))
+	for _, book := range books {
+		result = append(result, domain.NewCustomerVoucherBookReviewResponse(book))
+	}
+
+	return &domain.CustomerVoucherBookReviewListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r customerVoucherBookUseCase) ApproveReview(beegoCtx *beegoContext.Context, id int, request domain.CustomerVoucherBookReviewRequest) (*domain.CustomerVoucherBookReviewResponse, error) {
+	return r.decideReview(beegoCtx, id, func(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook) error {
+		return r.RedeemWithTx(ctx, tx, book, reviewReason("review approved", request.Note))
+	})
+}
+
+func (r customerVoucherBookUseCase) RejectReview(beegoCtx *beegoContext.Context, id int, request domain.CustomerVoucherBookReviewRequest) (*domain.CustomerVoucherBookReviewResponse, error) {
+	return r.decideReview(beegoCtx, id, func(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook) error {
+		return r.releaseWithTx(ctx, tx, book, reviewReason("review rejected", request.Note))
+	})
+}
+
+// decideReview apply the reviewer decision to a booking waiting for review inside a single transaction,
+// response.ErrInvalidBookStatusTransition is returned when the booking is not waiting for review
+func (r customerVoucherBookUseCase) decideReview(beegoCtx *beegoContext.Context, id int, decide func(ctx context.Context, tx *gorm.DB, book *domain.CustomerVoucherBook) error) (*domain.CustomerVoucherBookReviewResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var book domain.CustomerVoucherBook
+	if err := r.mysqlCustomerVoucherBookRepository.SingleWithFilter(c, []string{"*"}, []string{}, []string{"id = ?"}, &book, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	if book.Status != domain.CustomerVoucherBookStatusPendingReview {
+		return nil, response.ErrInvalidBookStatusTransition
+	}
+
+	err := r.mysqlCustomerVoucherBookRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		return decide(c, tx, &book)
+	})
+	if err != nil {
+		if !errors.Is(err, response.ErrInvalidBookStatusTransition) && !errors.Is(err, response.ErrVoucherNotAvailable) {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+
+	result := domain.NewCustomerVoucherBookReviewResponse(book)
+	return &result, nil
+}
+
+func reviewReason(decision, note string) string {
+	if note == "" {
+		return decision
+	}
+	return decision + ": " + note
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	customerUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer/usecase"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"gorm.io/gorm"
+)
+
+var defaultPhotoVerificationConfig = customerUsecase.PhotoVerificationConfig{
+	MaxAttempts:          3,
+	DuplicateMaxDistance: 5,
+	DuplicateAction:      customerUsecase.DuplicatePhotoReject,
+}
+
+// linkVoucher books a voucher of the campaign for the customer
+func linkVoucher(t *testing.T, useCases testutil.UseCases, campaignId, customerId int) domain.CustomerVoucherBook {
+	t.Helper()
+
+	ctx := testutil.NewContext(httptest.NewRequest("GET", "/api/v1/link-voucher", nil))
+	if _, err := useCases.Customer.GetVoucherByCustomerId(ctx, campaignId, customerId); err != nil {
+		t.Fatalf("link voucher of customer %d: %v", customerId, err)
+	}
+
+	var book domain.CustomerVoucherBook
+	if err := useCases.DB.Where("campaign_id = ? AND customer_id = ?", campaignId, customerId).Order("id DESC").First(&book).Error; err != nil {
+		t.Fatal(err)
+	}
+	return book
+}
+
+// expireBooking moves the booking and the reservation of its voucher into the past
+func expireBooking(t *testing.T, db *gorm.DB, book *domain.CustomerVoucherBook) {
+	t.Helper()
+
+	expiredDate := time.Now().Add(-time.Minute)
+	if err := db.Model(&domain.CustomerVoucherBook{}).Where("id = ?", book.ID).Update("expired_date", expiredDate).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&domain.CustomerVoucher{}).Where("id = ?", book.CustomerVoucherID).Update("reserved_until", expiredDate).Error; err != nil {
+		t.Fatal(err)
+	}
+	book.ExpiredDate = expiredDate
+}
+
+func TestReleaseExpiredBookingsKeepsNewReservation(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	useCases := testutil.NewUseCases(t, db, defaultPhotoVerificationConfig)
+	campaign := testutil.CreateCampaign(t, db, 10, 1, true)
+	customerIds := testutil.CreateCustomers(t, db, 2)
+
+	expired := linkVoucher(t, useCases, campaign.ID, customerIds[0])
+	expireBooking(t, db, &expired)
+
+	// the voucher of the expired booking is claimed again before the release job runs
+	claimed := linkVoucher(t, useCases, campaign.ID, customerIds[1])
+	if claimed.CustomerVoucherID != expired.CustomerVoucherID {
+		t.Fatalf("claimed voucher %d, want %d", claimed.CustomerVoucherID, expired.CustomerVoucherID)
+	}
+
+	released, err := useCases.CustomerVoucherBook.ReleaseExpiredBookings(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Errorf("released = %d, want 1", released)
+	}
+
+	var voucher domain.CustomerVoucher
+	if err := db.First(&voucher, claimed.CustomerVoucherID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if voucher.ReservedUntil == nil || !voucher.ReservedUntil.Equal(claimed.ExpiredDate) {
+		t.Errorf("reserved until = %v, want %v", voucher.ReservedUntil, claimed.ExpiredDate)
+	}
+}
+
+func TestReleaseExpiredBookingsReturnsVoucher(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	useCases := testutil.NewUseCases(t, db, defaultPhotoVerificationConfig)
+	campaign := testutil.CreateCampaign(t, db, 10, 1, true)
+	customerIds := testutil.CreateCustomers(t, db, 1)
+
+	expired := linkVoucher(t, useCases, campaign.ID, customerIds[0])
+	expireBooking(t, db, &expired)
+
+	released, err := useCases.CustomerVoucherBook.ReleaseExpiredBookings(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Errorf("released = %d, want 1", released)
+	}
+
+	var voucher domain.CustomerVoucher
+	if err := db.First(&voucher, expired.CustomerVoucherID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if voucher.ReservedUntil != nil {
+		t.Errorf("reserved until = %v, want released", voucher.ReservedUntil)
+	}
+}
+
+func TestApproveReviewAfterReservationExpired(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	useCases := testutil.NewUseCases(t, db, defaultPhotoVerificationConfig)
+	campaign := testutil.CreateCampaign(t, db, 10, 1, true)
+	customerIds := testutil.CreateCustomers(t, db, 2)
+
+	reviewed := linkVoucher(t, useCases, campaign.ID, customerIds[0])
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/verify-photo", nil))
+	result, err := useCases.Customer.VerifyPhotoCustomer(ctx, campaign.ID, customerIds[0], testutil.NewFileHeader(t, "photo.png", testutil.NewPhoto(t, 0.2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != domain.CustomerVoucherBookStatusPendingReview {
+		t.Fatalf("status = %s, want %s", result.Status, domain.CustomerVoucherBookStatusPendingReview)
+	}
+
+	// the review takes longer than the reservation of the voucher
+	expireBooking(t, db, &reviewed)
+	if _, err := useCases.CustomerVoucherBook.ReleaseExpiredBookings(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = testutil.NewContext(httptest.NewRequest("GET", "/api/v1/link-voucher", nil))
+	if _, err := useCases.Customer.GetVoucherByCustomerId(ctx, campaign.ID, customerIds[1]); !errors.Is(err, response.ErrVoucherNotAvailable) {
+		t.Fatalf("link voucher of another customer error = %v, want %v", err, response.ErrVoucherNotAvailable)
+	}
+
+	ctx = testutil.NewContext(httptest.NewRequest("POST", "/api/v1/bookings/review", nil))
+	if _, err := useCases.CustomerVoucherBook.ApproveReview(ctx, reviewed.ID, domain.CustomerVoucherBookReviewRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var voucher domain.CustomerVoucher
+	if err := db.First(&voucher, reviewed.CustomerVoucherID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !voucher.IsRedeem || voucher.CustomerID == nil || *voucher.CustomerID != customerIds[0] {
+		t.Errorf("voucher = %+v, want redeemed by customer %d", voucher, customerIds[0])
+	}
+
+	var books int64
+	if err := db.Model(&domain.CustomerVoucherBook{}).Where("customer_voucher_id = ?", reviewed.CustomerVoucherID).Count(&books).Error; err != nil {
+		t.Fatal(err)
+	}
+	if books != 1 {
+		t.Errorf("bookings of the voucher = %d, want 1", books)
+	}
+}
+
+func TestGetAttemptPhotoOfRejectedPhoto(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	useCases := testutil.NewUseCases(t, db, defaultPhotoVerificationConfig)
+	campaign := testutil.CreateCampaign(t, db, 10, 1, true)
+	customerIds := testutil.CreateCustomers(t, db, 1)
+
+	book := linkVoucher(t, useCases, campaign.ID, customerIds[0])
+	photo := testutil.NewPhoto(t, 0)
+	ctx := testutil.NewContext(httptest.NewRequest("POST", "/api/v1/verify-photo", nil))
+	if _, err := useCases.Customer.VerifyPhotoCustomer(ctx, campaign.ID, customerIds[0], testutil.NewFileHeader(t, "photo.png", photo)); !errors.Is(err, response.ErrCustomerVerifyImage) {
+		t.Fatalf("verify photo error = %v, want %v", err, response.ErrCustomerVerifyImage)
+	}
+
+	ctx = testutil.NewContext(httptest.NewRequest("GET", "/api/v1/admin/bookings/attempts", nil))
+	attempts, err := useCases.CustomerVoucherBook.GetBookingAttempts(ctx, book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 1 || attempts[0].Outcome != domain.VerificationAttemptRejected || attempts[0].PhotoURL == "" {
+		t.Fatalf("attempts = %+v, want one rejected attempt with its photo", attempts)
+	}
+
+	ctx = testutil.NewContext(httptest.NewRequest("GET", attempts[0].PhotoURL, nil))
+	result, err := useCases.CustomerVoucherBook.GetAttemptPhoto(ctx, book.ID, attempts[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result.Data, photo) || result.ContentType != "image/png" {
+		t.Errorf("attempt photo = %s of %d bytes, want the rejected png of %d bytes", result.ContentType, len(result.Data), len(photo))
+	}
+
+	// the attempt belongs to another booking
+	ctx = testutil.NewContext(httptest.NewRequest("GET", attempts[0].PhotoURL, nil))
+	if _, err := useCases.CustomerVoucherBook.GetAttemptPhoto(ctx, book.ID+1, attempts[0].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("attempt photo of another booking error = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlCustomerVoucherBookAttemptRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlCustomerVoucherBookAttemptRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlCustomerVoucherBookAttemptRepository {
+	return &mysqlCustomerVoucherBookAttemptRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) CountFilterWithTx(ctx context.Context, tx *gorm.DB, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := tx.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) Store(ctx context.Context, data domain.CustomerVoucherBookAttempt) (domain.CustomerVoucherBookAttempt, error) {
+
+	err := c.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+func (c mysqlCustomerVoucherBookAttemptRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.CustomerVoucherBookAttempt) (int, error) {
+
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlCustomerVoucherBookEventRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlCustomerVoucherBookEventRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlCustomerVoucherBookEventRepository {
+	return &mysqlCustomerVoucherBookEventRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlCustomerVoucherBookEventRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlCustomerVoucherBookEventRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlCustomerVoucherBookEventRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c mysqlCustomerVoucherBookEventRepository) Store(ctx context.Context, data domain.CustomerVoucherBookEvent) (domain.CustomerVoucherBookEvent, error) {
+
+	err := c.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+func (c mysqlCustomerVoucherBookEventRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.CustomerVoucherBookEvent) (int, error) {
+
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"gorm.io/gorm"
+)
+
+// api key statuses, derived from the revoked and expired dates
+const (
+	ApiKeyStatusActive  = "active"
+	ApiKeyStatusExpired = "expired"
+	ApiKeyStatusRevoked = "revoked"
+)
+
+// ApiKeyScopeSeparator separator of the scopes stored in a single column
+const ApiKeyScopeSeparator = "|"
+
+// ApiKey key of a partner integration. Only the sha256 hash of the key is stored, the key itself
+// is returned once when it is created or rotated
+type ApiKey struct {
+	ID    int    `gorm:"column:id;primarykey;autoIncrement:true"`
+	Owner string `gorm:"type:varchar(255);column:owner"`
+	// Prefix first characters of the key, identify the key without storing it
+	Prefix  string `gorm:"type:varchar(16);column:prefix"`
+	KeyHash string `gorm:"type:varchar(64);column:key_hash;uniqueIndex:idx_api_keys_key_hash"`
+	// Scopes scopes granted to the key separated by ApiKeyScopeSeparator
+	Scopes     string     `gorm:"type:varchar(1000);column:scopes"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r ApiKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList scopes granted to the key
+func (r ApiKey) ScopeList() []string {
+	if r.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(r.Scopes, ApiKeyScopeSeparator)
+}
+
+// Status status of the key at the given time
+func (r ApiKey) Status(now time.Time) string {
+	if r.RevokedAt != nil {
+		return ApiKeyStatusRevoked
+	}
+	if r.ExpiresAt != nil && !now.Before(*r.ExpiresAt) {
+		return ApiKeyStatusExpired
+	}
+	return ApiKeyStatusActive
+}
+
+// MysqlApiKeyRepository Repository Interface
+type MysqlApiKeyRepository interface {
+	CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
+	Store(ctx context.Context, data ApiKey) (ApiKey, error)
+	DB() *gorm.DB
+}
+
+// ApiKeyUseCase UseCase Interface
+type ApiKeyUseCase interface {
+	GetApiKeys(beegoCtx *beegoContext.Context, page, limit int) (*ApiKeyListResponse, error)
+	CreateApiKey(beegoCtx *beegoContext.Context, request ApiKeyRequest) (*ApiKeySecretResponse, error)
+	RotateApiKey(beegoCtx *beegoContext.Context, id int) (*ApiKeySecretResponse, error)
+	RevokeApiKey(beegoCtx *beegoContext.Context, id int) error
+	// Authenticate scopes of an active key
+	Authenticate(ctx context.Context, key string) ([]string, error)
+}
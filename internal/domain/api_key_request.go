@@ -0,0 +1,10 @@
+package domain
+
+type ApiKeyRequest struct {
+	// Owner partner the key is issued to
+	Owner string `json:"owner" validate:"required,max=255"`
+	// Scopes permissions granted to the key besides the permissions of its roles, e.g. purchase_transactions:write
+	Scopes []string `json:"scopes" validate:"dive,required"`
+	// ExpiresAt the key never expires when empty
+	ExpiresAt string `json:"expires_at" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+}
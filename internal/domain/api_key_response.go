@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+)
+
+type ApiKeyResponse struct {
+	ID         int      `json:"id"`
+	Owner      string   `json:"owner"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	Status     string   `json:"status"`
+	ExpiresAt  string   `json:"expires_at"`
+	RevokedAt  string   `json:"revoked_at"`
+	LastUsedAt string   `json:"last_used_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// ApiKeySecretResponse key created or rotated, Key is not returned again
+type ApiKeySecretResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+type ApiKeyListResponse struct {
+	Items      []ApiKeyResponse   `json:"items"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+func NewApiKeyResponse(apiKey ApiKey, now time.Time) ApiKeyResponse {
+	return ApiKeyResponse{
+		ID:         apiKey.ID,
+		Owner:      apiKey.Owner,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.ScopeList(),
+		Status:     apiKey.Status(now),
+		ExpiresAt:  formatOptionalTime(apiKey.ExpiresAt),
+		RevokedAt:  formatOptionalTime(apiKey.RevokedAt),
+		LastUsedAt: formatOptionalTime(apiKey.LastUsedAt),
+		CreatedAt:  apiKey.CreatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(helper.DateTimeFormatDefault)
+}
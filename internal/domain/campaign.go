@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"gorm.io/gorm"
+)
+
+type Campaign struct {
+	ID                        int       `gorm:"column:id;primarykey;autoIncrement:true"`
+	Name                      string    `gorm:"type:varchar(255);column:name"`
+	StartDate                 time.Time `gorm:"column:start_date"`
+	EndDate                   time.Time `gorm:"column:end_date"`
+	Budget                    int       `gorm:"column:budget"`
+	PerCustomerLimit          int       `gorm:"column:per_customer_limit"`
+	PhotoVerificationRequired bool      `gorm:"bool;column:photo_verification_required"`
+	// RulesConfigured the rule set of the campaign was saved, also when it is empty. Campaigns never configured
+	// are evaluated with the default rules
+	RulesConfigured bool `gorm:"bool;column:rules_configured"`
+	// Currency purchases are converted to before the spend rules are evaluated
+	Currency  string         `gorm:"type:varchar(3);column:currency;default:USD"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+// TableName name of table
+func (r Campaign) TableName() string {
+	return "campaigns"
+}
+
+// IsActive campaign is running at the given time
+func (r Campaign) IsActive(now time.Time) bool {
+	return !now.Before(r.StartDate) && !now.After(r.EndDate)
+}
+
+// CampaignUseCase UseCase Interface
+type CampaignUseCase interface {
+	GetCampaigns(beegoCtx *beegoContext.Context, page, limit int) (*CampaignListResponse, error)
+	GetCampaignByID(beegoCtx *beegoContext.Context, id int) (*CampaignResponse, error)
+	CreateCampaign(beegoCtx *beegoContext.Context, request CampaignRequest) (*CampaignResponse, error)
+	UpdateCampaign(beegoCtx *beegoContext.Context, id int, request CampaignRequest) (*CampaignResponse, error)
+	DeleteCampaign(beegoCtx *beegoContext.Context, id int) error
+}
+
+// MysqlCampaignRepository Repository Interface
+type MysqlCampaignRepository interface {
+	CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	Update(ctx context.Context, data Campaign) error
+	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
+	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
+	Store(ctx context.Context, data Campaign) (Campaign, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data Campaign) (int, error)
+	LockWithTx(ctx context.Context, tx *gorm.DB, id int) (Campaign, error)
+	Delete(ctx context.Context, id int) (int, error)
+	SoftDelete(ctx context.Context, id int) (int, error)
+	DB() *gorm.DB
+}
@@ -0,0 +1,23 @@
+package domain
+
+type CampaignRequest struct {
+	Name                      string `json:"name" validate:"required,max=255"`
+	StartDate                 string `json:"start_date" validate:"required,datetime=2006-01-02 15:04:05"`
+	EndDate                   string `json:"end_date" validate:"required,datetime=2006-01-02 15:04:05"`
+	Budget                    int    `json:"budget" validate:"required,min=1"`
+	PerCustomerLimit          int    `json:"per_customer_limit" validate:"required,min=1"`
+	PhotoVerificationRequired bool   `json:"photo_verification_required"`
+	// Currency ISO 4217 code the spend rules are evaluated in, USD when empty
+	Currency string `json:"currency" validate:"omitempty,iso4217,currency"`
+	// Rules replaces the eligibility rule set of the campaign. An empty list saves a campaign without rules,
+	// the default rules of the config apply while rules were never sent
+	Rules []CampaignRuleRequest `json:"rules" validate:"omitempty,dive"`
+}
+
+type CampaignRuleRequest struct {
+	RuleType   string  `json:"rule_type" validate:"required,enum=purchase_count-spend_sum-customer_age-account_age-previous_redemption"`
+	Threshold  float64 `json:"threshold" validate:"min=0"`
+	WindowDays int     `json:"window_days" validate:"min=0"`
+	// Currency ISO 4217 code of a spend_sum threshold, the campaign currency when empty
+	Currency string `json:"currency" validate:"omitempty,iso4217,currency"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "github.com/radyatamaa/technical-test-aichat/pkg/helper"
+
+type CampaignResponse struct {
+	ID                        int    `json:"id"`
+	Name                      string `json:"name"`
+	StartDate                 string `json:"start_date"`
+	EndDate                   string `json:"end_date"`
+	Budget                    int    `json:"budget"`
+	PerCustomerLimit          int    `json:"per_customer_limit"`
+	PhotoVerificationRequired bool   `json:"photo_verification_required"`
+	Currency                  string `json:"currency"`
+	// DefaultRules the campaign is evaluated with the default rules of the config, it has no rule set of its own
+	DefaultRules bool `json:"default_rules"`
+
+	Rules []CampaignRuleResponse `json:"rules,omitempty"`
+}
+
+type CampaignRuleResponse struct {
+	RuleType   string  `json:"rule_type"`
+	Threshold  float64 `json:"threshold"`
+	WindowDays int     `json:"window_days"`
+	Currency   string  `json:"currency,omitempty"`
+}
+
+type CampaignListResponse struct {
+	Items      []CampaignResponse `json:"items"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+func NewCampaignResponse(campaign Campaign, rules ...CampaignRule) CampaignResponse {
+	result := CampaignResponse{
+		ID:                        campaign.ID,
+		Name:                      campaign.Name,
+		StartDate:                 campaign.StartDate.Format(helper.DateTimeFormatDefault),
+		EndDate:                   campaign.EndDate.Format(helper.DateTimeFormatDefault),
+		Budget:                    campaign.Budget,
+		PerCustomerLimit:          campaign.PerCustomerLimit,
+		PhotoVerificationRequired: campaign.PhotoVerificationRequired,
+		Currency:                  campaign.Currency,
+		DefaultRules:              !campaign.RulesConfigured,
+	}
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, CampaignRuleResponse{
+			RuleType:   rule.RuleType,
+			Threshold:  rule.Threshold,
+			WindowDays: rule.WindowDays,
+			Currency:   rule.Currency,
+		})
+	}
+	return result
+}
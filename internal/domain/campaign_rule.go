@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EligibilityRulePurchaseCount      = "purchase_count"
+	EligibilityRuleSpendSum           = "spend_sum"
+	EligibilityRuleCustomerAge        = "customer_age"
+	EligibilityRuleAccountAge         = "account_age"
+	EligibilityRulePreviousRedemption = "previous_redemption"
+)
+
+// CampaignRule eligibility rule configuration of a campaign
+type CampaignRule struct {
+	ID         int      `gorm:"column:id;primarykey;autoIncrement:true"`
+	CampaignID int      `gorm:"type:bigint(20);column:campaign_id;index"`
+	Campaign   Campaign `gorm:"foreignkey:CampaignID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	RuleType   string   `gorm:"type:varchar(50);column:rule_type"`
+	Threshold  float64  `gorm:"type:decimal(12,2);column:threshold"`
+	WindowDays int      `gorm:"column:window_days"`
+	// Currency of the threshold of a spend rule, empty is the campaign currency
+	Currency  string    `gorm:"type:varchar(3);column:currency"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r CampaignRule) TableName() string {
+	return "campaign_rules"
+}
+
+// MysqlCampaignRuleRepository Repository Interface
+type MysqlCampaignRuleRepository interface {
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	Update(ctx context.Context, data CampaignRule) error
+	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
+	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
+	Store(ctx context.Context, data CampaignRule) (CampaignRule, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data CampaignRule) (int, error)
+	DeleteByCampaignWithTx(ctx context.Context, tx *gorm.DB, campaignId int) error
+	Delete(ctx context.Context, id int) (int, error)
+	SoftDelete(ctx context.Context, id int) (int, error)
+	DB() *gorm.DB
+}
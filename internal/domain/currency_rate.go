@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"gorm.io/gorm"
+)
+
+// CurrencyRate exchange rate of a currency pair, an amount in BaseCurrency times Rate is the amount in QuoteCurrency.
+// The pair is also used the other way around when only the opposite pair is configured
+type CurrencyRate struct {
+	ID            int        `gorm:"column:id;primarykey;autoIncrement:true"`
+	BaseCurrency  string     `gorm:"type:varchar(3);column:base_currency;uniqueIndex:idx_currency_rates_pair"`
+	QuoteCurrency string     `gorm:"type:varchar(3);column:quote_currency;uniqueIndex:idx_currency_rates_pair"`
+	Rate          money.Rate `gorm:"type:decimal(18,8);column:rate"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r CurrencyRate) TableName() string {
+	return "currency_rates"
+}
+
+// MysqlCurrencyRateRepository Repository Interface
+type MysqlCurrencyRateRepository interface {
+	CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	Upsert(ctx context.Context, data CurrencyRate) (CurrencyRate, error)
+	Delete(ctx context.Context, id int) (int, error)
+	DB() *gorm.DB
+}
+
+// CurrencyRateUseCase UseCase Interface
+type CurrencyRateUseCase interface {
+	GetCurrencyRates(beegoCtx *beegoContext.Context, page, limit int) (*CurrencyRateListResponse, error)
+	SaveCurrencyRate(beegoCtx *beegoContext.Context, request CurrencyRateRequest) (*CurrencyRateResponse, error)
+	DeleteCurrencyRate(beegoCtx *beegoContext.Context, id int) error
+	CurrencyConverter
+}
+
+// CurrencyConverter converts amounts between currencies with the configured rates
+type CurrencyConverter interface {
+	Convert(ctx context.Context, amount money.Amount, from, to string) (money.Amount, error)
+}
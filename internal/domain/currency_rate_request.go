@@ -0,0 +1,10 @@
+package domain
+
+import "github.com/radyatamaa/technical-test-aichat/pkg/money"
+
+type CurrencyRateRequest struct {
+	BaseCurrency  string `json:"base_currency" validate:"required,iso4217,currency"`
+	QuoteCurrency string `json:"quote_currency" validate:"required,iso4217,currency,nefield=BaseCurrency"`
+	// Rate amount in quote currency of one unit of the base currency, up to 8 decimals
+	Rate money.Rate `json:"rate" validate:"gt=0"`
+}
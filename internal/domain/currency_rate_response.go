@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+)
+
+type CurrencyRateResponse struct {
+	ID            int        `json:"id"`
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          money.Rate `json:"rate"`
+	UpdatedAt     string     `json:"updated_at"`
+}
+
+type CurrencyRateListResponse struct {
+	Items      []CurrencyRateResponse `json:"items"`
+	Pagination PaginationResponse     `json:"pagination"`
+}
+
+func NewCurrencyRateResponse(rate CurrencyRate) CurrencyRateResponse {
+	return CurrencyRateResponse{
+		ID:            rate.ID,
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		UpdatedAt:     rate.UpdatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
@@ -2,11 +2,11 @@ package domain
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 	"time"
 
 	beegoContext "github.com/beego/beego/v2/server/web/context"
-	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
 	"gorm.io/gorm"
 )
 
@@ -20,6 +20,7 @@ type Customer struct {
 	Email         string    `gorm:"type:varchar(255);column:email"`
 	CreatedAt     time.Time `gorm:"column:created_at"`
 	UpdatedAt     time.Time `gorm:"column:updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 // TableName name of table
@@ -29,119 +30,29 @@ func (r Customer) TableName() string {
 
 // CustomerUseCase UseCase Interface
 type CustomerUseCase interface {
-	VerifyPhotoCustomer(beegoCtx *beegoContext.Context, customerId int, file *multipart.FileHeader) (*CustomerVerifyPhotoResponse, error)
-	GetVoucherByCustomerId(beegoCtx *beegoContext.Context, customerId int) (*CustomerVoucherBookResponse, error)
+	VerifyPhotoCustomer(beegoCtx *beegoContext.Context, campaignId, customerId int, file *multipart.FileHeader) (*CustomerVerifyPhotoResponse, error)
+	GetVoucherByCustomerId(beegoCtx *beegoContext.Context, campaignId, customerId int) (*CustomerVoucherBookResponse, error)
+	GetEligibilityByCustomerId(beegoCtx *beegoContext.Context, campaignId, customerId int) (*CustomerEligibilityResponse, error)
+	GetCustomers(beegoCtx *beegoContext.Context, page, limit int) (*CustomerListResponse, error)
+	GetCustomerByID(beegoCtx *beegoContext.Context, id int) (*CustomerResponse, error)
+	CreateCustomer(beegoCtx *beegoContext.Context, request CustomerRequest) (*CustomerResponse, error)
+	UpdateCustomer(beegoCtx *beegoContext.Context, id int, request CustomerUpdateRequest) (*CustomerResponse, error)
+	DeleteCustomer(beegoCtx *beegoContext.Context, id int) error
+	ExportCustomers(ctx context.Context, writer io.Writer) (int, error)
 }
 
 // MysqlCustomerRepository Repository Interface
 type MysqlCustomerRepository interface {
 	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
 	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	CountFilter(ctx context.Context, associate []string, model interface{}, filter []string, args ...interface{}) (int, error)
 	Update(ctx context.Context, data Customer) error
 	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
 	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
 	Store(ctx context.Context, data Customer) (Customer, error)
 	StoreWithTx(ctx context.Context, tx *gorm.DB, data Customer) (int, error)
+	LockWithTx(ctx context.Context, tx *gorm.DB, id int) (Customer, error)
 	Delete(ctx context.Context, id int) (int, error)
 	SoftDelete(ctx context.Context, id int) (int, error)
 	DB() *gorm.DB
 }
-
-func SeederData(db *gorm.DB) {
-	dataCustomer := make([]Customer, 1500)
-	for i := range dataCustomer {
-		dataCustomer[i] = Customer{
-			ID:            0,
-			FirstName:     "Customer First Name" + helper.IntToString(i+1),
-			LastName:      "Customer last Name" + helper.IntToString(i+1),
-			Gender:        "Laki-Laki",
-			DateOfBirth:   "2022-07-13",
-			ContactNumber: "081572345351",
-			Email:         helper.RandomString(10),
-		}
-		db.Create(&dataCustomer[i])
-
-
-		if dataCustomer[i].ID == 10 {
-			//SEEDER FOR NEGATIVE CASE 30 DAYS PURCHASE TRANSACTION < 3
-			datapcsTransaction := []PurchaseTransaction{
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    100,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-			}
-			db.Create(&datapcsTransaction)
-		}else if dataCustomer[i].ID == 11 {
-			//SEEDER FOR NEGATIVE CASE TOTAL TRANSACTION < 100$
-			datapcsTransaction := []PurchaseTransaction{
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    50,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    1,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    10,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-			}
-			db.Create(&datapcsTransaction)
-		}else {
-
-			datapcsTransaction := []PurchaseTransaction{
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					//Customer:      Customer{},
-					TotalSpent:    100,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    100,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-				PurchaseTransaction{
-					ID:            0,
-					CustomerID:    dataCustomer[i].ID,
-					TotalSpent:    100,
-					TotalSaving:   50,
-					TransactionAt: time.Now(),
-				},
-			}
-
-			db.Create(&datapcsTransaction)
-		}
-
-	}
-
-
-	dataCustomerVoucher := make([]CustomerVoucher, 1000)
-	for i := range dataCustomerVoucher {
-		dataCustomerVoucher[i] = CustomerVoucher{
-			ID:          0,
-			//CustomerID:  sql.NullInt32{},
-			VoucherCode: helper.RandomString(10),
-			IsRedeem:    false,
-		}
-	}
-
-	db.Create(dataCustomerVoucher)
-}
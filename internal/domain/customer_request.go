@@ -0,0 +1,21 @@
+package domain
+
+type CustomerRequest struct {
+	FirstName     string `json:"first_name" validate:"required,max=255"`
+	LastName      string `json:"last_name" validate:"max=255"`
+	Gender        string `json:"gender" validate:"required,enum=male-female"`
+	DateOfBirth   string `json:"date_of_birth" validate:"required,date_only"`
+	ContactNumber string `json:"contact_number" validate:"required,max=50"`
+	Email         string `json:"email" validate:"required,email,max=255,unique_store=email:customers"`
+}
+
+type CustomerUpdateRequest struct {
+	// ID customer being updated, set from the path so the email is unique among the other customers
+	ID            int    `json:"-"`
+	FirstName     string `json:"first_name" validate:"required,max=255"`
+	LastName      string `json:"last_name" validate:"max=255"`
+	Gender        string `json:"gender" validate:"required,enum=male-female"`
+	DateOfBirth   string `json:"date_of_birth" validate:"required,date_only"`
+	ContactNumber string `json:"contact_number" validate:"required,max=50"`
+	Email         string `json:"email" validate:"required,email,max=255,unique_update=ID:customers:email:id"`
+}
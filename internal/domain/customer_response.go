@@ -1,9 +1,109 @@
 package domain
 
+import "github.com/radyatamaa/technical-test-aichat/pkg/helper"
+
+type CustomerResponse struct {
+	ID            int    `json:"id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Gender        string `json:"gender"`
+	DateOfBirth   string `json:"date_of_birth"`
+	ContactNumber string `json:"contact_number"`
+	Email         string `json:"email"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type CustomerListResponse struct {
+	Items      []CustomerResponse `json:"items"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+func NewCustomerResponse(customer Customer) CustomerResponse {
+	// date column is read back as a timestamp string by the driver
+	dateOfBirth := customer.DateOfBirth
+	if len(dateOfBirth) > len(helper.DateFormatDefault) {
+		dateOfBirth = dateOfBirth[:len(helper.DateFormatDefault)]
+	}
+
+	return CustomerResponse{
+		ID:            customer.ID,
+		FirstName:     customer.FirstName,
+		LastName:      customer.LastName,
+		Gender:        customer.Gender,
+		DateOfBirth:   dateOfBirth,
+		ContactNumber: customer.ContactNumber,
+		Email:         customer.Email,
+		CreatedAt:     customer.CreatedAt.Format(helper.DateTimeFormatDefault),
+		UpdatedAt:     customer.UpdatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
+
 type CustomerVoucherBookResponse struct {
-	Expired string `json:"expired"`
+	Expired     string `json:"expired"`
+	VoucherCode string `json:"voucher_code,omitempty"`
 }
 
 type CustomerVerifyPhotoResponse struct {
-	VoucherCode string `json:"voucher_code"`
+	VoucherCode       string `json:"voucher_code,omitempty"`
+	Status            string `json:"status,omitempty"`
+	Attempts          int    `json:"attempts"`
+	MaxAttempts       int    `json:"max_attempts"`
+	RemainingAttempts int    `json:"remaining_attempts"`
+}
+
+func NewCustomerVerifyPhotoResponse(attempts, maxAttempts int) *CustomerVerifyPhotoResponse {
+	remaining := maxAttempts - attempts
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &CustomerVerifyPhotoResponse{
+		Attempts:          attempts,
+		MaxAttempts:       maxAttempts,
+		RemainingAttempts: remaining,
+	}
+}
+
+type CustomerEligibilityResponse struct {
+	CustomerID int                                `json:"customer_id"`
+	CampaignID int                                `json:"campaign_id"`
+	Eligible   bool                               `json:"eligible"`
+	Checks     []CustomerEligibilityCheckResponse `json:"checks"`
+}
+
+type CustomerEligibilityCheckResponse struct {
+	Check       string  `json:"check"`
+	Description string  `json:"description"`
+	Passed      bool    `json:"passed"`
+	Value       float64 `json:"value"`
+	Threshold   float64 `json:"threshold"`
+	WindowDays  int     `json:"window_days,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+	Code        string  `json:"code,omitempty"`
+	Message     string  `json:"message,omitempty"`
+}
+
+// NewCustomerEligibilityResponse report of every check, descriptions and messages are filled in by the handler language
+func NewCustomerEligibilityResponse(customerId, campaignId int, results []EligibilityResult) *CustomerEligibilityResponse {
+	report := &CustomerEligibilityResponse{
+		CustomerID: customerId,
+		CampaignID: campaignId,
+		Eligible:   true,
+		Checks:     make([]CustomerEligibilityCheckResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		if !result.Passed {
+			report.Eligible = false
+		}
+		report.Checks = append(report.Checks, CustomerEligibilityCheckResponse{
+			Check:      result.Rule,
+			Passed:     result.Passed,
+			Value:      result.Value,
+			Threshold:  result.Threshold,
+			WindowDays: result.WindowDays,
+			Currency:   result.Currency,
+			Code:       result.ErrorCode,
+		})
+	}
+	return report
 }
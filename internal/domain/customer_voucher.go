@@ -2,6 +2,9 @@ package domain
 
 import (
 	"context"
+	"io"
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -9,8 +12,11 @@ type CustomerVoucher struct {
 	ID        int            `gorm:"column:id;primarykey;autoIncrement:true"`
 	CustomerID  *int `gorm:"type:bigint(20);column:customer_id"`
 	Customer               Customer       `gorm:"foreignkey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	CampaignID  int `gorm:"type:bigint(20);column:campaign_id;index"`
+	Campaign               Campaign       `gorm:"foreignkey:CampaignID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
 	VoucherCode string `gorm:"type:varchar(255);column:voucher_code"`
 	IsRedeem bool `gorm:"bool;column:is_redeem"`
+	ReservedUntil *time.Time `gorm:"column:reserved_until;index"`
 }
 
 
@@ -21,8 +27,24 @@ func (r CustomerVoucher) TableName() string {
 	return "customer_voucher"
 }
 
+// CustomerVoucherUseCase UseCase Interface
+type CustomerVoucherUseCase interface {
+	GenerateVouchers(ctx context.Context, campaignId, count, length int) (int, error)
+	ImportVouchers(ctx context.Context, campaignId int, file io.Reader) (*CustomerVoucherImportResult, error)
+}
+
+// CustomerVoucherImportResult vouchers of an import file, codes already stored are counted as duplicates
+// and rows that cannot be stored are described in Errors
+type CustomerVoucherImportResult struct {
+	Created    int
+	Duplicates int
+	Errors     []string
+}
+
 // MysqlCustomerVoucherRepository Repository Interface
 type MysqlCustomerVoucherRepository interface {
+	CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
+	CountFilterWithTx(ctx context.Context, tx *gorm.DB, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
 	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
 	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
 	Update(ctx context.Context, data CustomerVoucher) error
@@ -30,6 +52,12 @@ type MysqlCustomerVoucherRepository interface {
 	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
 	Store(ctx context.Context, data CustomerVoucher) (CustomerVoucher, error)
 	StoreWithTx(ctx context.Context, tx *gorm.DB, data CustomerVoucher) (int, error)
+	ClaimAvailableWithTx(ctx context.Context, tx *gorm.DB, campaignId int, now, reservedUntil time.Time) (CustomerVoucher, error)
+	// ReleaseReservationWithTx clear a reservation of the voucher ending at or before reservedUntil, false is returned
+	// when the voucher was reserved again by another booking
+	ReleaseReservationWithTx(ctx context.Context, tx *gorm.DB, id int, reservedUntil time.Time) (bool, error)
+	// RedeemWithTx redeem the voucher for the customer, gorm.ErrRecordNotFound is returned when it was already redeemed
+	RedeemWithTx(ctx context.Context, tx *gorm.DB, id, customerId int) error
 	Delete(ctx context.Context, id int) (int, error)
 	SoftDelete(ctx context.Context, id int) (int, error)
 	DB() *gorm.DB
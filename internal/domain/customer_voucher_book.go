@@ -2,17 +2,81 @@ package domain
 
 import (
 	"context"
-	"gorm.io/gorm"
 	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagehash"
+	"gorm.io/gorm"
+)
+
+// lifecycle status of a voucher booking
+const (
+	CustomerVoucherBookStatusBooked   = "booked"
+	CustomerVoucherBookStatusVerified = "verified"
+	CustomerVoucherBookStatusExpired  = "expired"
+	CustomerVoucherBookStatusReleased = "released"
+	CustomerVoucherBookStatusLocked   = "locked"
+	// CustomerVoucherBookStatusPendingReview photo verification was inconclusive and waits for a reviewer
+	CustomerVoucherBookStatusPendingReview = "pending_review"
 )
 
+// customerVoucherBookTransitions allowed next status of every booking status
+var customerVoucherBookTransitions = map[string][]string{
+	CustomerVoucherBookStatusBooked: {
+		CustomerVoucherBookStatusVerified,
+		CustomerVoucherBookStatusExpired,
+		CustomerVoucherBookStatusReleased,
+		CustomerVoucherBookStatusLocked,
+		CustomerVoucherBookStatusPendingReview,
+	},
+	CustomerVoucherBookStatusPendingReview: {
+		CustomerVoucherBookStatusVerified,
+		CustomerVoucherBookStatusReleased,
+	},
+	CustomerVoucherBookStatusExpired: {
+		CustomerVoucherBookStatusReleased,
+	},
+	CustomerVoucherBookStatusLocked: {
+		CustomerVoucherBookStatusReleased,
+	},
+}
+
+// CanTransitionCustomerVoucherBook booking may move from status to the next status
+func CanTransitionCustomerVoucherBook(from, to string) bool {
+	for _, next := range customerVoucherBookTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type CustomerVoucherBook struct {
 	ID        int            `gorm:"column:id;primarykey;autoIncrement:true"`
 	CustomerID  int `gorm:"type:bigint(20);column:customer_id"`
 	Customer               Customer       `gorm:"foreignkey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
 	CustomerVoucherID int `gorm:"type:bigint(20);column:customer_voucher_id"`
 	CustomerVoucher               CustomerVoucher       `gorm:"foreignkey:CustomerVoucherID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	CampaignID  int `gorm:"type:bigint(20);column:campaign_id;index"`
+	Campaign               Campaign       `gorm:"foreignkey:CampaignID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
 	ExpiredDate 	time.Time `gorm:"column:expired_date"`
+	Status      string    `gorm:"type:varchar(20);column:status;index;default:booked"`
+	PhotoHash   string    `gorm:"type:varchar(64);column:photo_hash"`
+	PhotoPath   string    `gorm:"type:varchar(255);column:photo_path"`
+	// PhotoPerceptualHash dHash of the accepted photo, bit pattern of the uint64 hash
+	PhotoPerceptualHash *int64 `gorm:"type:bigint(20);column:photo_phash;index:idx_customer_voucher_books_photo_phash"`
+	PhotoPerceptualHashBand0 *int `gorm:"type:smallint;column:photo_phash_band0;index:idx_customer_voucher_books_photo_phash_band0"`
+	PhotoPerceptualHashBand1 *int `gorm:"type:smallint;column:photo_phash_band1;index:idx_customer_voucher_books_photo_phash_band1"`
+	PhotoPerceptualHashBand2 *int `gorm:"type:smallint;column:photo_phash_band2;index:idx_customer_voucher_books_photo_phash_band2"`
+	PhotoPerceptualHashBand3 *int `gorm:"type:smallint;column:photo_phash_band3;index:idx_customer_voucher_books_photo_phash_band3"`
+	PhotoPerceptualHashBand4 *int `gorm:"type:smallint;column:photo_phash_band4;index:idx_customer_voucher_books_photo_phash_band4"`
+	PhotoPerceptualHashBand5 *int `gorm:"type:smallint;column:photo_phash_band5;index:idx_customer_voucher_books_photo_phash_band5"`
+	PhotoPerceptualHashBand6 *int `gorm:"type:smallint;column:photo_phash_band6;index:idx_customer_voucher_books_photo_phash_band6"`
+	PhotoPerceptualHashBand7 *int `gorm:"type:smallint;column:photo_phash_band7;index:idx_customer_voucher_books_photo_phash_band7"`
+	Flagged             bool   `gorm:"column:flagged;default:false"`
+	FlagReason          string `gorm:"type:varchar(255);column:flag_reason"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
 }
 
 // TableName name of table
@@ -20,16 +84,69 @@ func (r CustomerVoucherBook) TableName() string {
 	return "customer_voucher_books"
 }
 
+// PhotoPerceptualHashBandColumns columns of the bytes of the photo perceptual hash returned by imagehash.Split
+var PhotoPerceptualHashBandColumns = [imagehash.Bands]string{
+	"photo_phash_band0",
+	"photo_phash_band1",
+	"photo_phash_band2",
+	"photo_phash_band3",
+	"photo_phash_band4",
+	"photo_phash_band5",
+	"photo_phash_band6",
+	"photo_phash_band7",
+}
+
+// SetPhotoPerceptualHash photo perceptual hash of the booking with its bytes
+func (r *CustomerVoucherBook) SetPhotoPerceptualHash(hash int64) {
+	bands := imagehash.Split(uint64(hash))
+	r.PhotoPerceptualHash = &hash
+	r.PhotoPerceptualHashBand0 = &bands[0]
+	r.PhotoPerceptualHashBand1 = &bands[1]
+	r.PhotoPerceptualHashBand2 = &bands[2]
+	r.PhotoPerceptualHashBand3 = &bands[3]
+	r.PhotoPerceptualHashBand4 = &bands[4]
+	r.PhotoPerceptualHashBand5 = &bands[5]
+	r.PhotoPerceptualHashBand6 = &bands[6]
+	r.PhotoPerceptualHashBand7 = &bands[7]
+}
+
 // MysqlCustomerVoucherBookRepository Repository Interface
 type MysqlCustomerVoucherBookRepository interface {
 	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	SingleWithFilterWithTx(ctx context.Context, tx *gorm.DB, fields, associate, filter []string, model interface{}, args ...interface{}) error
 	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
 	Update(ctx context.Context, data CustomerVoucherBook) error
 	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
 	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
 	Store(ctx context.Context, data CustomerVoucherBook) (CustomerVoucherBook, error)
 	StoreWithTx(ctx context.Context, tx *gorm.DB, data CustomerVoucherBook) (int, error)
+	UpdateStatusWithTx(ctx context.Context, tx *gorm.DB, id int, from, to string) error
+	LockWithTx(ctx context.Context, tx *gorm.DB, id int) (CustomerVoucherBook, error)
+	FetchSimilarPhotoHash(ctx context.Context, customerId int, hash int64, maxDistance int, limit int) ([]CustomerVoucherBook, error)
+	CountFilter(ctx context.Context, associate []string, model interface{}, filter []string, args ...interface{}) (int, error)
 	Delete(ctx context.Context, id int) (int, error)
 	SoftDelete(ctx context.Context, id int) (int, error)
 	DB() *gorm.DB
+}
+
+// CustomerVoucherBookUseCase UseCase Interface
+type CustomerVoucherBookUseCase interface {
+	GetBookingsByCustomerId(beegoCtx *beegoContext.Context, customerId, page, limit int) (*CustomerVoucherBookHistoryListResponse, error)
+	CreateWithTx(ctx context.Context, tx *gorm.DB, book CustomerVoucherBook) (CustomerVoucherBook, error)
+	TransitionWithTx(ctx context.Context, tx *gorm.DB, book *CustomerVoucherBook, to, reason string) error
+	RedeemWithTx(ctx context.Context, tx *gorm.DB, book *CustomerVoucherBook, reason string) error
+	ReleaseExpiredBookings(ctx context.Context, batchSize int) (int, error)
+	GetBookingPhoto(beegoCtx *beegoContext.Context, id int) (*CustomerVoucherBookPhoto, error)
+	GetBookingAttempts(beegoCtx *beegoContext.Context, id int) ([]CustomerVoucherBookAttemptResponse, error)
+	GetAttemptPhoto(beegoCtx *beegoContext.Context, id, attemptId int) (*CustomerVoucherBookPhoto, error)
+	GetPendingReviews(beegoCtx *beegoContext.Context, page, limit int) (*CustomerVoucherBookReviewListResponse, error)
+	ApproveReview(beegoCtx *beegoContext.Context, id int, request CustomerVoucherBookReviewRequest) (*CustomerVoucherBookReviewResponse, error)
+	RejectReview(beegoCtx *beegoContext.Context, id int, request CustomerVoucherBookReviewRequest) (*CustomerVoucherBookReviewResponse, error)
+}
+
+// CustomerVoucherBookPhoto verification photo stored for a booking
+type CustomerVoucherBookPhoto struct {
+	Hash        string
+	ContentType string
+	Data        []byte
 }
\ No newline at end of file
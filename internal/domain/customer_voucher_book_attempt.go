@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// outcome of a photo verification attempt
+const (
+	VerificationAttemptVerified     = "verified"
+	VerificationAttemptRejected     = "rejected"
+	VerificationAttemptInconclusive = "inconclusive"
+)
+
+// CustomerVoucherBookAttempt photo verification attempt of a voucher booking
+type CustomerVoucherBookAttempt struct {
+	ID                    int                 `gorm:"column:id;primarykey;autoIncrement:true"`
+	CustomerVoucherBookID int                 `gorm:"type:bigint(20);column:customer_voucher_book_id;index"`
+	CustomerVoucherBook   CustomerVoucherBook `gorm:"foreignkey:CustomerVoucherBookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	CustomerID            int                 `gorm:"type:bigint(20);column:customer_id;index"`
+	Outcome               string              `gorm:"type:varchar(20);column:outcome"`
+	ReasonCode            string              `gorm:"type:varchar(50);column:reason_code"`
+	PhotoHash             string              `gorm:"type:varchar(64);column:photo_hash"`
+	PhotoPath             string              `gorm:"type:varchar(255);column:photo_path"`
+	CreatedAt             time.Time           `gorm:"column:created_at"`
+}
+
+// TableName name of table
+func (r CustomerVoucherBookAttempt) TableName() string {
+	return "customer_voucher_book_attempts"
+}
+
+// MysqlCustomerVoucherBookAttemptRepository Repository Interface
+type MysqlCustomerVoucherBookAttemptRepository interface {
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	CountFilter(ctx context.Context, associate []string, model interface{}, filter []string, args ...interface{}) (int, error)
+	CountFilterWithTx(ctx context.Context, tx *gorm.DB, associate []string, model interface{}, filter []string, args ...interface{}) (int, error)
+	Store(ctx context.Context, data CustomerVoucherBookAttempt) (CustomerVoucherBookAttempt, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data CustomerVoucherBookAttempt) (int, error)
+	DB() *gorm.DB
+}
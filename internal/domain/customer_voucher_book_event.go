@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CustomerVoucherBookEvent history of every status change of a voucher booking
+type CustomerVoucherBookEvent struct {
+	ID                    int                 `gorm:"column:id;primarykey;autoIncrement:true"`
+	CustomerVoucherBookID int                 `gorm:"type:bigint(20);column:customer_voucher_book_id;index"`
+	CustomerVoucherBook   CustomerVoucherBook `gorm:"foreignkey:CustomerVoucherBookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	CustomerID            int                 `gorm:"type:bigint(20);column:customer_id;index"`
+	FromStatus            string              `gorm:"type:varchar(20);column:from_status"`
+	ToStatus              string              `gorm:"type:varchar(20);column:to_status"`
+	Reason                string              `gorm:"type:varchar(255);column:reason"`
+	CreatedAt             time.Time           `gorm:"column:created_at"`
+}
+
+// TableName name of table
+func (r CustomerVoucherBookEvent) TableName() string {
+	return "customer_voucher_book_events"
+}
+
+// MysqlCustomerVoucherBookEventRepository Repository Interface
+type MysqlCustomerVoucherBookEventRepository interface {
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	Store(ctx context.Context, data CustomerVoucherBookEvent) (CustomerVoucherBookEvent, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data CustomerVoucherBookEvent) (int, error)
+	DB() *gorm.DB
+}
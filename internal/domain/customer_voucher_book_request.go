@@ -0,0 +1,6 @@
+package domain
+
+type CustomerVoucherBookReviewRequest struct {
+	// Note reason of the reviewer decision, kept in the booking history
+	Note string `json:"note" validate:"max=200"`
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+)
+
+type CustomerVoucherBookHistoryResponse struct {
+	ID                int                                `json:"id"`
+	CampaignID        int                                `json:"campaign_id"`
+	CustomerVoucherID int                                `json:"customer_voucher_id"`
+	Status            string                             `json:"status"`
+	ExpiredDate       string                             `json:"expired_date"`
+	CreatedAt         string                             `json:"created_at"`
+	PhotoHash         string                             `json:"photo_hash,omitempty"`
+	Flagged           bool                               `json:"flagged"`
+	FlagReason        string                             `json:"flag_reason,omitempty"`
+	Events            []CustomerVoucherBookEventResponse `json:"events"`
+}
+
+type CustomerVoucherBookEventResponse struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type CustomerVoucherBookHistoryListResponse struct {
+	Items      []CustomerVoucherBookHistoryResponse `json:"items"`
+	Pagination PaginationResponse                   `json:"pagination"`
+}
+
+func NewCustomerVoucherBookHistoryResponse(book CustomerVoucherBook, events []CustomerVoucherBookEvent) CustomerVoucherBookHistoryResponse {
+	result := CustomerVoucherBookHistoryResponse{
+		ID:                book.ID,
+		CampaignID:        book.CampaignID,
+		CustomerVoucherID: book.CustomerVoucherID,
+		Status:            book.Status,
+		ExpiredDate:       book.ExpiredDate.Format(helper.DateTimeFormatDefault),
+		CreatedAt:         book.CreatedAt.Format(helper.DateTimeFormatDefault),
+		PhotoHash:         book.PhotoHash,
+		Flagged:           book.Flagged,
+		FlagReason:        book.FlagReason,
+		Events:            make([]CustomerVoucherBookEventResponse, 0, len(events)),
+	}
+	for _, event := range events {
+		result.Events = append(result.Events, CustomerVoucherBookEventResponse{
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Reason:     event.Reason,
+			CreatedAt:  event.CreatedAt.Format(helper.DateTimeFormatDefault),
+		})
+	}
+	return result
+}
+
+type CustomerVoucherBookReviewResponse struct {
+	ID                int    `json:"id"`
+	CustomerID        int    `json:"customer_id"`
+	CampaignID        int    `json:"campaign_id"`
+	CustomerVoucherID int    `json:"customer_voucher_id"`
+	Status            string `json:"status"`
+	PhotoHash         string `json:"photo_hash"`
+	PhotoURL          string `json:"photo_url"`
+	Flagged           bool   `json:"flagged"`
+	FlagReason        string `json:"flag_reason,omitempty"`
+	ExpiredDate       string `json:"expired_date"`
+	SubmittedAt       string `json:"submitted_at"`
+}
+
+type CustomerVoucherBookReviewListResponse struct {
+	Items      []CustomerVoucherBookReviewResponse `json:"items"`
+	Pagination PaginationResponse                  `json:"pagination"`
+}
+
+func NewCustomerVoucherBookReviewResponse(book CustomerVoucherBook) CustomerVoucherBookReviewResponse {
+	return CustomerVoucherBookReviewResponse{
+		ID:                book.ID,
+		CustomerID:        book.CustomerID,
+		CampaignID:        book.CampaignID,
+		CustomerVoucherID: book.CustomerVoucherID,
+		Status:            book.Status,
+		PhotoHash:         book.PhotoHash,
+		PhotoURL:          fmt.Sprintf("/api/v1/admin/bookings/%d/photo", book.ID),
+		Flagged:           book.Flagged,
+		FlagReason:        book.FlagReason,
+		ExpiredDate:       book.ExpiredDate.Format(helper.DateTimeFormatDefault),
+		SubmittedAt:       book.UpdatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
+
+type CustomerVoucherBookAttemptResponse struct {
+	ID         int    `json:"id"`
+	CustomerID int    `json:"customer_id"`
+	Outcome    string `json:"outcome"`
+	ReasonCode string `json:"reason_code,omitempty"`
+	PhotoHash  string `json:"photo_hash"`
+	PhotoURL   string `json:"photo_url,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func NewCustomerVoucherBookAttemptResponse(attempt CustomerVoucherBookAttempt) CustomerVoucherBookAttemptResponse {
+	result := CustomerVoucherBookAttemptResponse{
+		ID:         attempt.ID,
+		CustomerID: attempt.CustomerID,
+		Outcome:    attempt.Outcome,
+		ReasonCode: attempt.ReasonCode,
+		PhotoHash:  attempt.PhotoHash,
+		CreatedAt:  attempt.CreatedAt.Format(helper.DateTimeFormatDefault),
+	}
+	if attempt.PhotoPath != "" {
+		result.PhotoURL = fmt.Sprintf("/api/v1/admin/bookings/%d/attempts/%d/photo", attempt.CustomerVoucherBookID, attempt.ID)
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// checks evaluated next to the campaign rule set before a voucher is booked
+const (
+	EligibilityCheckCampaignActive  = "campaign_active"
+	EligibilityCheckExistingVoucher = "existing_voucher"
+	EligibilityCheckActiveBooking   = "active_booking"
+)
+
+// EligibilityInput data a rule is evaluated against
+type EligibilityInput struct {
+	Customer Customer
+	Campaign Campaign
+	Now      time.Time
+}
+
+// EligibilityResult outcome of a single rule, failed rules carry the i18n error code
+type EligibilityResult struct {
+	Rule       string
+	Passed     bool
+	Value      float64
+	Threshold  float64
+	WindowDays int
+	// Currency of Value and Threshold of the spend rules
+	Currency  string
+	ErrorCode string
+}
+
+// EligibilityRule single eligibility check of a campaign
+type EligibilityRule interface {
+	Name() string
+	Evaluate(ctx context.Context, input EligibilityInput) (EligibilityResult, error)
+}
+
+// EligibilityEngine evaluates every rule configured for a campaign
+type EligibilityEngine interface {
+	Rules(ctx context.Context, campaign Campaign) ([]EligibilityRule, error)
+	Evaluate(ctx context.Context, input EligibilityInput) ([]EligibilityResult, error)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"image"
+)
+
+// FaceVerificationInput photo uploaded by the customer, already validated and decoded
+type FaceVerificationInput struct {
+	CustomerID int
+	MimeType   string
+	Data       []byte
+	Image      image.Image
+}
+
+// FaceVerificationResult outcome of a verification, Inconclusive when the provider can not decide
+type FaceVerificationResult struct {
+	Provider     string
+	Verified     bool
+	Inconclusive bool
+	Confidence   float64
+}
+
+// FaceVerifier checks the photo contains the face of a person
+type FaceVerifier interface {
+	Verify(ctx context.Context, input FaceVerificationInput) (FaceVerificationResult, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey response of a request sent with an Idempotency-Key header, replayed to the retries of the request
+type IdempotencyKey struct {
+	// KeyHash sha256 of the header value with the client and route the key is scoped to
+	KeyHash string `gorm:"type:varchar(64);column:key_hash;primarykey"`
+	// Fingerprint sha256 of the request body, a retry with another body is rejected
+	Fingerprint string `gorm:"type:varchar(64);column:fingerprint"`
+	// StatusCode status of the stored response, 0 while the request is processed
+	StatusCode  int       `gorm:"column:status_code"`
+	ContentType string    `gorm:"type:varchar(255);column:content_type"`
+	Body        []byte    `gorm:"column:body"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;index:idx_idempotency_keys_expires_at"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// MysqlIdempotencyKeyRepository Repository Interface
+type MysqlIdempotencyKeyRepository interface {
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	// StoreIgnoreDuplicate store the key unless it is already stored, false is returned when the insert was skipped
+	StoreIgnoreDuplicate(ctx context.Context, data IdempotencyKey) (bool, error)
+	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, keyHash string) error
+	// DeleteWithFilter delete the keys matching the filter, e.g. a key expired at a given date
+	DeleteWithFilter(ctx context.Context, filter []string, args ...interface{}) (int, error)
+	// DeleteExpired delete at most limit keys expired before now
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error)
+	DB() *gorm.DB
+}
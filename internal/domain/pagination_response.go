@@ -0,0 +1,23 @@
+package domain
+
+import "math"
+
+type PaginationResponse struct {
+	Page      int `json:"page"`
+	Limit     int `json:"limit"`
+	Total     int `json:"total"`
+	TotalPage int `json:"total_page"`
+}
+
+func NewPaginationResponse(page, limit, total int) PaginationResponse {
+	totalPage := 1
+	if limit > 0 && total > 0 {
+		totalPage = int(math.Ceil(float64(total) / float64(limit)))
+	}
+	return PaginationResponse{
+		Page:      page,
+		Limit:     limit,
+		Total:     total,
+		TotalPage: totalPage,
+	}
+}
@@ -3,16 +3,45 @@ package domain
 import (
 	"context"
 	"gorm.io/gorm"
+	"io"
+	"mime/multipart"
 	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+)
+
+// status of a purchase transaction
+const (
+	PurchaseTransactionStatusCompleted         = "completed"
+	PurchaseTransactionStatusPartiallyRefunded = "partially_refunded"
+	PurchaseTransactionStatusRefunded          = "refunded"
+	PurchaseTransactionStatusVoided            = "voided"
 )
 
+// PurchaseTransactionEligibleStatuses transactions counted by the eligibility rules, the spend of a
+// partially refunded transaction is counted net of its refunds
+var PurchaseTransactionEligibleStatuses = []string{
+	PurchaseTransactionStatusCompleted,
+	PurchaseTransactionStatusPartiallyRefunded,
+}
+
 type PurchaseTransaction struct {
 	ID        int            `gorm:"column:id;primarykey;autoIncrement:true"`
 	CustomerID  int `gorm:"type:bigint(20);column:customer_id"`
 	Customer               Customer       `gorm:"foreignkey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
-	TotalSpent     float64         `gorm:"type:decimal(10,2);column:total_spent"`
-	TotalSaving     float64         `gorm:"type:decimal(10,2);column:total_saving"`
+	TotalSpent     money.Amount    `gorm:"type:decimal(10,2);column:total_spent"`
+	TotalSaving     money.Amount    `gorm:"type:decimal(10,2);column:total_saving"`
+	// Currency ISO 4217 code of the amounts
+	Currency      string         `gorm:"type:varchar(3);column:currency;default:USD"`
 	TransactionAt time.Time      `gorm:"column:transaction_at"`
+	// ExternalReference id of the transaction in the source system, a transaction is ingested once per reference
+	ExternalReference *string   `gorm:"type:varchar(100);column:external_reference;uniqueIndex"`
+	Status            string    `gorm:"type:varchar(20);column:status;index;default:completed"`
+	// RefundedAmount sum of the refunds of the transaction, the net spend is TotalSpent - RefundedAmount
+	RefundedAmount money.Amount `gorm:"type:decimal(10,2);column:refunded_amount;default:0"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at"`
 }
 
 // TableName name of table
@@ -24,13 +53,42 @@ func (r PurchaseTransaction) TableName() string {
 type MysqlPurchaseTransactionRepository interface {
 	CountFilter(ctx context.Context, associate []string, model interface{},criteria []string, args ...interface{}) (int, error)
 	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	SingleWithFilterWithTx(ctx context.Context, tx *gorm.DB, fields, associate, filter []string, model interface{}, args ...interface{}) error
 	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
 	Update(ctx context.Context, data PurchaseTransaction) error
 	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
 	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
 	Store(ctx context.Context, data PurchaseTransaction) (PurchaseTransaction, error)
 	StoreWithTx(ctx context.Context, tx *gorm.DB, data PurchaseTransaction) (int, error)
+	StoreIgnoreDuplicateWithTx(ctx context.Context, tx *gorm.DB, data PurchaseTransaction) (int, bool, error)
+	LockWithTx(ctx context.Context, tx *gorm.DB, id int) (PurchaseTransaction, error)
+	SumNetSpentByCurrency(ctx context.Context, filter []string, args ...interface{}) ([]PurchaseTransactionCurrencySum, error)
 	Delete(ctx context.Context, id int) (int, error)
 	SoftDelete(ctx context.Context, id int) (int, error)
 	DB() *gorm.DB
 }
+
+// PurchaseTransactionUseCase UseCase Interface
+type PurchaseTransactionUseCase interface {
+	GetPurchaseTransactions(beegoCtx *beegoContext.Context, customerId, page, limit int, filter PurchaseTransactionFilter) (*PurchaseTransactionListResponse, error)
+	CreatePurchaseTransaction(beegoCtx *beegoContext.Context, customerId int, request PurchaseTransactionRequest) (*PurchaseTransactionResponse, error)
+	CreatePurchaseTransactions(beegoCtx *beegoContext.Context, customerId int, request PurchaseTransactionBatchRequest) (*PurchaseTransactionBatchResponse, error)
+	ImportPurchaseTransactions(ctx context.Context, fileName string, file io.ReadSeeker) (*PurchaseTransactionImportResponse, error)
+	UploadPurchaseTransactions(beegoCtx *beegoContext.Context, file *multipart.FileHeader) (*PurchaseTransactionImportResponse, error)
+	GetImport(beegoCtx *beegoContext.Context, id int) (*PurchaseTransactionImportResponse, error)
+	GetImportResult(beegoCtx *beegoContext.Context, id int) (*PurchaseTransactionImportResult, error)
+	RefundPurchaseTransaction(beegoCtx *beegoContext.Context, customerId, id int, request PurchaseTransactionRefundRequest) (*PurchaseTransactionResponse, error)
+	VoidPurchaseTransaction(beegoCtx *beegoContext.Context, customerId, id int) (*PurchaseTransactionResponse, error)
+}
+
+// PurchaseTransactionCurrencySum net spend of the transactions in a currency
+type PurchaseTransactionCurrencySum struct {
+	Currency string
+	NetSpent money.Amount
+}
+
+// PurchaseTransactionFilter transaction_at range of the listing, zero time is not filtered
+type PurchaseTransactionFilter struct {
+	StartDate time.Time
+	EndDate   time.Time
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// status of a purchase transaction import
+const (
+	PurchaseTransactionImportStatusPending    = "pending"
+	PurchaseTransactionImportStatusProcessing = "processing"
+	PurchaseTransactionImportStatusCompleted  = "completed"
+	PurchaseTransactionImportStatusFailed     = "failed"
+)
+
+// PurchaseTransactionImport csv file of purchase transactions, a file is identified by its checksum
+// and a failed import resumes after LastRow
+type PurchaseTransactionImport struct {
+	ID            int       `gorm:"column:id;primarykey;autoIncrement:true"`
+	Checksum      string    `gorm:"type:varchar(64);column:checksum;uniqueIndex"`
+	FileName      string    `gorm:"type:varchar(255);column:file_name"`
+	Status        string    `gorm:"type:varchar(20);column:status;default:pending"`
+	LastRow       int       `gorm:"column:last_row"`
+	ImportedRows  int       `gorm:"column:imported_rows"`
+	DuplicateRows int       `gorm:"column:duplicate_rows"`
+	FailedRows    int       `gorm:"column:failed_rows"`
+	Message       string    `gorm:"type:varchar(255);column:message"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r PurchaseTransactionImport) TableName() string {
+	return "purchase_transaction_imports"
+}
+
+// PurchaseTransactionImportError row of the import file that was not stored
+type PurchaseTransactionImportError struct {
+	ID                          int       `gorm:"column:id;primarykey;autoIncrement:true"`
+	PurchaseTransactionImportID int       `gorm:"type:bigint(20);column:purchase_transaction_import_id;index:idx_purchase_transaction_import_errors_purchase_transaction_import_id"`
+	Row                         int       `gorm:"column:row"`
+	ExternalReference           string    `gorm:"type:varchar(100);column:external_reference"`
+	Message                     string    `gorm:"type:varchar(255);column:message"`
+	CreatedAt                   time.Time `gorm:"column:created_at"`
+}
+
+// TableName name of table
+func (r PurchaseTransactionImportError) TableName() string {
+	return "purchase_transaction_import_errors"
+}
+
+// MysqlPurchaseTransactionImportRepository Repository Interface
+type MysqlPurchaseTransactionImportRepository interface {
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	Store(ctx context.Context, data PurchaseTransactionImport) (PurchaseTransactionImport, error)
+	UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error
+	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
+	Claim(ctx context.Context, id int, staleBefore time.Time) (bool, error)
+	StoreErrorsWithTx(ctx context.Context, tx *gorm.DB, data []PurchaseTransactionImportError) error
+	FetchErrors(ctx context.Context, importId, afterId, limit int) ([]PurchaseTransactionImportError, error)
+	DB() *gorm.DB
+}
+
+// PurchaseTransactionImportResult csv file of the rows that were not imported
+type PurchaseTransactionImportResult struct {
+	FileName string
+	Data     []byte
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"gorm.io/gorm"
+)
+
+// PurchaseTransactionRefund refund of a purchase transaction, the transaction keeps the sum in RefundedAmount
+type PurchaseTransactionRefund struct {
+	ID                    int                 `gorm:"column:id;primarykey;autoIncrement:true"`
+	PurchaseTransactionID int                 `gorm:"type:bigint(20);column:purchase_transaction_id;index"`
+	PurchaseTransaction   PurchaseTransaction `gorm:"foreignkey:PurchaseTransactionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;->"`
+	Amount                money.Amount        `gorm:"type:decimal(10,2);column:amount"`
+	Reason                string              `gorm:"type:varchar(255);column:reason"`
+	CreatedAt             time.Time           `gorm:"column:created_at"`
+}
+
+// TableName name of table
+func (r PurchaseTransactionRefund) TableName() string {
+	return "purchase_transaction_refunds"
+}
+
+// MysqlPurchaseTransactionRefundRepository Repository Interface
+type MysqlPurchaseTransactionRefundRepository interface {
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data PurchaseTransactionRefund) (int, error)
+	DB() *gorm.DB
+}
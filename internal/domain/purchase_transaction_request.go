@@ -0,0 +1,23 @@
+package domain
+
+import "github.com/radyatamaa/technical-test-aichat/pkg/money"
+
+type PurchaseTransactionRequest struct {
+	// ExternalReference id of the transaction in the source system, resending the same reference does not create a new transaction
+	ExternalReference string       `json:"external_reference" validate:"required,no_space,max=100"`
+	TotalSpent        money.Amount `json:"total_spent" validate:"gt=0,max=99999999.99"`
+	TotalSaving       money.Amount `json:"total_saving" validate:"min=0,max=99999999.99"`
+	// Currency ISO 4217 code of the amounts, USD when empty. The amounts have at most the decimals of its minor unit
+	Currency      string `json:"currency" validate:"omitempty,iso4217,currency"`
+	TransactionAt string `json:"transaction_at" validate:"required,datetime=2006-01-02 15:04:05"`
+}
+
+type PurchaseTransactionBatchRequest struct {
+	Transactions []PurchaseTransactionRequest `json:"transactions" validate:"required,min=1,max=100,dive"`
+}
+
+type PurchaseTransactionRefundRequest struct {
+	// Amount in the currency of the purchase transaction, with at most the decimals of its minor unit
+	Amount money.Amount `json:"amount" validate:"gt=0,max=99999999.99"`
+	Reason string       `json:"reason" validate:"max=255"`
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+)
+
+type PurchaseTransactionResponse struct {
+	ID                int          `json:"id"`
+	CustomerID        int          `json:"customer_id"`
+	ExternalReference string       `json:"external_reference,omitempty"`
+	TotalSpent        money.Amount `json:"total_spent"`
+	TotalSaving       money.Amount `json:"total_saving"`
+	Currency          string       `json:"currency"`
+	TransactionAt     string       `json:"transaction_at"`
+	Status            string       `json:"status"`
+	RefundedAmount    money.Amount `json:"refunded_amount"`
+	// NetSpent spend counted by the eligibility rules, zero when the transaction is refunded or voided
+	NetSpent money.Amount `json:"net_spent"`
+	// Duplicate the external reference was already ingested and the stored transaction is returned
+	Duplicate bool `json:"duplicate"`
+}
+
+type PurchaseTransactionListResponse struct {
+	Items      []PurchaseTransactionResponse `json:"items"`
+	Pagination PaginationResponse            `json:"pagination"`
+}
+
+type PurchaseTransactionBatchResponse struct {
+	Items      []PurchaseTransactionResponse `json:"items"`
+	Created    int                           `json:"created"`
+	Duplicates int                           `json:"duplicates"`
+}
+
+func NewPurchaseTransactionResponse(transaction PurchaseTransaction, duplicate bool) PurchaseTransactionResponse {
+	result := PurchaseTransactionResponse{
+		ID:             transaction.ID,
+		CustomerID:     transaction.CustomerID,
+		TotalSpent:     transaction.TotalSpent,
+		TotalSaving:    transaction.TotalSaving,
+		Currency:       transaction.Currency,
+		TransactionAt:  transaction.TransactionAt.Format(helper.DateTimeFormatDefault),
+		Status:         transaction.Status,
+		RefundedAmount: transaction.RefundedAmount,
+		Duplicate:      duplicate,
+	}
+	if transaction.Status == PurchaseTransactionStatusCompleted || transaction.Status == PurchaseTransactionStatusPartiallyRefunded {
+		result.NetSpent = transaction.TotalSpent - transaction.RefundedAmount
+	}
+	if transaction.ExternalReference != nil {
+		result.ExternalReference = *transaction.ExternalReference
+	}
+	return result
+}
+
+type PurchaseTransactionImportResponse struct {
+	ID            int    `json:"id"`
+	FileName      string `json:"file_name"`
+	Checksum      string `json:"checksum"`
+	Status        string `json:"status"`
+	Message       string `json:"message,omitempty"`
+	ProcessedRows int    `json:"processed_rows"`
+	ImportedRows  int    `json:"imported_rows"`
+	DuplicateRows int    `json:"duplicate_rows"`
+	FailedRows    int    `json:"failed_rows"`
+	ResultURL     string `json:"result_url"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+func NewPurchaseTransactionImportResponse(data PurchaseTransactionImport) PurchaseTransactionImportResponse {
+	return PurchaseTransactionImportResponse{
+		ID:            data.ID,
+		FileName:      data.FileName,
+		Checksum:      data.Checksum,
+		Status:        data.Status,
+		Message:       data.Message,
+		ProcessedRows: data.LastRow,
+		ImportedRows:  data.ImportedRows,
+		DuplicateRows: data.DuplicateRows,
+		FailedRows:    data.FailedRows,
+		ResultURL:     fmt.Sprintf("/api/v1/admin/purchase-transactions/imports/%d/result", data.ID),
+		CreatedAt:     data.CreatedAt.Format(helper.DateTimeFormatDefault),
+		UpdatedAt:     data.UpdatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"gorm.io/gorm"
+)
+
+// permissions granted by roles and api key scopes, "resource:read" or "resource:write"
+const (
+	PermissionCampaignsWrite            = "campaigns:write"
+	PermissionCustomersRead             = "customers:read"
+	PermissionCustomersWrite            = "customers:write"
+	PermissionPurchaseTransactionsRead  = "purchase_transactions:read"
+	PermissionPurchaseTransactionsWrite = "purchase_transactions:write"
+	PermissionImportsRead               = "imports:read"
+	PermissionImportsWrite              = "imports:write"
+	PermissionReviewsRead               = "reviews:read"
+	PermissionReviewsWrite              = "reviews:write"
+	PermissionCurrencyRatesRead         = "currency_rates:read"
+	PermissionCurrencyRatesWrite        = "currency_rates:write"
+	PermissionApiKeysRead               = "api_keys:read"
+	PermissionApiKeysWrite              = "api_keys:write"
+	PermissionRolesRead                 = "roles:read"
+	PermissionRolesWrite                = "roles:write"
+)
+
+// Permissions every permission checked by the routes
+var Permissions = []string{
+	PermissionCampaignsWrite,
+	PermissionCustomersRead,
+	PermissionCustomersWrite,
+	PermissionPurchaseTransactionsRead,
+	PermissionPurchaseTransactionsWrite,
+	PermissionImportsRead,
+	PermissionImportsWrite,
+	PermissionReviewsRead,
+	PermissionReviewsWrite,
+	PermissionCurrencyRatesRead,
+	PermissionCurrencyRatesWrite,
+	PermissionApiKeysRead,
+	PermissionApiKeysWrite,
+	PermissionRolesRead,
+	PermissionRolesWrite,
+}
+
+// IsPermission value is one of the Permissions
+func IsPermission(value string) bool {
+	for _, permission := range Permissions {
+		if permission == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Role named set of permissions assigned to api keys
+type Role struct {
+	ID          int              `gorm:"column:id;primarykey;autoIncrement:true"`
+	Name        string           `gorm:"type:varchar(100);column:name;uniqueIndex:idx_roles_name"`
+	Description string           `gorm:"type:varchar(255);column:description"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID"`
+	CreatedAt   time.Time        `gorm:"column:created_at"`
+	UpdatedAt   time.Time        `gorm:"column:updated_at"`
+}
+
+// TableName name of table
+func (r Role) TableName() string {
+	return "roles"
+}
+
+// PermissionList permissions granted by the role
+func (r Role) PermissionList() []string {
+	result := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		result = append(result, permission.Permission)
+	}
+	return result
+}
+
+// RolePermission permission granted by a role
+type RolePermission struct {
+	RoleID     int    `gorm:"column:role_id;primarykey;autoIncrement:false"`
+	Permission string `gorm:"type:varchar(100);column:permission;primarykey"`
+}
+
+// TableName name of table
+func (r RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// ApiKeyRole role assigned to an api key
+type ApiKeyRole struct {
+	ApiKeyID int `gorm:"column:api_key_id;primarykey;autoIncrement:false"`
+	RoleID   int `gorm:"column:role_id;primarykey;autoIncrement:false;index:idx_api_key_roles_role_id"`
+}
+
+// TableName name of table
+func (r ApiKeyRole) TableName() string {
+	return "api_key_roles"
+}
+
+// MysqlRoleRepository Repository Interface
+type MysqlRoleRepository interface {
+	CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error)
+	SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error
+	FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error)
+	StoreWithTx(ctx context.Context, tx *gorm.DB, data Role) (int, error)
+	UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error
+	DeleteWithTx(ctx context.Context, tx *gorm.DB, id int) error
+	// ReplacePermissionsWithTx replace the permissions granted by the role
+	ReplacePermissionsWithTx(ctx context.Context, tx *gorm.DB, roleId int, permissions []string) error
+	// ReplaceApiKeyRolesWithTx replace the roles assigned to the api key
+	ReplaceApiKeyRolesWithTx(ctx context.Context, tx *gorm.DB, apiKeyId int, roleIds []int) error
+	// FetchRolePermissions permissions granted by the roles
+	FetchRolePermissions(ctx context.Context, roleIds []int) ([]RolePermission, error)
+	// FetchApiKeyPermissions permissions granted by the roles of the api key
+	FetchApiKeyPermissions(ctx context.Context, apiKeyId int) ([]string, error)
+	DB() *gorm.DB
+}
+
+// RoleUseCase UseCase Interface
+type RoleUseCase interface {
+	GetRoles(beegoCtx *beegoContext.Context, page, limit int) (*RoleListResponse, error)
+	CreateRole(beegoCtx *beegoContext.Context, request RoleRequest) (*RoleResponse, error)
+	UpdateRole(beegoCtx *beegoContext.Context, id int, request RoleUpdateRequest) (*RoleResponse, error)
+	DeleteRole(beegoCtx *beegoContext.Context, id int) error
+	SetApiKeyRoles(beegoCtx *beegoContext.Context, apiKeyId int, request ApiKeyRoleRequest) error
+}
@@ -0,0 +1,21 @@
+package domain
+
+type RoleRequest struct {
+	Name        string `json:"name" validate:"required,max=100,unique_store=name:roles"`
+	Description string `json:"description" validate:"max=255"`
+	// Permissions permissions granted by the role, see the Permissions list
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required"`
+}
+
+type RoleUpdateRequest struct {
+	// ID role being updated, set from the path so the name is unique among the other roles
+	ID          int      `json:"-"`
+	Name        string   `json:"name" validate:"required,max=100,unique_update=ID:roles:name:id"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required"`
+}
+
+type ApiKeyRoleRequest struct {
+	// RoleIDs roles replacing the roles of the api key, the key keeps only its scopes when empty
+	RoleIDs []int `json:"role_ids" validate:"dive,min=1"`
+}
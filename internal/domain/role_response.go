@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/radyatamaa/technical-test-aichat/pkg/helper"
+
+type RoleResponse struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+type RoleListResponse struct {
+	Items      []RoleResponse     `json:"items"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+func NewRoleResponse(role Role) RoleResponse {
+	return RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.PermissionList(),
+		UpdatedAt:   role.UpdatedAt.Format(helper.DateTimeFormatDefault),
+	}
+}
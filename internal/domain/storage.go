@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Storage object storage of uploaded files, objects are addressed by a slash separated key.
+// Get returns an error wrapping os.ErrNotExist when the key does not exist
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
@@ -0,0 +1,155 @@
+package eligibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+)
+
+var ErrUnknownRuleType = errors.New("unknown eligibility rule type")
+
+type eligibilityEngine struct {
+	defaultRules                       []domain.CampaignRule
+	mysqlCampaignRuleRepository        domain.MysqlCampaignRuleRepository
+	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository
+	mysqlCustomerVoucherRepository     domain.MysqlCustomerVoucherRepository
+	currencyConverter                  domain.CurrencyConverter
+}
+
+// NewEligibilityEngine engine loading the rule set of a campaign from the database,
+// campaigns whose rules were never configured are evaluated with defaultRules.
+func NewEligibilityEngine(defaultRules []domain.CampaignRule,
+	mysqlCampaignRuleRepository domain.MysqlCampaignRuleRepository,
+	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository,
+	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository,
+	currencyConverter domain.CurrencyConverter) domain.EligibilityEngine {
+	return &eligibilityEngine{
+		defaultRules:                       defaultRules,
+		mysqlCampaignRuleRepository:        mysqlCampaignRuleRepository,
+		mysqlPurchaseTransactionRepository: mysqlPurchaseTransactionRepository,
+		mysqlCustomerVoucherRepository:     mysqlCustomerVoucherRepository,
+		currencyConverter:                  currencyConverter,
+	}
+}
+
+// Rules rule set of the campaign, an empty rule set saved for the campaign has no rules
+func (e eligibilityEngine) Rules(ctx context.Context, campaign domain.Campaign) ([]domain.EligibilityRule, error) {
+	configs, err := e.mysqlCampaignRuleRepository.FetchWithFilter(
+		ctx,
+		100,
+		0,
+		"id ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		[]string{"campaign_id = ?"},
+		&[]domain.CampaignRule{}, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleConfigs := e.defaultRules
+	if result, ok := configs.(*[]domain.CampaignRule); ok && (campaign.RulesConfigured || len(*result) > 0) {
+		ruleConfigs = *result
+	}
+
+	rules := make([]domain.EligibilityRule, 0, len(ruleConfigs))
+	for _, config := range ruleConfigs {
+		rule, err := e.newRule(config)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Evaluate runs every rule of the campaign, it does not stop at the first failed rule.
+func (e eligibilityEngine) Evaluate(ctx context.Context, input domain.EligibilityInput) ([]domain.EligibilityResult, error) {
+	rules, err := e.Rules(ctx, input.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.EligibilityResult, 0, len(rules))
+	for _, rule := range rules {
+		result, err := rule.Evaluate(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (e eligibilityEngine) newRule(config domain.CampaignRule) (domain.EligibilityRule, error) {
+	switch config.RuleType {
+	case domain.EligibilityRulePurchaseCount:
+		return purchaseCountRule{config: config, mysqlPurchaseTransactionRepository: e.mysqlPurchaseTransactionRepository}, nil
+	case domain.EligibilityRuleSpendSum:
+		return spendSumRule{config: config, mysqlPurchaseTransactionRepository: e.mysqlPurchaseTransactionRepository, currencyConverter: e.currencyConverter}, nil
+	case domain.EligibilityRuleCustomerAge:
+		return customerAgeRule{config: config}, nil
+	case domain.EligibilityRuleAccountAge:
+		return accountAgeRule{config: config}, nil
+	case domain.EligibilityRulePreviousRedemption:
+		return previousRedemptionRule{config: config, mysqlCustomerVoucherRepository: e.mysqlCustomerVoucherRepository}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRuleType, config.RuleType)
+	}
+}
+
+// ParseRules parse rule set from config, format "type:threshold:windowDays:currency" separated by "|",
+// the currency of a spend threshold is optional and defaults to the campaign currency
+//
+//	purchase_count:3:30|spend_sum:100:0:USD
+func ParseRules(value string) ([]domain.CampaignRule, error) {
+	var rules []domain.CampaignRule
+
+	for _, item := range strings.Split(value, "|") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("invalid eligibility rule %q", item)
+		}
+
+		threshold, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid eligibility rule threshold %q: %w", item, err)
+		}
+
+		windowDays := 0
+		if len(parts) >= 3 {
+			if windowDays, err = strconv.Atoi(parts[2]); err != nil {
+				return nil, fmt.Errorf("invalid eligibility rule window %q: %w", item, err)
+			}
+		}
+
+		rule := domain.CampaignRule{
+			RuleType:   parts[0],
+			Threshold:  threshold,
+			WindowDays: windowDays,
+		}
+		if len(parts) == 4 {
+			rule.Currency = strings.ToUpper(parts[3])
+			if err := money.CheckCurrency(rule.Currency); err != nil {
+				return nil, fmt.Errorf("invalid eligibility rule currency %q: %w", item, err)
+			}
+		}
+		if _, err := (eligibilityEngine{}).newRule(rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,72 @@
+package eligibility_test
+
+import (
+	"context"
+	"testing"
+
+	campaignRuleRepository "github.com/radyatamaa/technical-test-aichat/internal/campaign_rule/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/eligibility"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+)
+
+func TestEligibilityEngineRules(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	defaultRules, err := eligibility.ParseRules("purchase_count:3:30|spend_sum:100:0:USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := eligibility.NewEligibilityEngine(defaultRules,
+		campaignRuleRepository.NewMysqlCampaignRuleRepository(db, nil),
+		nil,
+		nil,
+		nil)
+
+	neverConfigured := testutil.CreateCampaign(t, db, 10, 0, false)
+	withoutRules := testutil.CreateCampaign(t, db, 10, 0, false)
+	withoutRules.RulesConfigured = true
+	withRules := testutil.CreateCampaign(t, db, 10, 0, false)
+	withRules.RulesConfigured = true
+	if err := db.Create(&domain.CampaignRule{CampaignID: withRules.ID, RuleType: domain.EligibilityRuleCustomerAge, Threshold: 18}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		campaign domain.Campaign
+		want     []string
+	}{
+		{
+			name:     "rules never configured use the default rules",
+			campaign: neverConfigured,
+			want:     []string{domain.EligibilityRulePurchaseCount, domain.EligibilityRuleSpendSum},
+		},
+		{
+			name:     "empty rule set has no rules",
+			campaign: withoutRules,
+			want:     []string{},
+		},
+		{
+			name:     "rule set of the campaign",
+			campaign: withRules,
+			want:     []string{domain.EligibilityRuleCustomerAge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := engine.Rules(context.Background(), tt.campaign)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != len(tt.want) {
+				t.Fatalf("rules = %d, want %d", len(rules), len(tt.want))
+			}
+			for i := range rules {
+				if rules[i].Name() != tt.want[i] {
+					t.Errorf("rule %d = %s, want %s", i, rules[i].Name(), tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package eligibility
+
+import (
+	"context"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// purchaseCountRule customer must have at least Threshold purchases in the window, refunded and voided purchases are not counted
+type purchaseCountRule struct {
+	config                             domain.CampaignRule
+	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository
+}
+
+func (r purchaseCountRule) Name() string {
+	return domain.EligibilityRulePurchaseCount
+}
+
+func (r purchaseCountRule) Evaluate(ctx context.Context, input domain.EligibilityInput) (domain.EligibilityResult, error) {
+	filter, args := purchaseWindowFilter(r.config, input)
+
+	count, err := r.mysqlPurchaseTransactionRepository.CountFilter(ctx, []string{}, &domain.PurchaseTransaction{}, filter, args...)
+	if err != nil {
+		return domain.EligibilityResult{}, err
+	}
+	return newResult(r.config, float64(count), float64(count) >= r.config.Threshold, response.TransactionCompletePurchase30Days), nil
+}
+
+// spendSumRule customer must have spent at least Threshold in the window, net of refunds. Purchases and the
+// threshold are converted to the campaign currency
+type spendSumRule struct {
+	config                             domain.CampaignRule
+	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository
+	currencyConverter                  domain.CurrencyConverter
+}
+
+func (r spendSumRule) Name() string {
+	return domain.EligibilityRuleSpendSum
+}
+
+func (r spendSumRule) Evaluate(ctx context.Context, input domain.EligibilityInput) (domain.EligibilityResult, error) {
+	filter, args := purchaseWindowFilter(r.config, input)
+
+	currency := input.Campaign.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	sums, err := r.mysqlPurchaseTransactionRepository.SumNetSpentByCurrency(ctx, filter, args...)
+	if err != nil {
+		return domain.EligibilityResult{}, err
+	}
+
+	var totalSpent money.Amount
+	for _, sum := range sums {
+		converted, err := r.currencyConverter.Convert(ctx, sum.NetSpent, sum.Currency, currency)
+		if err != nil {
+			return domain.EligibilityResult{}, err
+		}
+		totalSpent += converted
+	}
+
+	threshold := money.FromFloat(r.config.Threshold)
+	if r.config.Currency != "" {
+		if threshold, err = r.currencyConverter.Convert(ctx, threshold, r.config.Currency, currency); err != nil {
+			return domain.EligibilityResult{}, err
+		}
+	}
+
+	result := newResult(r.config, totalSpent.Float64(), totalSpent >= threshold, response.TransactionMinimum)
+	result.Threshold = threshold.Float64()
+	result.Currency = currency
+	return result, nil
+}
+
+// customerAgeRule customer must be at least Threshold years old
+type customerAgeRule struct {
+	config domain.CampaignRule
+}
+
+func (r customerAgeRule) Name() string {
+	return domain.EligibilityRuleCustomerAge
+}
+
+func (r customerAgeRule) Evaluate(ctx context.Context, input domain.EligibilityInput) (domain.EligibilityResult, error) {
+	dateOfBirth := input.Customer.DateOfBirth
+	if len(dateOfBirth) > len(helper.DateFormatDefault) {
+		dateOfBirth = dateOfBirth[:len(helper.DateFormatDefault)]
+	}
+
+	age := 0
+	if birth := helper.StringToDate(dateOfBirth); !birth.IsZero() {
+		age = input.Now.Year() - birth.Year()
+		if input.Now.Before(birth.AddDate(age, 0, 0)) {
+			age--
+		}
+	}
+	return newResult(r.config, float64(age), float64(age) >= r.config.Threshold, response.CustomerAgeNotEligible), nil
+}
+
+// accountAgeRule customer account must be at least Threshold days old
+type accountAgeRule struct {
+	config domain.CampaignRule
+}
+
+func (r accountAgeRule) Name() string {
+	return domain.EligibilityRuleAccountAge
+}
+
+func (r accountAgeRule) Evaluate(ctx context.Context, input domain.EligibilityInput) (domain.EligibilityResult, error) {
+	days := int(input.Now.Sub(input.Customer.CreatedAt) / (24 * time.Hour))
+	if input.Customer.CreatedAt.IsZero() || days < 0 {
+		days = 0
+	}
+	return newResult(r.config, float64(days), float64(days) >= r.config.Threshold, response.AccountAgeNotEligible), nil
+}
+
+// previousRedemptionRule customer must have redeemed at most Threshold vouchers of other campaigns
+type previousRedemptionRule struct {
+	config                         domain.CampaignRule
+	mysqlCustomerVoucherRepository domain.MysqlCustomerVoucherRepository
+}
+
+func (r previousRedemptionRule) Name() string {
+	return domain.EligibilityRulePreviousRedemption
+}
+
+func (r previousRedemptionRule) Evaluate(ctx context.Context, input domain.EligibilityInput) (domain.EligibilityResult, error) {
+	count, err := r.mysqlCustomerVoucherRepository.CountFilter(ctx,
+		[]string{},
+		&domain.CustomerVoucher{},
+		[]string{"customer_id = ?", "campaign_id <> ?", "is_redeem = ?"},
+		input.Customer.ID,
+		input.Campaign.ID,
+		true)
+	if err != nil {
+		return domain.EligibilityResult{}, err
+	}
+	return newResult(r.config, float64(count), float64(count) <= r.config.Threshold, response.PreviousRedemptionNotEligible), nil
+}
+
+// purchaseWindowFilter completed purchases of the customer, limited to the last WindowDays when configured
+func purchaseWindowFilter(config domain.CampaignRule, input domain.EligibilityInput) ([]string, []interface{}) {
+	filter := []string{"customer_id = ?", "status IN ?"}
+	args := []interface{}{input.Customer.ID, domain.PurchaseTransactionEligibleStatuses}
+
+	if config.WindowDays > 0 {
+		filter = append(filter, "transaction_at >= ?", "transaction_at <= ?")
+		args = append(args, input.Now.AddDate(0, 0, -config.WindowDays), input.Now)
+	}
+	return filter, args
+}
+
+func newResult(config domain.CampaignRule, value float64, passed bool, errorCode string) domain.EligibilityResult {
+	result := domain.EligibilityResult{
+		Rule:       config.RuleType,
+		Passed:     passed,
+		Value:      value,
+		Threshold:  config.Threshold,
+		WindowDays: config.WindowDays,
+	}
+	if !passed {
+		result.ErrorCode = errorCode
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package faceverification
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+)
+
+// Config settings of every provider, Provider selects the one in use
+type Config struct {
+	Provider              string
+	MinSkinRatio          float64
+	InconclusiveSkinRatio float64
+	Url                   string
+	ApiKey                string
+	Timeout               time.Duration
+	MinConfidence         float64
+}
+
+// NewFaceVerifier provider selected by the config
+func NewFaceVerifier(config Config) (domain.FaceVerifier, error) {
+	switch config.Provider {
+	case ProviderLocal:
+		return NewLocalFaceVerifier(config.MinSkinRatio, config.InconclusiveSkinRatio), nil
+	case ProviderHttp:
+		if config.Url == "" {
+			return nil, fmt.Errorf("face verification provider %s requires url", config.Provider)
+		}
+		return NewHttpFaceVerifier(config.Url, config.ApiKey, config.Timeout, config.MinConfidence), nil
+	default:
+		return nil, fmt.Errorf("unknown face verification provider %q", config.Provider)
+	}
+}
@@ -0,0 +1,87 @@
+package faceverification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+)
+
+const ProviderHttp = "http"
+
+type httpFaceVerifier struct {
+	client        *http.Client
+	url           string
+	apiKey        string
+	minConfidence float64
+}
+
+type httpFaceVerificationRequest struct {
+	CustomerID int    `json:"customer_id"`
+	MimeType   string `json:"mime_type"`
+	Image      string `json:"image"`
+}
+
+type httpFaceVerificationResponse struct {
+	Verified     bool    `json:"verified"`
+	Inconclusive bool    `json:"inconclusive"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// NewHttpFaceVerifier client of an external verification service, the photo is posted base64 encoded as json
+// and the face is verified when the service reports it with at least minConfidence
+func NewHttpFaceVerifier(url, apiKey string, timeout time.Duration, minConfidence float64) domain.FaceVerifier {
+	return &httpFaceVerifier{
+		client:        &http.Client{Timeout: timeout},
+		url:           url,
+		apiKey:        apiKey,
+		minConfidence: minConfidence,
+	}
+}
+
+func (v httpFaceVerifier) Verify(ctx context.Context, input domain.FaceVerificationInput) (domain.FaceVerificationResult, error) {
+	result := domain.FaceVerificationResult{Provider: ProviderHttp}
+
+	body, err := json.Marshal(httpFaceVerificationRequest{
+		CustomerID: input.CustomerID,
+		MimeType:   input.MimeType,
+		Image:      base64.StdEncoding.EncodeToString(input.Data),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.apiKey != "" {
+		req.Header.Set("X-API-KEY", v.apiKey)
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("face verification service responded with status %d", res.StatusCode)
+	}
+
+	var verification httpFaceVerificationResponse
+	if err := json.NewDecoder(res.Body).Decode(&verification); err != nil {
+		return result, err
+	}
+
+	result.Confidence = verification.Confidence
+	result.Verified = verification.Verified && verification.Confidence >= v.minConfidence
+	result.Inconclusive = !result.Verified && (verification.Inconclusive || verification.Verified)
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package faceverification
+
+import (
+	"context"
+	"image"
+	"image/color"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+)
+
+const ProviderLocal = "local"
+
+// maxSamples upper bound of pixels sampled on each axis
+const maxSamples = 200
+
+type localFaceVerifier struct {
+	minSkinRatio          float64
+	inconclusiveSkinRatio float64
+}
+
+// NewLocalFaceVerifier deterministic heuristic measuring the skin tone ratio in the center of the photo,
+// photos at or above minSkinRatio are verified, photos between inconclusiveSkinRatio and minSkinRatio are inconclusive
+func NewLocalFaceVerifier(minSkinRatio, inconclusiveSkinRatio float64) domain.FaceVerifier {
+	return &localFaceVerifier{
+		minSkinRatio:          minSkinRatio,
+		inconclusiveSkinRatio: inconclusiveSkinRatio,
+	}
+}
+
+func (v localFaceVerifier) Verify(ctx context.Context, input domain.FaceVerificationInput) (domain.FaceVerificationResult, error) {
+	result := domain.FaceVerificationResult{Provider: ProviderLocal}
+	if input.Image == nil {
+		return result, nil
+	}
+
+	ratio := skinRatio(centerRegion(input.Image.Bounds()), input.Image)
+
+	result.Confidence = ratio / v.minSkinRatio
+	if result.Confidence > 1 {
+		result.Confidence = 1
+	}
+	result.Verified = ratio >= v.minSkinRatio
+	result.Inconclusive = !result.Verified && ratio >= v.inconclusiveSkinRatio
+	return result, nil
+}
+
+// centerRegion middle 60% of the photo where a portrait face is expected
+func centerRegion(bounds image.Rectangle) image.Rectangle {
+	marginX := bounds.Dx() / 5
+	marginY := bounds.Dy() / 5
+	return image.Rect(bounds.Min.X+marginX, bounds.Min.Y+marginY, bounds.Max.X-marginX, bounds.Max.Y-marginY)
+}
+
+// skinRatio share of sampled pixels inside the YCbCr skin tone range
+func skinRatio(region image.Rectangle, img image.Image) float64 {
+	stepX := region.Dx()/maxSamples + 1
+	stepY := region.Dy()/maxSamples + 1
+
+	total, skin := 0, 0
+	for y := region.Min.Y; y < region.Max.Y; y += stepY {
+		for x := region.Min.X; x < region.Max.X; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			_, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+			if cb >= 77 && cb <= 127 && cr >= 133 && cr <= 173 {
+				skin++
+			}
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(skin) / float64(total)
+}
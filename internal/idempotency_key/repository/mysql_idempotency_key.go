@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mysqlIdempotencyKeyRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlIdempotencyKeyRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlIdempotencyKeyRepository {
+	return &mysqlIdempotencyKeyRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlIdempotencyKeyRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlIdempotencyKeyRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StoreIgnoreDuplicate store the key unless it is already stored, false is returned when the insert was skipped
+func (c mysqlIdempotencyKeyRepository) StoreIgnoreDuplicate(ctx context.Context, data domain.IdempotencyKey) (bool, error) {
+
+	result := c.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&data)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (c mysqlIdempotencyKeyRepository) UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, keyHash string) error {
+
+	return c.db.WithContext(ctx).Table(domain.IdempotencyKey{}.TableName()).Select(field).Where("key_hash = ?", keyHash).Updates(values).Error
+}
+
+func (c mysqlIdempotencyKeyRepository) DeleteWithFilter(ctx context.Context, filter []string, args ...interface{}) (int, error) {
+
+	db := c.db.WithContext(ctx)
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	result := db.Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+// DeleteExpired delete at most limit keys expired before now
+func (c mysqlIdempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	var keyHashes []string
+
+	if err := c.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).
+		Where("expires_at < ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Pluck("key_hash", &keyHashes).Error; err != nil {
+		return 0, err
+	}
+	if len(keyHashes) == 0 {
+		return 0, nil
+	}
+
+	result := c.db.WithContext(ctx).Where("key_hash IN ? AND expires_at < ?", keyHashes, now).Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,156 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// ApiKeyScopesKey input data key of the scopes of an authenticated api key
+const ApiKeyScopesKey = "apiKeyScopes"
+
+type (
+	// ApiKeyAuthenticator looks a registered api key up and returns its scopes.
+	ApiKeyAuthenticator func(ctx context.Context, key string) ([]string, error)
+
+	// ApiKeyConfig defines the config for ApiKey middleware.
+	ApiKeyConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Header carrying the api key.
+		// Optional. Default value X-API-KEY.
+		Header string
+
+		// ApiKey expected value, every request is rejected when empty and no Authenticator is set.
+		ApiKey string
+
+		// Scopes scopes of ApiKey stored in the ApiKeyScopesKey input data.
+		// Optional.
+		Scopes []string
+
+		// Authenticator checks the api keys other than ApiKey, the scopes of the key are stored
+		// in the ApiKeyScopesKey input data.
+		// Optional. Errors other than response.ErrApiKeyNotRegistered, response.ErrApiKeyRevoked
+		// and response.ErrApiKeyExpired are server errors.
+		Authenticator ApiKeyAuthenticator
+	}
+)
+
+var (
+	errMissingApiKey = errors.New("api key is missing")
+	errInvalidApiKey = errors.New("api key is invalid")
+)
+
+// ApiKey returns a middleware rejecting requests without the api key in the X-API-KEY header.
+func ApiKey(apiKey string) beego.FilterChain {
+	return ApiKeyWithConfig(ApiKeyConfig{
+		Skipper: DefaultSkipper,
+		ApiKey:  apiKey,
+	})
+}
+
+// RegisteredApiKey returns a middleware rejecting requests without an active registered api key
+// in the X-API-KEY header.
+func RegisteredApiKey(authenticator ApiKeyAuthenticator) beego.FilterChain {
+	return ApiKeyWithConfig(ApiKeyConfig{
+		Skipper:       DefaultSkipper,
+		Authenticator: authenticator,
+	})
+}
+
+// ApiKeyWithConfig returns an api key middleware with config.
+func ApiKeyWithConfig(config ApiKeyConfig) beego.FilterChain {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSkipper
+	}
+	if config.Header == "" {
+		config.Header = "X-API-KEY"
+	}
+
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *beegoContext.Context) {
+			if config.Skipper(ctx) {
+				next(ctx)
+				return
+			}
+
+			lang := helper.GetLangVersion(ctx)
+			key := ctx.Request.Header.Get(config.Header)
+			if key == "" {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.MissingApiKeyCodeError, response.ErrorCodeText(response.MissingApiKeyCodeError, lang), errMissingApiKey)
+				return
+			}
+			if config.ApiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(config.ApiKey)) == 1 {
+				ctx.Input.SetData(ApiKeyScopesKey, config.Scopes)
+				next(ctx)
+				return
+			}
+			if config.Authenticator == nil {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.InvalidApiKeyCodeError, response.ErrorCodeText(response.InvalidApiKeyCodeError, lang), errInvalidApiKey)
+				return
+			}
+
+			scopes, err := config.Authenticator(ctx.Request.Context(), key)
+			switch {
+			case errors.Is(err, response.ErrApiKeyNotRegistered):
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.ApiKeyNotRegisteredCodeError, response.ErrorCodeText(response.ApiKeyNotRegisteredCodeError, lang), err)
+				return
+			case errors.Is(err, response.ErrApiKeyRevoked), errors.Is(err, response.ErrApiKeyExpired):
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.InvalidApiKeyCodeError, response.ErrorCodeText(response.InvalidApiKeyCodeError, lang), err)
+				return
+			case err != nil:
+				response.ApiResponse{}.ResponseError(ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, lang), err)
+				return
+			}
+
+			ctx.Input.SetData(ApiKeyScopesKey, scopes)
+			next(ctx)
+		}
+	}
+}
+
+// ApiKeySkipper returns a Skipper skipping the requests whose api key of config is granted one of the
+// permissions, e.g. staff reading a customer resource otherwise checked by the Jwt middleware. The
+// scopes of the key are stored in the ApiKeyScopesKey input data, a missing, unknown or failing key
+// is not skipped.
+func ApiKeySkipper(config ApiKeyConfig, permissions ...string) Skipper {
+	if config.Header == "" {
+		config.Header = "X-API-KEY"
+	}
+
+	return func(ctx *beegoContext.Context) bool {
+		key := ctx.Request.Header.Get(config.Header)
+		if key == "" {
+			return false
+		}
+
+		var scopes []string
+		switch {
+		case config.ApiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(config.ApiKey)) == 1:
+			scopes = config.Scopes
+		case config.Authenticator != nil:
+			var err error
+			if scopes, err = config.Authenticator(ctx.Request.Context(), key); err != nil {
+				return false
+			}
+		default:
+			return false
+		}
+
+		for _, permission := range permissions {
+			if HasPermission(scopes, permission) {
+				ctx.Input.SetData(ApiKeyScopesKey, scopes)
+				return true
+			}
+		}
+		return false
+	}
+}
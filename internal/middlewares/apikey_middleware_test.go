@@ -0,0 +1,252 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// registeredApiKeys authenticator of the keys of the map, a key mapped to an error fails with it
+func registeredApiKeys(keys map[string]interface{}) ApiKeyAuthenticator {
+	return func(ctx context.Context, key string) ([]string, error) {
+		switch value := keys[key].(type) {
+		case []string:
+			return value, nil
+		case error:
+			return nil, value
+		default:
+			return nil, response.ErrApiKeyNotRegistered
+		}
+	}
+}
+
+func TestApiKeyWithConfig(t *testing.T) {
+	authenticator := registeredApiKeys(map[string]interface{}{
+		"partner": []string{"customers:read"},
+		"revoked": response.ErrApiKeyRevoked,
+		"expired": response.ErrApiKeyExpired,
+		"failing": errors.New("connection refused"),
+	})
+
+	tests := []struct {
+		name       string
+		config     ApiKeyConfig
+		method     string
+		key        string
+		wantStatus int
+		wantCode   string
+		wantNext   bool
+		wantScopes []string
+	}{
+		{
+			name:       "request without api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.MissingApiKeyCodeError,
+		},
+		{
+			name:       "wrong api key without authenticator",
+			config:     ApiKeyConfig{ApiKey: "admin"},
+			key:        "other",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.InvalidApiKeyCodeError,
+		},
+		{
+			name:       "admin api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Scopes: []string{PermissionAll}, Authenticator: authenticator},
+			key:        "admin",
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantScopes: []string{PermissionAll},
+		},
+		{
+			name:       "registered api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			key:        "partner",
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantScopes: []string{"customers:read"},
+		},
+		{
+			name:       "api key in another header",
+			config:     ApiKeyConfig{Header: "X-PARTNER-KEY", Authenticator: authenticator},
+			key:        "partner",
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantScopes: []string{"customers:read"},
+		},
+		{
+			name:       "unknown api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			key:        "unknown",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.ApiKeyNotRegisteredCodeError,
+		},
+		{
+			name:       "revoked api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			key:        "revoked",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.InvalidApiKeyCodeError,
+		},
+		{
+			name:       "expired api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			key:        "expired",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.InvalidApiKeyCodeError,
+		},
+		{
+			name:       "failing authenticator",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator},
+			key:        "failing",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   response.ServerErrorCode,
+		},
+		{
+			name:       "campaigns listed without api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator, Skipper: MethodSkipper(http.MethodGet)},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+		{
+			name:       "campaign created without api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Authenticator: authenticator, Skipper: MethodSkipper(http.MethodGet)},
+			method:     http.MethodPost,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.MissingApiKeyCodeError,
+		},
+		{
+			name:       "campaign created with the admin api key",
+			config:     ApiKeyConfig{ApiKey: "admin", Scopes: []string{PermissionAll}, Authenticator: authenticator, Skipper: MethodSkipper(http.MethodGet)},
+			method:     http.MethodPost,
+			key:        "admin",
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantScopes: []string{PermissionAll},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := ApiKeyWithConfig(tt.config)(func(ctx *beegoContext.Context) {
+				called = true
+				ctx.Output.SetStatus(http.StatusOK)
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			header := tt.config.Header
+			if header == "" {
+				header = "X-API-KEY"
+			}
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(method, "/api/v1/campaigns", nil)
+			if tt.key != "" {
+				request.Header.Set(header, tt.key)
+			}
+			ctx := beegoContext.NewContext()
+			ctx.Reset(recorder, request)
+			handler(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			if tt.wantCode != "" && !strings.Contains(recorder.Body.String(), tt.wantCode) {
+				t.Errorf("body = %s, want code %s", recorder.Body.String(), tt.wantCode)
+			}
+			scopes, _ := ctx.Input.GetData(ApiKeyScopesKey).([]string)
+			if !reflect.DeepEqual(scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestApiKeySkipper(t *testing.T) {
+	config := ApiKeyConfig{
+		ApiKey: "admin",
+		Scopes: []string{PermissionAll},
+		Authenticator: registeredApiKeys(map[string]interface{}{
+			"support":  []string{"customers:read"},
+			"auditor":  []string{"reviews:read"},
+			"campaign": []string{"campaigns:write"},
+			"revoked":  response.ErrApiKeyRevoked,
+		}),
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		want       bool
+		wantScopes []string
+	}{
+		{
+			name: "request without api key",
+		},
+		{
+			name:       "admin api key",
+			key:        "admin",
+			want:       true,
+			wantScopes: []string{PermissionAll},
+		},
+		{
+			name:       "api key granted customers:read",
+			key:        "support",
+			want:       true,
+			wantScopes: []string{"customers:read"},
+		},
+		{
+			name:       "api key granted reviews:read",
+			key:        "auditor",
+			want:       true,
+			wantScopes: []string{"reviews:read"},
+		},
+		{
+			name: "api key without the permissions",
+			key:  "campaign",
+		},
+		{
+			name: "revoked api key",
+			key:  "revoked",
+		},
+		{
+			name: "unknown api key",
+			key:  "unknown",
+		},
+	}
+
+	skipper := ApiKeySkipper(config, "customers:read", "reviews:read")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/customers/1/bookings", nil)
+			if tt.key != "" {
+				request.Header.Set("X-API-KEY", tt.key)
+			}
+			ctx := beegoContext.NewContext()
+			ctx.Reset(httptest.NewRecorder(), request)
+
+			if got := skipper(ctx); got != tt.want {
+				t.Errorf("skipped = %v, want %v", got, tt.want)
+			}
+			scopes, _ := ctx.Input.GetData(ApiKeyScopesKey).([]string)
+			if !reflect.DeepEqual(scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+		})
+	}
+}
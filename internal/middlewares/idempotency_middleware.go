@@ -0,0 +1,240 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+)
+
+// IdempotentReplayedHeader response header set on the replayed responses
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyKeyMaxLength longest accepted header value
+const idempotencyKeyMaxLength = 255
+
+type (
+	// IdempotencyConfig defines the config for Idempotency middleware.
+	IdempotencyConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Header carrying the key chosen by the client.
+		// Optional. Default value Idempotency-Key.
+		Header string
+
+		// Store keeping the responses.
+		// Required.
+		Store IdempotencyStore
+
+		// Methods http methods replaying the responses.
+		// Optional. Default value POST.
+		Methods []string
+
+		// Client returns the client a key is scoped to besides the route, e.g. RateLimitKeyByCustomer
+		// or RateLimitKeyByApiKey. The requests without a client are not replayed.
+		// Optional. Default value RateLimitKeyByCustomer(":id").
+		Client func(ctx *beegoContext.Context) string
+
+		// MaxBytes largest request body buffered for the fingerprint, a larger body is rejected with 413.
+		// Optional. Default value beego.BConfig.MaxUploadSize.
+		MaxBytes int64
+
+		// LockTimeout time a key stays claimed by a request without a response, e.g. when the instance crashed.
+		// Optional. Default value 1 minute.
+		LockTimeout time.Duration
+
+		// Expiration time a response is replayed.
+		// Optional. Default value 24 hours.
+		Expiration time.Duration
+
+		// Logger logs the store errors.
+		// Optional.
+		Logger zaplogger.Logger
+	}
+)
+
+var (
+	errIdempotencyKeyInvalid    = errors.New("idempotency key is too long")
+	errIdempotencyKeyMismatch   = errors.New("idempotency key was used for a different request")
+	errIdempotencyKeyInProgress = errors.New("idempotency key is in progress")
+)
+
+// Idempotency returns a middleware storing the response of a POST request sent with an Idempotency-Key header
+// and replaying it to the retries of the request by the same customer on the same route.
+//
+//	beego.InsertFilterChain("/api/v1/verify-photo/:id", middlewares.Idempotency(store))
+func Idempotency(store IdempotencyStore) beego.FilterChain {
+	return IdempotencyWithConfig(IdempotencyConfig{
+		Skipper: DefaultSkipper,
+		Store:   store,
+	})
+}
+
+// IdempotencyWithConfig returns an idempotency middleware with config.
+// A retry with another body is rejected with 422 and a retry sent before the response with 409. Server errors and
+// the 408, 409 and 429 responses are not stored, the request may be retried with the same key.
+func IdempotencyWithConfig(config IdempotencyConfig) beego.FilterChain {
+	// Defaults
+	if config.Store == nil {
+		panic("idempotency middleware requires a store")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultSkipper
+	}
+	if config.Header == "" {
+		config.Header = "Idempotency-Key"
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = []string{http.MethodPost}
+	}
+	if config.Client == nil {
+		config.Client = RateLimitKeyByCustomer(":id")
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = beego.BConfig.MaxUploadSize
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = time.Minute
+	}
+	if config.Expiration <= 0 {
+		config.Expiration = 24 * time.Hour
+	}
+
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *beegoContext.Context) {
+			value := ctx.Request.Header.Get(config.Header)
+			if value == "" || config.Skipper(ctx) || !permissionMethod(config.Methods, ctx.Request.Method) {
+				next(ctx)
+				return
+			}
+
+			client := config.Client(ctx)
+			if client == "" {
+				next(ctx)
+				return
+			}
+
+			lang := helper.GetLangVersion(ctx)
+			if len(value) > idempotencyKeyMaxLength {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusBadRequest, response.IdempotencyKeyInvalid, response.ErrorCodeText(response.IdempotencyKeyInvalid, lang), errIdempotencyKeyInvalid)
+				return
+			}
+
+			var body []byte
+			if ctx.Request.Body != nil {
+				var err error
+				if body, err = ioutil.ReadAll(http.MaxBytesReader(ctx.ResponseWriter, ctx.Request.Body, config.MaxBytes)); err != nil {
+					// the reader stops at the limit, a shorter body failed for another reason
+					if int64(len(body)) >= config.MaxBytes {
+						response.ApiResponse{}.ResponseError(ctx, http.StatusRequestEntityTooLarge, response.RequestBodyTooLarge, response.ErrorCodeText(response.RequestBodyTooLarge, lang), err)
+						return
+					}
+					response.ApiResponse{}.ResponseError(ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, lang), err)
+					return
+				}
+			}
+			ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+			fingerprint := idempotencyFingerprint(ctx.Request.Header.Get("Content-Type"), body)
+			sum := sha256.Sum256([]byte(client + "\n" + ctx.Request.Method + " " + ctx.Request.URL.Path + "\n" + value))
+			key := hex.EncodeToString(sum[:])
+
+			record, locked, err := config.Store.Lock(ctx.Request.Context(), key, fingerprint, config.LockTimeout)
+			if err != nil {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, lang), err)
+				return
+			}
+			if !locked {
+				switch {
+				case record.Fingerprint != fingerprint:
+					response.ApiResponse{}.ResponseError(ctx, http.StatusUnprocessableEntity, response.IdempotencyKeyMismatch, response.ErrorCodeText(response.IdempotencyKeyMismatch, lang), errIdempotencyKeyMismatch)
+				case record.StatusCode == 0:
+					response.ApiResponse{}.ResponseError(ctx, http.StatusConflict, response.IdempotencyKeyInProgress, response.ErrorCodeText(response.IdempotencyKeyInProgress, lang), errIdempotencyKeyInProgress)
+				default:
+					if record.ContentType != "" {
+						ctx.Output.Header("Content-Type", record.ContentType)
+					}
+					ctx.Output.Header(IdempotentReplayedHeader, "true")
+					ctx.Output.SetStatus(record.StatusCode)
+					_ = ctx.Output.Body(record.Body)
+				}
+				return
+			}
+
+			// the store is updated after the request, also when the client is gone
+			saved := false
+			defer func() {
+				if saved {
+					return
+				}
+				if err := config.Store.Unlock(context.Background(), key); err != nil && config.Logger != nil {
+					config.Logger.Errorf("idempotency unlock: %v", err)
+				}
+			}()
+
+			// Response
+			resBody := new(bytes.Buffer)
+			mw := io.MultiWriter(ctx.ResponseWriter.ResponseWriter, resBody)
+			ctx.ResponseWriter.ResponseWriter = &bodyDumpResponseWriter{Writer: mw, ResponseWriter: ctx.ResponseWriter.ResponseWriter}
+
+			next(ctx)
+
+			status := ctx.ResponseWriter.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if !idempotencyStorable(status) {
+				return
+			}
+			if err := config.Store.Save(context.Background(), key, IdempotencyRecord{
+				Fingerprint: fingerprint,
+				StatusCode:  status,
+				ContentType: ctx.ResponseWriter.Header().Get("Content-Type"),
+				Body:        resBody.Bytes(),
+			}, config.Expiration); err != nil {
+				if config.Logger != nil {
+					config.Logger.Errorf("idempotency save: %v", err)
+				}
+				return
+			}
+			saved = true
+		}
+	}
+}
+
+// idempotencyFingerprint hash of the media type and the body, the boundary of a multipart body is left out
+// as a retry may be sent with another one
+func idempotencyFingerprint(contentType string, body []byte) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+	hash := sha256.New()
+	hash.Write([]byte(mediaType))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyStorable responses replayed to the retries, the others may succeed when retried
+func idempotencyStorable(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
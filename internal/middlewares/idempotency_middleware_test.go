@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+// countingIdempotencyStore store claiming every key, counting the claims
+type countingIdempotencyStore struct {
+	locks int
+}
+
+func (s *countingIdempotencyStore) Lock(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (IdempotencyRecord, bool, error) {
+	s.locks++
+	return IdempotencyRecord{}, true, nil
+}
+
+func (s *countingIdempotencyStore) Save(ctx context.Context, key string, record IdempotencyRecord, expiration time.Duration) error {
+	return nil
+}
+
+func (s *countingIdempotencyStore) Unlock(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestIdempotencyWithConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     string
+		body       string
+		wantStatus int
+		wantLocks  int
+		wantNext   bool
+	}{
+		{
+			name:       "request of a client is claimed",
+			client:     "customer:1",
+			body:       "small",
+			wantStatus: http.StatusOK,
+			wantLocks:  1,
+			wantNext:   true,
+		},
+		{
+			name:       "body above the limit is rejected",
+			client:     "customer:1",
+			body:       strings.Repeat("x", 64),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "request without client is not replayed",
+			body:       strings.Repeat("x", 64),
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &countingIdempotencyStore{}
+			middleware := IdempotencyWithConfig(IdempotencyConfig{
+				Store:    store,
+				MaxBytes: 16,
+				Client: func(ctx *beegoContext.Context) string {
+					return tt.client
+				},
+			})
+
+			called := false
+			handler := middleware(func(ctx *beegoContext.Context) {
+				called = true
+				ctx.Output.SetStatus(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/verify-photo/1", strings.NewReader(tt.body))
+			request.Header.Set("Idempotency-Key", "key")
+			ctx := beegoContext.NewContext()
+			ctx.Reset(recorder, request)
+			handler(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if store.locks != tt.wantLocks {
+				t.Errorf("locks = %d, want %d", store.locks, tt.wantLocks)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	// IdempotencyStore keeps the responses of the Idempotency middleware.
+	IdempotencyStore interface {
+		// Lock claims the key for a request with the fingerprint until the lock timeout, the record of the key is
+		// returned with false when the key is already claimed or holds a response.
+		Lock(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (IdempotencyRecord, bool, error)
+		// Save stores the response of a claimed key until the expiration.
+		Save(ctx context.Context, key string, record IdempotencyRecord, expiration time.Duration) error
+		// Unlock releases a claimed key without a response, the request may be sent again.
+		Unlock(ctx context.Context, key string) error
+	}
+
+	// IdempotencyRecord request and response of a key.
+	IdempotencyRecord struct {
+		// Fingerprint hash of the request body.
+		Fingerprint string `json:"fingerprint"`
+		// StatusCode status of the response, 0 while the request is processed.
+		StatusCode  int    `json:"status_code"`
+		ContentType string `json:"content_type"`
+		Body        []byte `json:"body"`
+	}
+)
+
+var errIdempotencyKeyContended = errors.New("idempotency key is contended")
+
+type databaseIdempotencyStore struct {
+	mysqlIdempotencyKeyRepository domain.MysqlIdempotencyKeyRepository
+}
+
+// NewDatabaseIdempotencyStore returns a store keeping the responses in the idempotency_keys table,
+// expired keys are removed by a job.
+func NewDatabaseIdempotencyStore(mysqlIdempotencyKeyRepository domain.MysqlIdempotencyKeyRepository) IdempotencyStore {
+	return &databaseIdempotencyStore{
+		mysqlIdempotencyKeyRepository: mysqlIdempotencyKeyRepository,
+	}
+}
+
+func (s *databaseIdempotencyStore) Lock(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (IdempotencyRecord, bool, error) {
+	now := time.Now()
+	for attempt := 0; attempt < 2; attempt++ {
+		stored, err := s.mysqlIdempotencyKeyRepository.StoreIgnoreDuplicate(ctx, domain.IdempotencyKey{
+			KeyHash:     key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   now.Add(lockTimeout),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		if stored {
+			return IdempotencyRecord{Fingerprint: fingerprint}, true, nil
+		}
+
+		var entity domain.IdempotencyKey
+		err = s.mysqlIdempotencyKeyRepository.SingleWithFilter(ctx, []string{"*"}, []string{}, []string{"key_hash = ?"}, &entity, key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// removed since the insert
+			continue
+		}
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		if now.Before(entity.ExpiresAt) {
+			return IdempotencyRecord{
+				Fingerprint: entity.Fingerprint,
+				StatusCode:  entity.StatusCode,
+				ContentType: entity.ContentType,
+				Body:        entity.Body,
+			}, false, nil
+		}
+
+		// an expired key, e.g. the lock of a crashed instance, is claimed again unless another request did first
+		if _, err := s.mysqlIdempotencyKeyRepository.DeleteWithFilter(ctx, []string{"key_hash = ?", "expires_at = ?"}, key, entity.ExpiresAt); err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+	}
+	return IdempotencyRecord{}, false, errIdempotencyKeyContended
+}
+
+func (s *databaseIdempotencyStore) Save(ctx context.Context, key string, record IdempotencyRecord, expiration time.Duration) error {
+	now := time.Now()
+	return s.mysqlIdempotencyKeyRepository.UpdateSelectedField(ctx,
+		[]string{"status_code", "content_type", "body", "expires_at", "updated_at"},
+		map[string]interface{}{
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+			"expires_at":   now.Add(expiration),
+			"updated_at":   now,
+		}, key)
+}
+
+func (s *databaseIdempotencyStore) Unlock(ctx context.Context, key string) error {
+	_, err := s.mysqlIdempotencyKeyRepository.DeleteWithFilter(ctx, []string{"key_hash = ?"}, key)
+	return err
+}
+
+type redisIdempotencyStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisIdempotencyStore returns a store keeping the responses in redis below the key prefix,
+// the keys expire with their lock timeout or expiration.
+func NewRedisIdempotencyStore(client redis.UniversalClient, prefix string) IdempotencyStore {
+	return &redisIdempotencyStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *redisIdempotencyStore) Lock(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (IdempotencyRecord, bool, error) {
+	record := IdempotencyRecord{Fingerprint: fingerprint}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		locked, err := s.client.SetNX(ctx, s.prefix+key, value, lockTimeout).Result()
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		if locked {
+			return record, true, nil
+		}
+
+		stored, err := s.client.Get(ctx, s.prefix+key).Bytes()
+		if err == redis.Nil {
+			// expired since the SETNX
+			continue
+		}
+		if err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		var current IdempotencyRecord
+		if err := json.Unmarshal(stored, &current); err != nil {
+			return IdempotencyRecord{}, false, err
+		}
+		return current, false, nil
+	}
+	return IdempotencyRecord{}, false, errIdempotencyKeyContended
+}
+
+func (s *redisIdempotencyStore) Save(ctx context.Context, key string, record IdempotencyRecord, expiration time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+key, value, expiration).Err()
+}
+
+func (s *redisIdempotencyStore) Unlock(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
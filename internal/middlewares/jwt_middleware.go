@@ -0,0 +1,156 @@
+package middlewares
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// signing algorithms of the Jwt middleware
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+)
+
+// JwtClaimsKey input data key of the claims of a valid token
+const JwtClaimsKey = "jwtClaims"
+
+type (
+	// JwtConfig defines the config for Jwt middleware.
+	JwtConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Header carrying the token after the Bearer scheme.
+		// Optional. Default value Authorization.
+		Header string
+
+		// Algorithm signing algorithm of the tokens, HS256 or RS256.
+		Algorithm string
+
+		// Secret key of HS256 tokens, required by HS256.
+		Secret []byte
+
+		// PublicKey key of RS256 tokens, required by RS256.
+		PublicKey *rsa.PublicKey
+
+		// Issuer expected iss claim.
+		// Optional. The issuer is not checked when empty.
+		Issuer string
+
+		// Audience expected aud claim.
+		// Optional. The audience is not checked when empty.
+		Audience string
+
+		// SubjectParam path parameter the sub claim must be equal to, e.g. :id.
+		// Optional. The subject is not checked when empty.
+		SubjectParam string
+	}
+)
+
+var (
+	errMissingToken    = errors.New("token is missing")
+	errInvalidToken    = errors.New("token is invalid")
+	errExpiredToken    = errors.New("token is expired")
+	errSubjectMismatch = errors.New("token subject does not match the requested resource")
+)
+
+// Jwt returns a middleware rejecting requests without a valid bearer token signed with the key
+// whose subject is not the :id path parameter. key is the HS256 secret or the RS256 public key.
+func Jwt(algorithm string, key interface{}) beego.FilterChain {
+	config := JwtConfig{
+		Skipper:      DefaultSkipper,
+		Algorithm:    algorithm,
+		SubjectParam: ":id",
+	}
+	switch value := key.(type) {
+	case []byte:
+		config.Secret = value
+	case *rsa.PublicKey:
+		config.PublicKey = value
+	}
+	return JwtWithConfig(config)
+}
+
+// JwtWithConfig returns a jwt middleware with config.
+// It panics when the algorithm is not supported or its key is missing.
+func JwtWithConfig(config JwtConfig) beego.FilterChain {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSkipper
+	}
+	if config.Header == "" {
+		config.Header = "Authorization"
+	}
+
+	var key interface{}
+	switch config.Algorithm {
+	case JwtAlgorithmHS256:
+		if len(config.Secret) == 0 {
+			panic("jwt middleware: HS256 requires a secret")
+		}
+		key = config.Secret
+	case JwtAlgorithmRS256:
+		if config.PublicKey == nil {
+			panic("jwt middleware: RS256 requires a public key")
+		}
+		key = config.PublicKey
+	default:
+		panic("jwt middleware: unsupported algorithm " + config.Algorithm)
+	}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{config.Algorithm}))
+
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *beegoContext.Context) {
+			if config.Skipper(ctx) {
+				next(ctx)
+				return
+			}
+
+			lang := helper.GetLangVersion(ctx)
+			token := bearerToken(ctx.Request.Header.Get(config.Header))
+			if token == "" {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.MissingTokenCodeError, response.ErrorCodeText(response.MissingTokenCodeError, lang), errMissingToken)
+				return
+			}
+
+			claims := &jwt.RegisteredClaims{}
+			_, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+				return key, nil
+			})
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.ExpiredTokenCodeError, response.ErrorCodeText(response.ExpiredTokenCodeError, lang), errExpiredToken)
+				return
+			}
+			if err != nil ||
+				(config.Issuer != "" && !claims.VerifyIssuer(config.Issuer, true)) ||
+				(config.Audience != "" && !claims.VerifyAudience(config.Audience, true)) {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusUnauthorized, response.InvalidTokenCodeError, response.ErrorCodeText(response.InvalidTokenCodeError, lang), errInvalidToken)
+				return
+			}
+			if config.SubjectParam != "" && ctx.Input.Param(config.SubjectParam) != claims.Subject {
+				response.ApiResponse{}.ResponseError(ctx, http.StatusForbidden, response.RequestForbiddenCodeError, response.ErrorCodeText(response.RequestForbiddenCodeError, lang), errSubjectMismatch)
+				return
+			}
+
+			ctx.Input.SetData(JwtClaimsKey, claims)
+			next(ctx)
+		}
+	}
+}
+
+// bearerToken token of an Authorization header value using the Bearer scheme
+func bearerToken(header string) string {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,175 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+var jwtTestSecret = []byte("secret")
+
+func signJwt(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJwtWithConfig(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := jwt.RegisteredClaims{
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	tests := []struct {
+		name          string
+		config        JwtConfig
+		authorization string
+		wantStatus    int
+		wantCode      string
+		wantNext      bool
+	}{
+		{
+			name:          "token of the customer of the path",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, valid),
+			wantStatus:    http.StatusOK,
+			wantNext:      true,
+		},
+		{
+			name:          "rs256 token verified with the public key",
+			config:        JwtConfig{Algorithm: JwtAlgorithmRS256, PublicKey: &rsaKey.PublicKey, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodRS256, rsaKey, valid),
+			wantStatus:    http.StatusOK,
+			wantNext:      true,
+		},
+		{
+			name:       "missing header",
+			config:     JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.MissingTokenCodeError,
+		},
+		{
+			name:          "header without the bearer scheme",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Basic " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, valid),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      response.MissingTokenCodeError,
+		},
+		{
+			name:   "expired token",
+			config: JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, jwt.RegisteredClaims{
+				Subject:   "1",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			}),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.ExpiredTokenCodeError,
+		},
+		{
+			name:          "token signed with another algorithm",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS512, jwtTestSecret, valid),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      response.InvalidTokenCodeError,
+		},
+		{
+			name:          "hs256 token signed with the public key of rs256",
+			config:        JwtConfig{Algorithm: JwtAlgorithmRS256, PublicKey: &rsaKey.PublicKey, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, valid),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      response.InvalidTokenCodeError,
+		},
+		{
+			name:          "token signed with another secret",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, []byte("other"), valid),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      response.InvalidTokenCodeError,
+		},
+		{
+			name:          "token of another issuer",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, Issuer: "auth", SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, jwt.RegisteredClaims{Subject: "1", Issuer: "other"}),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      response.InvalidTokenCodeError,
+		},
+		{
+			name:          "token of another customer",
+			config:        JwtConfig{Algorithm: JwtAlgorithmHS256, Secret: jwtTestSecret, SubjectParam: ":id"},
+			authorization: "Bearer " + signJwt(t, jwt.SigningMethodHS256, jwtTestSecret, jwt.RegisteredClaims{Subject: "2"}),
+			wantStatus:    http.StatusForbidden,
+			wantCode:      response.RequestForbiddenCodeError,
+		},
+		{
+			name: "skipped request",
+			config: JwtConfig{
+				Algorithm:    JwtAlgorithmHS256,
+				Secret:       jwtTestSecret,
+				SubjectParam: ":id",
+				Skipper: func(ctx *beegoContext.Context) bool {
+					return true
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := JwtWithConfig(tt.config)(func(ctx *beegoContext.Context) {
+				called = true
+				ctx.Output.SetStatus(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/customers/1/eligibility", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			ctx := beegoContext.NewContext()
+			ctx.Reset(recorder, request)
+			ctx.Input.SetParam(":id", "1")
+			handler(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			if tt.wantCode != "" && !strings.Contains(recorder.Body.String(), tt.wantCode) {
+				t.Errorf("body = %s, want code %s", recorder.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJwtWithConfigWithoutKey(t *testing.T) {
+	for _, algorithm := range []string{JwtAlgorithmHS256, JwtAlgorithmRS256, "none"} {
+		t.Run(algorithm, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("JwtWithConfig(%s) without key did not panic", algorithm)
+				}
+			}()
+			JwtWithConfig(JwtConfig{Algorithm: algorithm})
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package middlewares
 
-import beegoContext "github.com/beego/beego/v2/server/web/context"
+import (
+	"strings"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
 
 type (
 	Skipper func(*beegoContext.Context) bool
@@ -9,3 +13,16 @@ type (
 func DefaultSkipper(*beegoContext.Context) bool {
 	return false
 }
+
+// MethodSkipper returns a Skipper skipping the requests with one of the http methods, e.g. the GET requests
+// of routes readable by everyone
+func MethodSkipper(methods ...string) Skipper {
+	return func(ctx *beegoContext.Context) bool {
+		for _, method := range methods {
+			if strings.EqualFold(method, ctx.Request.Method) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// PermissionAll permission granting every permission, e.g. to the admin api key
+const PermissionAll = "*"
+
+type (
+	// PermissionConfig defines the config for RequirePermission middleware.
+	PermissionConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Permission required from the caller, checked against the ApiKeyScopesKey input data
+		// stored by the api key middleware registered before.
+		Permission string
+
+		// Methods http methods requiring the permission.
+		// Optional. Every method requires the permission when empty.
+		Methods []string
+	}
+)
+
+var errPermissionDenied = errors.New("permission denied")
+
+// RequirePermission returns a middleware rejecting callers without the permission on requests
+// with one of the http methods, or every request when no method is given.
+//
+//	beego.Router("/api/v1/admin/reviews/:id/approve", pHandler, "post:ApproveReview")
+//	beego.InsertFilterChain("/api/v1/admin/reviews/:id/approve", middlewares.RequirePermission("reviews:write"))
+func RequirePermission(permission string, methods ...string) beego.FilterChain {
+	return RequirePermissionWithConfig(PermissionConfig{
+		Skipper:    DefaultSkipper,
+		Permission: permission,
+		Methods:    methods,
+	})
+}
+
+// RequirePermissionWithConfig returns a permission middleware with config.
+func RequirePermissionWithConfig(config PermissionConfig) beego.FilterChain {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSkipper
+	}
+
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *beegoContext.Context) {
+			if config.Skipper(ctx) || !permissionMethod(config.Methods, ctx.Request.Method) {
+				next(ctx)
+				return
+			}
+
+			granted, _ := ctx.Input.GetData(ApiKeyScopesKey).([]string)
+			if !HasPermission(granted, config.Permission) {
+				lang := helper.GetLangVersion(ctx)
+				response.ApiResponse{}.ResponseError(ctx, http.StatusForbidden, response.RequestForbiddenCodeError, response.ErrorCodeText(response.RequestForbiddenCodeError, lang), errPermissionDenied)
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// HasPermission the granted permissions contain the permission or PermissionAll
+func HasPermission(granted []string, permission string) bool {
+	for _, value := range granted {
+		if value == PermissionAll || value == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func permissionMethod(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, value := range methods {
+		if strings.EqualFold(value, method) {
+			return true
+		}
+	}
+	return false
+}
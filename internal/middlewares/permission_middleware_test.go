@@ -0,0 +1,117 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+func TestRequirePermissionWithConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     PermissionConfig
+		method     string
+		scopes     []string
+		wantStatus int
+		wantNext   bool
+	}{
+		{
+			name:       "granted permission",
+			config:     PermissionConfig{Permission: "reviews:write"},
+			scopes:     []string{"reviews:read", "reviews:write"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+		{
+			name:       "every permission granted",
+			config:     PermissionConfig{Permission: "reviews:write"},
+			scopes:     []string{PermissionAll},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+		{
+			name:       "missing permission",
+			config:     PermissionConfig{Permission: "reviews:write"},
+			scopes:     []string{"reviews:read"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "request without api key scopes",
+			config:     PermissionConfig{Permission: "reviews:write"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "permission prefix is not granted",
+			config:     PermissionConfig{Permission: "reviews:write"},
+			scopes:     []string{"reviews", "reviews:*"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "method requiring the permission",
+			config:     PermissionConfig{Permission: "campaigns:write", Methods: []string{http.MethodPost, http.MethodPut}},
+			method:     http.MethodPut,
+			scopes:     []string{"customers:read"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "method given in lower case",
+			config:     PermissionConfig{Permission: "campaigns:write", Methods: []string{"post"}},
+			method:     http.MethodPost,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "method not requiring the permission",
+			config:     PermissionConfig{Permission: "campaigns:write", Methods: []string{http.MethodPost, http.MethodPut}},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+		{
+			name: "skipped request",
+			config: PermissionConfig{
+				Permission: "reviews:write",
+				Skipper: func(ctx *beegoContext.Context) bool {
+					return true
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := RequirePermissionWithConfig(tt.config)(func(ctx *beegoContext.Context) {
+				called = true
+				ctx.Output.SetStatus(http.StatusOK)
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			recorder := httptest.NewRecorder()
+			ctx := beegoContext.NewContext()
+			ctx.Reset(recorder, httptest.NewRequest(method, "/api/v1/admin/reviews/1/approve", nil))
+			if tt.scopes != nil {
+				ctx.Input.SetData(ApiKeyScopesKey, tt.scopes)
+			}
+			handler(ctx)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			if tt.wantStatus == http.StatusForbidden && !strings.Contains(recorder.Body.String(), response.RequestForbiddenCodeError) {
+				t.Errorf("body = %s, want code %s", recorder.Body.String(), response.RequestForbiddenCodeError)
+			}
+		})
+	}
+}
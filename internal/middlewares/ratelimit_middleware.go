@@ -0,0 +1,177 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+)
+
+type (
+	// RateLimitKeyFunc returns the client a request is counted for, the request is not limited when empty.
+	RateLimitKeyFunc func(ctx *beegoContext.Context) string
+
+	// RateLimitConfig defines the config for RateLimit middleware.
+	RateLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Name of the limit, separates the buckets of limits sharing a store.
+		// Optional. Default value ratelimit.
+		Name string
+
+		// Store keeping the buckets, a RedisRateLimitStore shares them between the instances.
+		// Optional. Default value an in-memory store.
+		Store RateLimitStore
+
+		// KeyFunc returns the client a request is counted for.
+		// Optional. Default value RateLimitKeyByIP.
+		KeyFunc RateLimitKeyFunc
+
+		// Limit requests a client may send at once, the bucket of the client holds at most Limit tokens.
+		Limit int
+
+		// Period time to refill an empty bucket, a token is added every Period / Limit.
+		Period time.Duration
+
+		// Logger logs the store errors, requests are not limited while the store is failing.
+		// Optional.
+		Logger zaplogger.Logger
+	}
+)
+
+var errRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimit returns a middleware allowing a client IP at most limit requests at once, refilled over the period,
+// and rejecting the other requests with 429 Too Many Requests.
+//
+//	beego.InsertFilterChain("/api/*", middlewares.RateLimit(120, time.Minute))
+func RateLimit(limit int, period time.Duration) beego.FilterChain {
+	return RateLimitWithConfig(RateLimitConfig{
+		Skipper: DefaultSkipper,
+		KeyFunc: RateLimitKeyByIP,
+		Limit:   limit,
+		Period:  period,
+	})
+}
+
+// RateLimitWithConfig returns a token bucket rate limit middleware with config.
+// The RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers are set on every limited
+// request and Retry-After on rejected ones.
+func RateLimitWithConfig(config RateLimitConfig) beego.FilterChain {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSkipper
+	}
+	if config.Name == "" {
+		config.Name = "ratelimit"
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitKeyByIP
+	}
+	if config.Limit < 1 || config.Period <= 0 {
+		panic("rate limit middleware requires a positive limit and period")
+	}
+	policy := strconv.Itoa(config.Limit) + ";w=" + strconv.FormatInt(int64(math.Ceil(config.Period.Seconds())), 10)
+
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *beegoContext.Context) {
+			if config.Skipper(ctx) {
+				next(ctx)
+				return
+			}
+			key := config.KeyFunc(ctx)
+			if key == "" {
+				next(ctx)
+				return
+			}
+
+			result, err := config.Store.Take(ctx.Request.Context(), config.Name+":"+key, config.Limit, config.Period)
+			if err != nil {
+				// an unavailable store does not take the api down
+				if config.Logger != nil {
+					config.Logger.Errorf("rate limit %s: %v", config.Name, err)
+				}
+				next(ctx)
+				return
+			}
+
+			header := ctx.ResponseWriter.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(config.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+			header.Set("RateLimit-Policy", policy)
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+				lang := helper.GetLangVersion(ctx)
+				response.ApiResponse{}.ResponseError(ctx, http.StatusTooManyRequests, response.TooManyRequestsCodeError, response.ErrorCodeText(response.TooManyRequestsCodeError, lang), errRateLimitExceeded)
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// RateLimitKeyByIP counts the requests per address of the connection.
+func RateLimitKeyByIP(ctx *beegoContext.Context) string {
+	if ip, _, err := net.SplitHostPort(ctx.Request.RemoteAddr); err == nil {
+		return "ip:" + ip
+	}
+	return "ip:" + ctx.Request.RemoteAddr
+}
+
+// RateLimitKeyByForwardedIP counts the requests per client address of the X-Forwarded-For header,
+// only use it behind a proxy setting the header.
+func RateLimitKeyByForwardedIP(ctx *beegoContext.Context) string {
+	return "ip:" + ctx.Input.IP()
+}
+
+// RateLimitKeyByApiKey counts the requests per api key of the header, requests without the header are not limited.
+func RateLimitKeyByApiKey(header string) RateLimitKeyFunc {
+	return func(ctx *beegoContext.Context) string {
+		key := ctx.Request.Header.Get(header)
+		if key == "" {
+			return ""
+		}
+		// the key itself is not kept in the store
+		sum := sha256.Sum256([]byte(key))
+		return "apikey:" + hex.EncodeToString(sum[:16])
+	}
+}
+
+// RateLimitKeyByCustomer counts the requests per customer, the subject of the bearer token stored by the Jwt
+// middleware or else the customer id path parameter, e.g. :id.
+func RateLimitKeyByCustomer(param string) RateLimitKeyFunc {
+	return func(ctx *beegoContext.Context) string {
+		if claims, ok := ctx.Input.GetData(JwtClaimsKey).(*jwt.RegisteredClaims); ok && claims.Subject != "" {
+			return "customer:" + claims.Subject
+		}
+		if id := ctx.Input.Param(param); id != "" {
+			return "customer:" + id
+		}
+		return ""
+	}
+}
+
+// ceilSeconds whole seconds of the headers, rounded up so a client waiting for them is not rejected again
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,265 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+)
+
+// failingRateLimitStore store failing every Take
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("connection refused")
+}
+
+// testRateLimitStore takes from the buckets of a store holding 3 tokens refilled over 3 seconds, advance moves
+// the clock of the store
+func testRateLimitStore(t *testing.T, store RateLimitStore, advance func(d time.Duration)) {
+	t.Helper()
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		key     string
+		want    RateLimitResult
+	}{
+		{
+			name: "first request of the burst",
+			key:  "a",
+			want: RateLimitResult{Allowed: true, Remaining: 2, ResetAfter: time.Second},
+		},
+		{
+			name: "second request of the burst",
+			key:  "a",
+			want: RateLimitResult{Allowed: true, Remaining: 1, ResetAfter: 2 * time.Second},
+		},
+		{
+			name: "last request of the burst",
+			key:  "a",
+			want: RateLimitResult{Allowed: true, Remaining: 0, ResetAfter: 3 * time.Second},
+		},
+		{
+			name: "request above the burst",
+			key:  "a",
+			want: RateLimitResult{Allowed: false, Remaining: 0, RetryAfter: time.Second, ResetAfter: 3 * time.Second},
+		},
+		{
+			name: "other key has its own bucket",
+			key:  "b",
+			want: RateLimitResult{Allowed: true, Remaining: 2, ResetAfter: time.Second},
+		},
+		{
+			name:    "request before a token is refilled",
+			advance: 500 * time.Millisecond,
+			key:     "a",
+			want:    RateLimitResult{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, ResetAfter: 2500 * time.Millisecond},
+		},
+		{
+			name:    "request once a token is refilled",
+			advance: 500 * time.Millisecond,
+			key:     "a",
+			want:    RateLimitResult{Allowed: true, Remaining: 0, ResetAfter: 3 * time.Second},
+		},
+		{
+			name:    "request after the bucket is full again",
+			advance: time.Minute,
+			key:     "a",
+			want:    RateLimitResult{Allowed: true, Remaining: 2, ResetAfter: time.Second},
+		},
+	}
+
+	for _, step := range steps {
+		advance(step.advance)
+		got, err := store.Take(context.Background(), step.key, 3, 3*time.Second)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: result = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemoryRateLimitStore(func() time.Time {
+		return now
+	})
+
+	testRateLimitStore(t, store, func(d time.Duration) {
+		now = now.Add(d)
+	})
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	store := NewRedisRateLimitStore(client, "test:")
+
+	testRateLimitStore(t, store, func(d time.Duration) {
+		now = now.Add(d)
+		server.SetTime(now)
+		server.FastForward(d)
+	})
+
+	// the bucket expires a second after it is full again
+	if ttl := server.TTL("test:a"); ttl != 2*time.Second {
+		t.Errorf("bucket ttl = %v, want %v", ttl, 2*time.Second)
+	}
+	server.FastForward(2 * time.Second)
+	if server.Exists("test:a") {
+		t.Errorf("full bucket is kept after its ttl")
+	}
+
+	server.Close()
+	if _, err := store.Take(context.Background(), "a", 3, 3*time.Second); err == nil {
+		t.Errorf("take on a closed redis did not fail")
+	}
+}
+
+func TestRateLimitWithConfig(t *testing.T) {
+	type request struct {
+		remoteAddr string
+		apiKey     string
+		subject    string
+		param      string
+		wantStatus int
+	}
+
+	tests := []struct {
+		name     string
+		keyFunc  RateLimitKeyFunc
+		requests []request
+	}{
+		{
+			name:    "per ip",
+			keyFunc: RateLimitKeyByIP,
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1000", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.1:2000", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.2:1000", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name:    "per api key",
+			keyFunc: RateLimitKeyByApiKey("X-API-KEY"),
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1000", apiKey: "a", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.2:1000", apiKey: "a", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.1:1000", apiKey: "b", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1000", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1000", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name:    "per customer",
+			keyFunc: RateLimitKeyByCustomer(":id"),
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1000", subject: "1", param: "1", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.2:1000", param: "1", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.1:1000", subject: "2", param: "1", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1000", param: "3", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1000", wantStatus: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			handler := RateLimitWithConfig(RateLimitConfig{
+				Store: newMemoryRateLimitStore(func() time.Time {
+					return now
+				}),
+				KeyFunc: tt.keyFunc,
+				Limit:   1,
+				Period:  time.Minute,
+			})(func(ctx *beegoContext.Context) {
+				ctx.Output.SetStatus(http.StatusOK)
+			})
+
+			for i, r := range tt.requests {
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(http.MethodGet, "/api/v1/customers/1/eligibility", nil)
+				request.RemoteAddr = r.remoteAddr
+				if r.apiKey != "" {
+					request.Header.Set("X-API-KEY", r.apiKey)
+				}
+				ctx := beegoContext.NewContext()
+				ctx.Reset(recorder, request)
+				if r.subject != "" {
+					ctx.Input.SetData(JwtClaimsKey, &jwt.RegisteredClaims{Subject: r.subject})
+				}
+				if r.param != "" {
+					ctx.Input.SetParam(":id", r.param)
+				}
+				handler(ctx)
+
+				if recorder.Code != r.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, recorder.Code, r.wantStatus)
+				}
+				if r.wantStatus != http.StatusTooManyRequests {
+					continue
+				}
+				for header, want := range map[string]string{
+					"Retry-After":         "60",
+					"RateLimit-Limit":     "1",
+					"RateLimit-Remaining": "0",
+					"RateLimit-Reset":     "60",
+					"RateLimit-Policy":    "1;w=60",
+				} {
+					if got := recorder.Header().Get(header); got != want {
+						t.Errorf("request %d: %s = %q, want %q", i, header, got, want)
+					}
+				}
+				if !strings.Contains(recorder.Body.String(), response.TooManyRequestsCodeError) {
+					t.Errorf("request %d: body = %s, want code %s", i, recorder.Body.String(), response.TooManyRequestsCodeError)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitWithConfigFailingStore(t *testing.T) {
+	called := 0
+	handler := RateLimitWithConfig(RateLimitConfig{
+		Store:  failingRateLimitStore{},
+		Limit:  1,
+		Period: time.Minute,
+	})(func(ctx *beegoContext.Context) {
+		called++
+		ctx.Output.SetStatus(http.StatusOK)
+	})
+
+	// requests are not limited while the store is failing
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		ctx := beegoContext.NewContext()
+		ctx.Reset(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/campaigns", nil))
+		handler(ctx)
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("request %d: status = %d, want %d", i, recorder.Code, http.StatusOK)
+		}
+		if got := recorder.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want no header", i, got)
+		}
+	}
+	if called != 3 {
+		t.Errorf("next called %d times, want 3", called)
+	}
+}
@@ -0,0 +1,165 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type (
+	// RateLimitStore keeps the token buckets of the RateLimit middleware.
+	RateLimitStore interface {
+		// Take takes a token from the bucket of the key holding at most limit tokens refilled over the period.
+		Take(ctx context.Context, key string, limit int, period time.Duration) (RateLimitResult, error)
+	}
+
+	// RateLimitResult state of a bucket after a Take.
+	RateLimitResult struct {
+		// Allowed a token was taken.
+		Allowed bool
+		// Remaining whole tokens left in the bucket.
+		Remaining int
+		// RetryAfter time until the next token, zero when Allowed.
+		RetryAfter time.Duration
+		// ResetAfter time until the bucket is full again.
+		ResetAfter time.Duration
+	}
+)
+
+// newRateLimitResult result of a bucket left with tokens, the bucket gains limit tokens per period
+func newRateLimitResult(allowed bool, tokens float64, limit int, period time.Duration) RateLimitResult {
+	perToken := float64(period) / float64(limit)
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration((float64(limit) - tokens) * perToken),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * perToken)
+	}
+	return result
+}
+
+// memoryRateLimitStoreSweepInterval full buckets are removed at most once per interval
+const memoryRateLimitStoreSweepInterval = time.Minute
+
+type (
+	memoryRateLimitStore struct {
+		// now clock of the buckets
+		now       func() time.Time
+		mu        sync.Mutex
+		buckets   map[string]*memoryRateLimitBucket
+		lastSweep time.Time
+	}
+
+	memoryRateLimitBucket struct {
+		tokens    float64
+		updatedAt time.Time
+		// fullAt the bucket is refilled, a full bucket is the same as no bucket
+		fullAt time.Time
+	}
+)
+
+// NewMemoryRateLimitStore returns a store keeping the buckets in the memory of the instance,
+// every instance of a deployment limits the clients on its own.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return newMemoryRateLimitStore(time.Now)
+}
+
+func newMemoryRateLimitStore(now func() time.Time) *memoryRateLimitStore {
+	return &memoryRateLimitStore{
+		now:       now,
+		buckets:   make(map[string]*memoryRateLimitBucket),
+		lastSweep: now(),
+	}
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration) (RateLimitResult, error) {
+	now := s.now()
+	perToken := float64(period) / float64(limit)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= memoryRateLimitStoreSweepInterval {
+		for k, bucket := range s.buckets {
+			if !now.Before(bucket.fullAt) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryRateLimitBucket{tokens: float64(limit), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(limit), bucket.tokens+float64(now.Sub(bucket.updatedAt))/perToken)
+	bucket.updatedAt = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	bucket.fullAt = now.Add(time.Duration((float64(limit) - bucket.tokens) * perToken))
+	return newRateLimitResult(allowed, bucket.tokens, limit, period), nil
+}
+
+// redisRateLimitScript refills and takes from the bucket hash of KEYS[1] atomically with the clock of the redis
+// server, ARGV[1] is the limit and ARGV[2] the period in milliseconds. The bucket expires once it is full again.
+var redisRateLimitScript = redis.NewScript(`
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1])
+local updatedAt = tonumber(bucket[2])
+if tokens == nil or updatedAt == nil then
+	tokens = limit
+	updatedAt = now
+end
+tokens = math.min(limit, tokens + math.max(0, now - updatedAt) * limit / period)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) * period / limit) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+type redisRateLimitStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisRateLimitStore returns a store keeping the buckets in redis below the key prefix,
+// the instances of a deployment sharing the redis limit the clients together.
+func NewRedisRateLimitStore(client redis.UniversalClient, prefix string) RateLimitStore {
+	return &redisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *redisRateLimitStore) Take(ctx context.Context, key string, limit int, period time.Duration) (RateLimitResult, error) {
+	values, err := redisRateLimitScript.Run(ctx, s.client, []string{s.prefix + key}, limit, period.Milliseconds()).Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	allowed, _ := values[0].(int64)
+	value, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	return newRateLimitResult(allowed == 1, tokens, limit, period), nil
+}
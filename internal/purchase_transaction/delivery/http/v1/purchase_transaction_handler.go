@@ -0,0 +1,466 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type PurchaseTransactionHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	PurchaseTransactionUsecase domain.PurchaseTransactionUseCase
+}
+
+func NewPurchaseTransactionHandler(purchaseTransactionUsecase domain.PurchaseTransactionUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &PurchaseTransactionHandler{
+		ZapLogger:                  zapLogger,
+		PurchaseTransactionUsecase: purchaseTransactionUsecase,
+	}
+	beego.Router("/api/v1/customers/:id/purchase-transactions", pHandler, "get:GetPurchaseTransactions;post:CreatePurchaseTransaction")
+	beego.Router("/api/v1/customers/:id/purchase-transactions/batch", pHandler, "post:CreatePurchaseTransactions")
+	beego.Router("/api/v1/customers/:id/purchase-transactions/:transactionId/refunds", pHandler, "post:RefundPurchaseTransaction")
+	beego.Router("/api/v1/customers/:id/purchase-transactions/:transactionId/void", pHandler, "post:VoidPurchaseTransaction")
+	beego.Router("/api/v1/admin/purchase-transactions/imports", pHandler, "post:ImportPurchaseTransactions")
+	beego.Router("/api/v1/admin/purchase-transactions/imports/:id", pHandler, "get:GetImport")
+	beego.Router("/api/v1/admin/purchase-transactions/imports/:id/result", pHandler, "get:GetImportResult")
+	beego.InsertFilterChain("/api/v1/customers/:id/purchase-transactions", middlewares.RequirePermission(domain.PermissionPurchaseTransactionsRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/customers/:id/purchase-transactions", middlewares.RequirePermission(domain.PermissionPurchaseTransactionsWrite, http.MethodPost))
+	beego.InsertFilterChain("/api/v1/customers/:id/purchase-transactions/batch", middlewares.RequirePermission(domain.PermissionPurchaseTransactionsWrite))
+	beego.InsertFilterChain("/api/v1/customers/:id/purchase-transactions/:transactionId/refunds", middlewares.RequirePermission(domain.PermissionPurchaseTransactionsWrite))
+	beego.InsertFilterChain("/api/v1/customers/:id/purchase-transactions/:transactionId/void", middlewares.RequirePermission(domain.PermissionPurchaseTransactionsWrite))
+	beego.InsertFilterChain("/api/v1/admin/purchase-transactions/imports", middlewares.RequirePermission(domain.PermissionImportsWrite))
+	beego.InsertFilterChain("/api/v1/admin/purchase-transactions/imports/:id", middlewares.RequirePermission(domain.PermissionImportsRead))
+	beego.InsertFilterChain("/api/v1/admin/purchase-transactions/imports/:id/result", middlewares.RequirePermission(domain.PermissionImportsRead))
+}
+
+func (h *PurchaseTransactionHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetPurchaseTransactions
+// @Title GetPurchaseTransactions
+// @Tags PurchaseTransaction
+// @Summary GetPurchaseTransactions
+// @Description purchase transactions of the customer, latest first
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    start_date query string false "transactions from this date, yyyy-mm-dd"
+// @Param    end_date query string false "transactions until this date inclusive, yyyy-mm-dd"
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/customers/{id}/purchase-transactions [get]
+func (h *PurchaseTransactionHandler) GetPurchaseTransactions() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var filter domain.PurchaseTransactionFilter
+	if startDate := h.GetString("start_date"); startDate != "" {
+		if filter.StartDate, err = time.ParseInLocation(helper.DateFormatDefault, startDate, time.Local); err != nil {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+			return
+		}
+	}
+	if endDate := h.GetString("end_date"); endDate != "" {
+		if filter.EndDate, err = time.ParseInLocation(helper.DateFormatDefault, endDate, time.Local); err != nil {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+			return
+		}
+		// end date is inclusive
+		filter.EndDate = filter.EndDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.StartDate.After(filter.EndDate) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), errors.New("start_date is after end_date"))
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.GetPurchaseTransactions(h.Ctx, pathParam, page, limit, filter)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreatePurchaseTransaction
+// @Title CreatePurchaseTransaction
+// @Tags PurchaseTransaction
+// @Summary CreatePurchaseTransaction
+// @Description store a purchase transaction, resending the same external reference returns the stored transaction
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    body body domain.PurchaseTransactionRequest true "request payload"
+// @Router /v1/customers/{id}/purchase-transactions [post]
+func (h *PurchaseTransactionHandler) CreatePurchaseTransaction() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.PurchaseTransactionRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.CreatePurchaseTransaction(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreatePurchaseTransactions
+// @Title CreatePurchaseTransactions
+// @Tags PurchaseTransaction
+// @Summary CreatePurchaseTransactions
+// @Description store up to 100 purchase transactions at once, nothing is stored when one of them is rejected
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionBatchResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    body body domain.PurchaseTransactionBatchRequest true "request payload"
+// @Router /v1/customers/{id}/purchase-transactions/batch [post]
+func (h *PurchaseTransactionHandler) CreatePurchaseTransactions() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.PurchaseTransactionBatchRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.CreatePurchaseTransactions(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// RefundPurchaseTransaction
+// @Title RefundPurchaseTransaction
+// @Tags PurchaseTransaction
+// @Summary RefundPurchaseTransaction
+// @Description record a refund of the purchase transaction, refunds are deducted from the spend counted by the eligibility rules
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    transactionId path int true "id purchase transaction"
+// @Param    body body domain.PurchaseTransactionRefundRequest true "request payload"
+// @Router /v1/customers/{id}/purchase-transactions/{transactionId}/refunds [post]
+func (h *PurchaseTransactionHandler) RefundPurchaseTransaction() {
+	customerId, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || customerId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	transactionId, err := strconv.Atoi(h.Ctx.Input.Param(":transactionId"))
+	if err != nil || transactionId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.PurchaseTransactionRefundRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.RefundPurchaseTransaction(h.Ctx, customerId, transactionId, request)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// VoidPurchaseTransaction
+// @Title VoidPurchaseTransaction
+// @Tags PurchaseTransaction
+// @Summary VoidPurchaseTransaction
+// @Description void a purchase transaction without refunds, a voided transaction is not counted by the eligibility rules
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "partner api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id customer"
+// @Param    transactionId path int true "id purchase transaction"
+// @Router /v1/customers/{id}/purchase-transactions/{transactionId}/void [post]
+func (h *PurchaseTransactionHandler) VoidPurchaseTransaction() {
+	customerId, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || customerId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	transactionId, err := strconv.Atoi(h.Ctx.Input.Param(":transactionId"))
+	if err != nil || transactionId < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.VoidPurchaseTransaction(h.Ctx, customerId, transactionId)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// ImportPurchaseTransactions
+// @Title ImportPurchaseTransactions
+// @Tags Admin
+// @Summary ImportPurchaseTransactions
+// @Description import a csv file with the columns customer_id, total_spent, total_saving, transaction_at and optional external_reference.
+// @Description A file already imported is not imported again, an interrupted import resumes after the last stored row. Requires the admin api key
+// @Accept multipart/form-data
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionImportResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=domain.PurchaseTransactionImportResponse}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionImportResponse}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=domain.PurchaseTransactionImportResponse}
+// @Param        file   formData  file    true  "csv file"
+// @Router /v1/admin/purchase-transactions/imports [post]
+func (h *PurchaseTransactionHandler) ImportPurchaseTransactions() {
+	_, fileHeader, err := h.GetFile("file")
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.UploadPurchaseTransactions(h.Ctx, fileHeader)
+	if err != nil {
+		if errors.Is(err, response.ErrImportInProgress) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusConflict, response.ImportInProgress, response.ErrorCodeText(response.ImportInProgress, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, response.ErrImportInvalidFile) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, response.ImportInvalidFile, response.ErrorCodeText(response.ImportInvalidFile, h.Locale.Lang), result, err)
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.ResponseErrorWithData(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), result, err)
+			return
+		}
+		h.ResponseErrorWithData(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), result, err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetImport
+// @Title GetImport
+// @Tags Admin
+// @Summary GetImport
+// @Description progress of a purchase transaction import, requires the admin api key
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.PurchaseTransactionImportResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id import"
+// @Router /v1/admin/purchase-transactions/imports/{id} [get]
+func (h *PurchaseTransactionHandler) GetImport() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.GetImport(h.Ctx, pathParam)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// GetImportResult
+// @Title GetImportResult
+// @Tags Admin
+// @Summary GetImportResult
+// @Description csv file with every row of the import that was not stored and the reason, requires the admin api key
+// @Produce text/csv
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {file} binary
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id import"
+// @Router /v1/admin/purchase-transactions/imports/{id}/result [get]
+func (h *PurchaseTransactionHandler) GetImportResult() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.PurchaseTransactionUsecase.GetImportResult(h.Ctx, pathParam)
+	if err != nil {
+		h.responsePurchaseTransactionError(err)
+		return
+	}
+
+	h.Ctx.Output.Header("Content-Type", "text/csv")
+	h.Ctx.Output.Header("Content-Disposition", `attachment; filename="`+result.FileName+`"`)
+	h.Ctx.Output.SetStatus(http.StatusOK)
+	h.Ctx.Output.Body(result.Data)
+}
+
+func (h *PurchaseTransactionHandler) responsePurchaseTransactionError(err error) {
+	if errors.Is(err, response.ErrPurchaseReferenceConflict) {
+		h.ResponseError(h.Ctx, http.StatusConflict, response.PurchaseReferenceConflict, response.ErrorCodeText(response.PurchaseReferenceConflict, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrPurchaseTransactionStatusInvalid) {
+		h.ResponseError(h.Ctx, http.StatusConflict, response.PurchaseTransactionStatusInvalid, response.ErrorCodeText(response.PurchaseTransactionStatusInvalid, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrRefundAmountExceeded) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.RefundAmountExceeded, response.ErrorCodeText(response.RefundAmountExceeded, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrTransactionAtInFuture) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.TransactionAtInFuture, response.ErrorCodeText(response.TransactionAtInFuture, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, response.ErrCurrencyAmountInvalid) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.CurrencyAmountInvalid, response.ErrorCodeText(response.CurrencyAmountInvalid, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
 	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
 	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type mysqlPurchaseTransactionRepository struct {
@@ -51,7 +53,7 @@ func (c mysqlPurchaseTransactionRepository) CountFilter(ctx context.Context, ass
 
 func (c mysqlPurchaseTransactionRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
 	p := paginator.NewPaginator(c.db, offset, limit, model)
-	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args).Select(strings.Join(fields, ",")).Error; err != nil {
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
 		return nil, err
 	}
 	return model, nil
@@ -77,6 +79,32 @@ func (c mysqlPurchaseTransactionRepository) SingleWithFilter(ctx context.Context
 	return nil
 }
 
+func (c mysqlPurchaseTransactionRepository) SingleWithFilterWithTx(ctx context.Context, tx *gorm.DB, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := tx.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c mysqlPurchaseTransactionRepository) Update(ctx context.Context, data domain.PurchaseTransaction) error {
 
 	err := c.db.WithContext(ctx).Updates(&data).Error
@@ -124,6 +152,20 @@ func (c mysqlPurchaseTransactionRepository) UpdateSelectedFieldWithTx(ctx contex
 	return tx.WithContext(ctx).Table(domain.PurchaseTransaction{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
 }
 
+// StoreIgnoreDuplicateWithTx store the transaction unless its external reference is already stored,
+// false is returned with a zero id when the insert was skipped
+func (c mysqlPurchaseTransactionRepository) StoreIgnoreDuplicateWithTx(ctx context.Context, tx *gorm.DB, data domain.PurchaseTransaction) (int, bool, error) {
+
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&data)
+	if result.Error != nil {
+		return 0, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, false, nil
+	}
+	return data.ID, true, nil
+}
+
 func (c mysqlPurchaseTransactionRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.PurchaseTransaction) (int, error) {
 
 	err := tx.WithContext(ctx).Create(&data).Error
@@ -132,3 +174,32 @@ func (c mysqlPurchaseTransactionRepository) StoreWithTx(ctx context.Context, tx
 	}
 	return data.ID, nil
 }
+
+// LockWithTx locks the purchase transaction row so concurrent refunds of the same transaction are serialized.
+func (c mysqlPurchaseTransactionRepository) LockWithTx(ctx context.Context, tx *gorm.DB, id int) (domain.PurchaseTransaction, error) {
+	var data domain.PurchaseTransaction
+
+	err := database.LockForUpdate(tx.WithContext(ctx), data.TableName()).Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// SumNetSpentByCurrency total spent net of refunds of the filtered transactions, one row per currency
+func (c mysqlPurchaseTransactionRepository) SumNetSpentByCurrency(ctx context.Context, filter []string, args ...interface{}) ([]domain.PurchaseTransactionCurrencySum, error) {
+	var result []domain.PurchaseTransactionCurrencySum
+
+	db := c.db.WithContext(ctx).Model(&domain.PurchaseTransaction{}).
+		Select("currency, COALESCE(SUM(total_spent - refunded_amount), 0) AS net_spent")
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.Group("currency").Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,465 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"gorm.io/gorm"
+)
+
+// columns of the import file, external_reference and currency are optional
+const (
+	importColumnCustomerId        = "customer_id"
+	importColumnTotalSpent        = "total_spent"
+	importColumnTotalSaving       = "total_saving"
+	importColumnTransactionAt     = "transaction_at"
+	importColumnExternalReference = "external_reference"
+	importColumnCurrency          = "currency"
+)
+
+var importRequiredColumns = []string{importColumnCustomerId, importColumnTotalSpent, importColumnTotalSaving, importColumnTransactionAt}
+
+// maxImportAmount largest amount of a decimal(10,2) column
+const maxImportAmount = money.Amount(9999999999)
+
+// importRow data row of the import file, err is the reason the row is not stored
+type importRow struct {
+	row         int
+	reference   string
+	transaction domain.PurchaseTransaction
+	err         error
+}
+
+// importBatch counters of the rows committed together with the import progress
+type importBatch struct {
+	imported   int
+	duplicates int
+	errors     []domain.PurchaseTransactionImportError
+}
+
+func (r purchaseTransactionUseCase) UploadPurchaseTransactions(beegoCtx *beegoContext.Context, file *multipart.FileHeader) (*domain.PurchaseTransactionImportResponse, error) {
+	src, err := file.Open()
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	defer src.Close()
+
+	result, err := r.ImportPurchaseTransactions(beegoCtx.Request.Context(), file.Filename, src)
+	if err != nil && !errors.Is(err, response.ErrImportInProgress) && !errors.Is(err, response.ErrImportInvalidFile) {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+	}
+	return result, err
+}
+
+// ImportPurchaseTransactions stream the csv file into purchase transactions. The file is identified by its checksum,
+// a completed file is not imported again and an interrupted import resumes after the last committed row.
+// Rows that cannot be stored are kept as import errors, the import response is returned with the error when
+// the import stops before the end of the file
+func (r purchaseTransactionUseCase) ImportPurchaseTransactions(ctx context.Context, fileName string, file io.ReadSeeker) (*domain.PurchaseTransactionImportResponse, error) {
+	c, cancel := context.WithTimeout(ctx, r.importConfig.Timeout)
+	defer cancel()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	imported, err := r.findOrCreateImport(c, hex.EncodeToString(hash.Sum(nil)), filepath.Base(fileName))
+	if err != nil {
+		return nil, err
+	}
+	if imported.Status == domain.PurchaseTransactionImportStatusCompleted {
+		result := domain.NewPurchaseTransactionImportResponse(*imported)
+		return &result, nil
+	}
+
+	claimed, err := r.mysqlPurchaseTransactionImportRepository.Claim(c, imported.ID, time.Now().Add(-r.importConfig.StaleAfter))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		result := domain.NewPurchaseTransactionImportResponse(*imported)
+		return &result, response.ErrImportInProgress
+	}
+	imported.Status = domain.PurchaseTransactionImportStatusProcessing
+
+	if err := r.importRows(c, imported, file); err != nil {
+		message := err.Error()
+		if len(message) > 255 {
+			message = message[:255]
+		}
+		imported.Status = domain.PurchaseTransactionImportStatusFailed
+		imported.Message = message
+		if updateErr := r.mysqlPurchaseTransactionImportRepository.UpdateSelectedField(context.Background(),
+			[]string{"status", "message", "updated_at"},
+			map[string]interface{}{
+				"status":     imported.Status,
+				"message":    imported.Message,
+				"updated_at": time.Now(),
+			},
+			imported.ID); updateErr != nil {
+			r.zapLogger.Errorf("purchase transaction import %d: %v", imported.ID, updateErr)
+		}
+		result := domain.NewPurchaseTransactionImportResponse(*imported)
+		return &result, err
+	}
+
+	imported.Status = domain.PurchaseTransactionImportStatusCompleted
+	imported.Message = ""
+	imported.UpdatedAt = time.Now()
+	if err := r.mysqlPurchaseTransactionImportRepository.UpdateSelectedField(c,
+		[]string{"status", "message", "updated_at"},
+		map[string]interface{}{
+			"status":     imported.Status,
+			"message":    imported.Message,
+			"updated_at": imported.UpdatedAt,
+		},
+		imported.ID); err != nil {
+		return nil, err
+	}
+
+	result := domain.NewPurchaseTransactionImportResponse(*imported)
+	return &result, nil
+}
+
+func (r purchaseTransactionUseCase) findOrCreateImport(ctx context.Context, checksum, fileName string) (*domain.PurchaseTransactionImport, error) {
+	var imported domain.PurchaseTransactionImport
+	err := r.mysqlPurchaseTransactionImportRepository.SingleWithFilter(ctx, []string{"*"}, []string{}, []string{"checksum = ?"}, &imported, checksum)
+	if err == nil {
+		return &imported, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	imported, err = r.mysqlPurchaseTransactionImportRepository.Store(ctx, domain.PurchaseTransactionImport{
+		Checksum: checksum,
+		FileName: fileName,
+		Status:   domain.PurchaseTransactionImportStatusPending,
+	})
+	if err != nil {
+		// the same file uploaded at the same time
+		if findErr := r.mysqlPurchaseTransactionImportRepository.SingleWithFilter(ctx, []string{"*"}, []string{}, []string{"checksum = ?"}, &imported, checksum); findErr == nil {
+			return &imported, nil
+		}
+		return nil, err
+	}
+	return &imported, nil
+}
+
+// importRows read the file row by row and commit every batch together with the import progress
+func (r purchaseTransactionUseCase) importRows(ctx context.Context, imported *domain.PurchaseTransactionImport, file io.Reader) error {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: %v", response.ErrImportInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("%w: missing column %s", response.ErrImportInvalidFile, name)
+		}
+	}
+
+	now := time.Now()
+	batch := make([]importRow, 0, r.importConfig.BatchSize)
+	row := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return err
+		}
+		// rows committed by a previous run of the same file
+		if row <= imported.LastRow {
+			continue
+		}
+
+		if err != nil {
+			batch = append(batch, importRow{row: row, err: err})
+		} else {
+			batch = append(batch, parseImportRow(row, record, columns, now))
+		}
+
+		if len(batch) >= r.importConfig.BatchSize {
+			if err := r.storeImportBatch(ctx, imported, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	return r.storeImportBatch(ctx, imported, batch)
+}
+
+func parseImportRow(row int, record []string, columns map[string]int, now time.Time) importRow {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	result := importRow{row: row, reference: value(importColumnExternalReference)}
+	if len(result.reference) > 100 {
+		result.err = errors.New("external_reference must be at most 100 characters")
+		return result
+	}
+
+	customerId, err := strconv.Atoi(value(importColumnCustomerId))
+	if err != nil || customerId < 1 {
+		result.err = errors.New("customer_id must be a positive number")
+		return result
+	}
+	currency := strings.ToUpper(value(importColumnCurrency))
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if !isCurrencyCode(currency) {
+		result.err = errors.New("currency must be an ISO 4217 currency code")
+		return result
+	}
+	if err := money.CheckCurrency(currency); err != nil {
+		result.err = err
+		return result
+	}
+	totalSpent, err := money.ParseAmountIn(value(importColumnTotalSpent), currency)
+	if err != nil || totalSpent <= 0 || totalSpent > maxImportAmount {
+		result.err = fmt.Errorf("total_spent must be a number with at most %d decimals greater than 0 and at most %s", money.MinorUnits(currency), maxImportAmount)
+		return result
+	}
+	totalSaving, err := money.ParseAmountIn(value(importColumnTotalSaving), currency)
+	if err != nil || totalSaving < 0 || totalSaving > maxImportAmount {
+		result.err = fmt.Errorf("total_saving must be a number with at most %d decimals between 0 and %s", money.MinorUnits(currency), maxImportAmount)
+		return result
+	}
+	transactionAt, err := time.ParseInLocation(helper.DateTimeFormatDefault, value(importColumnTransactionAt), time.Local)
+	if err != nil {
+		result.err = fmt.Errorf("transaction_at must be formatted as %s", helper.DateTimeFormatDefault)
+		return result
+	}
+	if transactionAt.After(now) {
+		result.err = response.ErrTransactionAtInFuture
+		return result
+	}
+
+	result.transaction = domain.PurchaseTransaction{
+		CustomerID:    customerId,
+		TotalSpent:    totalSpent,
+		TotalSaving:   totalSaving,
+		Currency:      currency,
+		TransactionAt: transactionAt,
+		Status:        domain.PurchaseTransactionStatusCompleted,
+	}
+	if result.reference != "" {
+		reference := result.reference
+		result.transaction.ExternalReference = &reference
+	}
+	return result
+}
+
+// isCurrencyCode three uppercase letters, the rates table tells whether the currency is supported
+func isCurrencyCode(value string) bool {
+	if len(value) != 3 {
+		return false
+	}
+	for _, c := range value {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// storeImportBatch store the valid rows, the failed rows and the import progress in a single database transaction
+func (r purchaseTransactionUseCase) storeImportBatch(ctx context.Context, imported *domain.PurchaseTransactionImport, rows []importRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	customers, err := r.existingCustomers(ctx, rows)
+	if err != nil {
+		return err
+	}
+
+	var batch importBatch
+	err = r.mysqlPurchaseTransactionRepository.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		batch = importBatch{}
+		for _, row := range rows {
+			if row.err == nil && !customers[row.transaction.CustomerID] {
+				row.err = errors.New("customer_id does not exist")
+			}
+			if row.err == nil {
+				duplicate, err := r.storeImportRowWithTx(ctx, tx, row.transaction)
+				switch {
+				case errors.Is(err, response.ErrPurchaseReferenceConflict):
+					row.err = err
+				case err != nil:
+					return err
+				case duplicate:
+					batch.duplicates++
+				default:
+					batch.imported++
+				}
+			}
+			if row.err != nil {
+				batch.errors = append(batch.errors, domain.PurchaseTransactionImportError{
+					PurchaseTransactionImportID: imported.ID,
+					Row:                         row.row,
+					ExternalReference:           row.reference,
+					Message:                     row.err.Error(),
+				})
+			}
+		}
+
+		if err := r.mysqlPurchaseTransactionImportRepository.StoreErrorsWithTx(ctx, tx, batch.errors); err != nil {
+			return err
+		}
+		return r.mysqlPurchaseTransactionImportRepository.UpdateSelectedFieldWithTx(ctx, tx,
+			[]string{"last_row", "imported_rows", "duplicate_rows", "failed_rows", "updated_at"},
+			map[string]interface{}{
+				"last_row":       rows[len(rows)-1].row,
+				"imported_rows":  imported.ImportedRows + batch.imported,
+				"duplicate_rows": imported.DuplicateRows + batch.duplicates,
+				"failed_rows":    imported.FailedRows + len(batch.errors),
+				"updated_at":     time.Now(),
+			},
+			imported.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	imported.LastRow = rows[len(rows)-1].row
+	imported.ImportedRows += batch.imported
+	imported.DuplicateRows += batch.duplicates
+	imported.FailedRows += len(batch.errors)
+	return nil
+}
+
+// storeImportRowWithTx store the row, rows with an external reference already stored are skipped as duplicate
+func (r purchaseTransactionUseCase) storeImportRowWithTx(ctx context.Context, tx *gorm.DB, transaction domain.PurchaseTransaction) (bool, error) {
+	if transaction.ExternalReference == nil {
+		_, err := r.mysqlPurchaseTransactionRepository.StoreWithTx(ctx, tx, transaction)
+		return false, err
+	}
+	_, duplicate, err := r.storeWithTx(ctx, tx, transaction)
+	return duplicate, err
+}
+
+// existingCustomers customers of the rows that exist
+func (r purchaseTransactionUseCase) existingCustomers(ctx context.Context, rows []importRow) (map[int]bool, error) {
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if row.err == nil {
+			ids = append(ids, row.transaction.CustomerID)
+		}
+	}
+
+	result := make(map[int]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	customers, err := r.mysqlCustomerRepository.FetchWithFilter(ctx, len(ids), 0, "id ASC", []string{"id"}, []string{}, []string{"id IN ?"}, &[]domain.Customer{}, ids)
+	if err != nil {
+		return nil, err
+	}
+	if customers, ok := customers.(*[]domain.Customer); ok {
+		for _, customer := range *customers {
+			result[customer.ID] = true
+		}
+	}
+	return result, nil
+}
+
+func (r purchaseTransactionUseCase) GetImport(beegoCtx *beegoContext.Context, id int) (*domain.PurchaseTransactionImportResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var imported domain.PurchaseTransactionImport
+	if err := r.mysqlPurchaseTransactionImportRepository.SingleWithFilter(c, []string{"*"}, []string{}, []string{"id = ?"}, &imported, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewPurchaseTransactionImportResponse(imported)
+	return &result, nil
+}
+
+// GetImportResult csv file with the rows of the import that were not stored and the reason
+func (r purchaseTransactionUseCase) GetImportResult(beegoCtx *beegoContext.Context, id int) (*domain.PurchaseTransactionImportResult, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var imported domain.PurchaseTransactionImport
+	if err := r.mysqlPurchaseTransactionImportRepository.SingleWithFilter(c, []string{"*"}, []string{}, []string{"id = ?"}, &imported, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write([]string{"row", importColumnExternalReference, "error"}); err != nil {
+		return nil, err
+	}
+
+	afterId := 0
+	for {
+		importErrors, err := r.mysqlPurchaseTransactionImportRepository.FetchErrors(c, imported.ID, afterId, 1000)
+		if err != nil {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+			return nil, err
+		}
+		for _, importError := range importErrors {
+			if err := writer.Write([]string{strconv.Itoa(importError.Row), importError.ExternalReference, importError.Message}); err != nil {
+				return nil, err
+			}
+			afterId = importError.ID
+		}
+		if len(importErrors) < 1000 {
+			break
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return &domain.PurchaseTransactionImportResult{
+		FileName: fmt.Sprintf("import-%d-result.csv", imported.ID),
+		Data:     buffer.Bytes(),
+	}, nil
+}
@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	customerRepository "github.com/radyatamaa/technical-test-aichat/internal/customer/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	purchaseTransactionRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/usecase"
+	purchaseTransactionImportRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction_import/repository"
+	purchaseTransactionRefundRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction_refund/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"gorm.io/gorm"
+)
+
+var errInterrupted = errors.New("connection reset")
+
+// interruptedReader file whose import stops after limit bytes, the checksum is still computed over the whole file
+// because the reader only fails once it was rewound for the import
+type interruptedReader struct {
+	*bytes.Reader
+	limit   int64
+	rewound bool
+}
+
+func (r *interruptedReader) Seek(offset int64, whence int) (int64, error) {
+	r.rewound = true
+	return r.Reader.Seek(offset, whence)
+}
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	if !r.rewound {
+		return r.Reader.Read(p)
+	}
+	position := r.Reader.Size() - int64(r.Reader.Len())
+	if position >= r.limit {
+		return 0, errInterrupted
+	}
+	if int64(len(p)) > r.limit-position {
+		p = p[:r.limit-position]
+	}
+	return r.Reader.Read(p)
+}
+
+func newPurchaseTransactionUseCase(t *testing.T, db *gorm.DB) domain.PurchaseTransactionUseCase {
+	t.Helper()
+
+	zapLog := testutil.NewLogger(t)
+	return usecase.NewPurchaseTransactionUseCase(30*time.Second,
+		usecase.ImportConfig{
+			BatchSize:  2,
+			Timeout:    30 * time.Second,
+			StaleAfter: time.Minute,
+		},
+		customerRepository.NewMysqlCustomerRepository(db, zapLog),
+		purchaseTransactionRepository.NewPurchaseTransactionRepository(db, zapLog),
+		purchaseTransactionImportRepository.NewMysqlPurchaseTransactionImportRepository(db, zapLog),
+		purchaseTransactionRefundRepository.NewMysqlPurchaseTransactionRefundRepository(db, zapLog),
+		zapLog)
+}
+
+// importFile rows of the customer committed in batches of two: (1, 2) (3, 4) (5, 6) (7)
+func importFile(customerId int) []string {
+	transactionAt := time.Now().Add(-time.Hour).Format(helper.DateTimeFormatDefault)
+	return []string{
+		"customer_id,total_spent,total_saving,transaction_at,external_reference",
+		fmt.Sprintf("%d,10.00,1.00,%s,REF-1", customerId, transactionAt),
+		fmt.Sprintf("%d,20.00,0,%s,", customerId, transactionAt),
+		fmt.Sprintf("%d,abc,0,%s,REF-3", customerId, transactionAt),
+		fmt.Sprintf("999999,5.00,0,%s,REF-4", transactionAt),
+		fmt.Sprintf("%d,30.00,0,%s,", customerId, transactionAt),
+		fmt.Sprintf("%d,99.00,0,%s,REF-1", customerId, transactionAt),
+		fmt.Sprintf("%d,10.00,1.00,%s,REF-1", customerId, transactionAt),
+	}
+}
+
+func countTransactions(t *testing.T, db *gorm.DB, customerId int) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&domain.PurchaseTransaction{}).Where("customer_id = ?", customerId).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestImportPurchaseTransactionsResumesInterruptedImport(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	purchaseTransactionUcase := newPurchaseTransactionUseCase(t, db)
+	customerId := testutil.CreateCustomers(t, db, 1)[0]
+
+	lines := importFile(customerId)
+	data := []byte(strings.Join(lines, "\n") + "\n")
+
+	// the connection drops in the middle of row 5, rows 1 to 4 are committed
+	limit := int64(len(strings.Join(lines[:5], "\n")) + 1 + len(lines[5])/2)
+	result, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "transactions.csv",
+		&interruptedReader{Reader: bytes.NewReader(data), limit: limit})
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("interrupted import error = %v, want %v", err, errInterrupted)
+	}
+	if result.Status != domain.PurchaseTransactionImportStatusFailed || result.ProcessedRows != 4 ||
+		result.ImportedRows != 2 || result.FailedRows != 2 {
+		t.Fatalf("interrupted import = %+v, want failed after row 4 with 2 imported and 2 failed rows", result)
+	}
+	if count := countTransactions(t, db, customerId); count != 2 {
+		t.Fatalf("transactions after the interruption = %d, want 2", count)
+	}
+
+	// another upload of the file while the import is still running
+	if err := db.Model(&domain.PurchaseTransactionImport{}).Where("id = ?", result.ID).
+		Updates(map[string]interface{}{"status": domain.PurchaseTransactionImportStatusProcessing, "updated_at": time.Now()}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "transactions.csv", bytes.NewReader(data)); !errors.Is(err, response.ErrImportInProgress) {
+		t.Fatalf("import in progress error = %v, want %v", err, response.ErrImportInProgress)
+	}
+
+	// the import crashed without progress for longer than staleAfter and is taken over
+	if err := db.Model(&domain.PurchaseTransactionImport{}).Where("id = ?", result.ID).
+		Update("updated_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "transactions.csv", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.ID != result.ID || resumed.Status != domain.PurchaseTransactionImportStatusCompleted || resumed.ProcessedRows != 7 ||
+		resumed.ImportedRows != 3 || resumed.DuplicateRows != 1 || resumed.FailedRows != 3 {
+		t.Fatalf("resumed import = %+v, want completed with 3 imported, 1 duplicate and 3 failed rows", resumed)
+	}
+	if count := countTransactions(t, db, customerId); count != 3 {
+		t.Errorf("transactions after the resume = %d, want 3", count)
+	}
+
+	ctx := testutil.NewContext(httptest.NewRequest("GET", resumed.ResultURL, nil))
+	importResult, err := purchaseTransactionUcase.GetImportResult(ctx, resumed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(importResult.Data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"row", "external_reference", "error"},
+		{"3", "REF-3", "total_spent must be"},
+		{"4", "REF-4", "customer_id does not exist"},
+		{"6", "REF-1", response.ErrPurchaseReferenceConflict.Error()},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("result rows = %v, want %v", records, want)
+	}
+	for i := range want {
+		if records[i][0] != want[i][0] || records[i][1] != want[i][1] || !strings.HasPrefix(records[i][2], want[i][2]) {
+			t.Errorf("result row %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+}
+
+func TestImportPurchaseTransactionsCompletedFile(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	purchaseTransactionUcase := newPurchaseTransactionUseCase(t, db)
+	customerId := testutil.CreateCustomers(t, db, 1)[0]
+
+	data := []byte(strings.Join(importFile(customerId), "\n") + "\n")
+	imported, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "transactions.csv", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Status != domain.PurchaseTransactionImportStatusCompleted || imported.ImportedRows != 3 {
+		t.Fatalf("import = %+v, want completed with 3 imported rows", imported)
+	}
+
+	// the same file uploaded again under another name
+	again, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "copy.csv", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != imported.ID || again.Status != domain.PurchaseTransactionImportStatusCompleted ||
+		again.ImportedRows != imported.ImportedRows || again.FailedRows != imported.FailedRows {
+		t.Errorf("uploaded again = %+v, want the completed import %+v", again, imported)
+	}
+	if count := countTransactions(t, db, customerId); count != 3 {
+		t.Errorf("transactions = %d, want 3", count)
+	}
+
+	var imports int64
+	if err := db.Model(&domain.PurchaseTransactionImport{}).Count(&imports).Error; err != nil {
+		t.Fatal(err)
+	}
+	if imports != 1 {
+		t.Errorf("imports = %d, want 1", imports)
+	}
+}
+
+func TestImportPurchaseTransactionsCurrencyDecimals(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	purchaseTransactionUcase := newPurchaseTransactionUseCase(t, db)
+	customerId := testutil.CreateCustomers(t, db, 1)[0]
+
+	transactionAt := time.Now().Add(-time.Hour).Format(helper.DateTimeFormatDefault)
+	data := []byte(strings.Join([]string{
+		"customer_id,total_spent,total_saving,currency,transaction_at,external_reference",
+		fmt.Sprintf("%d,10.50,0,USD,%s,REF-USD", customerId, transactionAt),
+		fmt.Sprintf("%d,1500,0,JPY,%s,REF-JPY", customerId, transactionAt),
+		fmt.Sprintf("%d,1500.50,0,JPY,%s,REF-JPY-DECIMALS", customerId, transactionAt),
+		fmt.Sprintf("%d,154321,0.5,IDR,%s,REF-IDR-SAVING", customerId, transactionAt),
+		fmt.Sprintf("%d,1.500,0,KWD,%s,REF-KWD", customerId, transactionAt),
+	}, "\n") + "\n")
+	imported, err := purchaseTransactionUcase.ImportPurchaseTransactions(context.Background(), "transactions.csv", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ImportedRows != 2 || imported.FailedRows != 3 {
+		t.Errorf("import = %+v, want 2 imported and 3 failed rows", imported)
+	}
+
+	var failed []domain.PurchaseTransactionImportError
+	if err := db.Where("purchase_transaction_import_id = ?", imported.ID).Order("row").Find(&failed).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"REF-JPY-DECIMALS": "total_spent must be a number with at most 0 decimals",
+		"REF-IDR-SAVING":   "total_saving must be a number with at most 0 decimals",
+		"REF-KWD":          "unsupported currency",
+	}
+	if len(failed) != len(want) {
+		t.Fatalf("failed rows = %+v, want %d", failed, len(want))
+	}
+	for _, row := range failed {
+		if !strings.Contains(row.Message, want[row.ExternalReference]) {
+			t.Errorf("row %s message = %q, want %q", row.ExternalReference, row.Message, want[row.ExternalReference])
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type purchaseTransactionUseCase struct {
+	zapLogger                                zaplogger.Logger
+	contextTimeout                           time.Duration
+	importConfig                             ImportConfig
+	mysqlCustomerRepository                  domain.MysqlCustomerRepository
+	mysqlPurchaseTransactionRepository       domain.MysqlPurchaseTransactionRepository
+	mysqlPurchaseTransactionImportRepository domain.MysqlPurchaseTransactionImportRepository
+	mysqlPurchaseTransactionRefundRepository domain.MysqlPurchaseTransactionRefundRepository
+}
+
+// ImportConfig csv import of purchase transactions, rows are committed every BatchSize rows and
+// an import without progress for StaleAfter is considered crashed and may be resumed
+type ImportConfig struct {
+	BatchSize  int
+	Timeout    time.Duration
+	StaleAfter time.Duration
+}
+
+func NewPurchaseTransactionUseCase(timeout time.Duration,
+	importConfig ImportConfig,
+	mysqlCustomerRepository domain.MysqlCustomerRepository,
+	mysqlPurchaseTransactionRepository domain.MysqlPurchaseTransactionRepository,
+	mysqlPurchaseTransactionImportRepository domain.MysqlPurchaseTransactionImportRepository,
+	mysqlPurchaseTransactionRefundRepository domain.MysqlPurchaseTransactionRefundRepository,
+	zapLogger zaplogger.Logger) domain.PurchaseTransactionUseCase {
+	return &purchaseTransactionUseCase{
+		mysqlCustomerRepository:                  mysqlCustomerRepository,
+		mysqlPurchaseTransactionRepository:       mysqlPurchaseTransactionRepository,
+		mysqlPurchaseTransactionImportRepository: mysqlPurchaseTransactionImportRepository,
+		mysqlPurchaseTransactionRefundRepository: mysqlPurchaseTransactionRefundRepository,
+		importConfig:                             importConfig,
+		contextTimeout:                           timeout,
+		zapLogger:                                zapLogger,
+	}
+}
+
+// QUERY PURCHASE TRANSACTION
+func (r purchaseTransactionUseCase) fetchPurchaseTransactionWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.PurchaseTransaction, error) {
+
+	if transaction, err := r.mysqlPurchaseTransactionRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"transaction_at DESC, id DESC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.PurchaseTransaction{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := transaction.(*[]domain.PurchaseTransaction); !ok {
+			return []domain.PurchaseTransaction{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+func (r purchaseTransactionUseCase) checkCustomer(ctx context.Context, customerId int) error {
+	var customer domain.Customer
+	return r.mysqlCustomerRepository.SingleWithFilter(ctx, []string{"id"}, []string{}, []string{"id = ?"}, &customer, customerId)
+}
+
+func (r purchaseTransactionUseCase) requestToEntity(customerId int, request domain.PurchaseTransactionRequest, now time.Time) (domain.PurchaseTransaction, error) {
+	transactionAt, err := time.ParseInLocation(helper.DateTimeFormatDefault, request.TransactionAt, time.Local)
+	if err != nil {
+		return domain.PurchaseTransaction{}, err
+	}
+	if transactionAt.After(now) {
+		return domain.PurchaseTransaction{}, response.ErrTransactionAtInFuture
+	}
+
+	currency := request.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	for _, amount := range []money.Amount{request.TotalSpent, request.TotalSaving} {
+		if err := amount.CheckIn(currency); err != nil {
+			return domain.PurchaseTransaction{}, fmt.Errorf("%w: %v", response.ErrCurrencyAmountInvalid, err)
+		}
+	}
+
+	reference := request.ExternalReference
+	return domain.PurchaseTransaction{
+		CustomerID:        customerId,
+		TotalSpent:        request.TotalSpent,
+		TotalSaving:       request.TotalSaving,
+		Currency:          currency,
+		TransactionAt:     transactionAt,
+		ExternalReference: &reference,
+		Status:            domain.PurchaseTransactionStatusCompleted,
+	}, nil
+}
+
+// storeWithTx store the transaction once per external reference, a resent transaction returns the stored one
+// with duplicate true and response.ErrPurchaseReferenceConflict when the reference belongs to a different transaction
+func (r purchaseTransactionUseCase) storeWithTx(ctx context.Context, tx *gorm.DB, entity domain.PurchaseTransaction) (domain.PurchaseTransaction, bool, error) {
+	id, created, err := r.mysqlPurchaseTransactionRepository.StoreIgnoreDuplicateWithTx(ctx, tx, entity)
+	if err != nil {
+		return entity, false, err
+	}
+	if created {
+		entity.ID = id
+		return entity, false, nil
+	}
+
+	var stored domain.PurchaseTransaction
+	if err := r.mysqlPurchaseTransactionRepository.SingleWithFilterWithTx(ctx, tx,
+		[]string{"*"},
+		[]string{},
+		[]string{"external_reference = ?"},
+		&stored,
+		*entity.ExternalReference); err != nil {
+		return entity, false, err
+	}
+	if stored.CustomerID != entity.CustomerID ||
+		stored.TotalSpent != entity.TotalSpent ||
+		stored.TotalSaving != entity.TotalSaving ||
+		stored.Currency != entity.Currency ||
+		!stored.TransactionAt.Equal(entity.TransactionAt) {
+		return entity, false, response.ErrPurchaseReferenceConflict
+	}
+	return stored, true, nil
+}
+
+func (r purchaseTransactionUseCase) GetPurchaseTransactions(beegoCtx *beegoContext.Context, customerId, page, limit int, filter domain.PurchaseTransactionFilter) (*domain.PurchaseTransactionListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if err := r.checkCustomer(c, customerId); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	criteria := []string{"customer_id = ?"}
+	args := []interface{}{customerId}
+	if !filter.StartDate.IsZero() {
+		criteria = append(criteria, "transaction_at >= ?")
+		args = append(args, filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		criteria = append(criteria, "transaction_at <= ?")
+		args = append(args, filter.EndDate)
+	}
+
+	total, err := r.mysqlPurchaseTransactionRepository.CountFilter(c, []string{}, &domain.PurchaseTransaction{}, criteria, args...)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	transactions, err := r.fetchPurchaseTransactionWithFilter(c, limit, (page-1)*limit, criteria, args...)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.PurchaseTransactionResponse, 0, len(transactions))
+	for i := range transactions {
+		result = append(result, domain.NewPurchaseTransactionResponse(transactions[i], false))
+	}
+
+	return &domain.PurchaseTransactionListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r purchaseTransactionUseCase) CreatePurchaseTransaction(beegoCtx *beegoContext.Context, customerId int, request domain.PurchaseTransactionRequest) (*domain.PurchaseTransactionResponse, error) {
+	result, err := r.CreatePurchaseTransactions(beegoCtx, customerId, domain.PurchaseTransactionBatchRequest{
+		Transactions: []domain.PurchaseTransactionRequest{request},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Items[0], nil
+}
+
+// CreatePurchaseTransactions store the transactions in a single database transaction, nothing is stored when one of them fails
+func (r purchaseTransactionUseCase) CreatePurchaseTransactions(beegoCtx *beegoContext.Context, customerId int, request domain.PurchaseTransactionBatchRequest) (*domain.PurchaseTransactionBatchResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if err := r.checkCustomer(c, customerId); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	entities := make([]domain.PurchaseTransaction, 0, len(request.Transactions))
+	for _, item := range request.Transactions {
+		entity, err := r.requestToEntity(customerId, item, now)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+
+	result := &domain.PurchaseTransactionBatchResponse{
+		Items: make([]domain.PurchaseTransactionResponse, 0, len(entities)),
+	}
+	err := r.mysqlPurchaseTransactionRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		for _, entity := range entities {
+			stored, duplicate, err := r.storeWithTx(c, tx, entity)
+			if err != nil {
+				return err
+			}
+			if duplicate {
+				result.Duplicates++
+			} else {
+				result.Created++
+			}
+			result.Items = append(result.Items, domain.NewPurchaseTransactionResponse(stored, duplicate))
+		}
+		return nil
+	})
+	if err != nil {
+		if err != response.ErrPurchaseReferenceConflict {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// RefundPurchaseTransaction record a refund of the transaction, the transaction becomes refunded once
+// the refunds reach its total spent and partially refunded before
+func (r purchaseTransactionUseCase) RefundPurchaseTransaction(beegoCtx *beegoContext.Context, customerId, id int, request domain.PurchaseTransactionRefundRequest) (*domain.PurchaseTransactionResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	amount := request.Amount
+	var transaction domain.PurchaseTransaction
+	err := r.mysqlPurchaseTransactionRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if transaction, err = r.lockCustomerTransactionWithTx(c, tx, customerId, id); err != nil {
+			return err
+		}
+		if err := amount.CheckIn(transaction.Currency); err != nil {
+			return fmt.Errorf("%w: %v", response.ErrCurrencyAmountInvalid, err)
+		}
+		if transaction.Status != domain.PurchaseTransactionStatusCompleted && transaction.Status != domain.PurchaseTransactionStatusPartiallyRefunded {
+			return response.ErrPurchaseTransactionStatusInvalid
+		}
+		if amount > transaction.TotalSpent-transaction.RefundedAmount {
+			return response.ErrRefundAmountExceeded
+		}
+
+		if _, err := r.mysqlPurchaseTransactionRefundRepository.StoreWithTx(c, tx, domain.PurchaseTransactionRefund{
+			PurchaseTransactionID: transaction.ID,
+			Amount:                amount,
+			Reason:                request.Reason,
+		}); err != nil {
+			return err
+		}
+
+		transaction.RefundedAmount += amount
+		transaction.Status = domain.PurchaseTransactionStatusPartiallyRefunded
+		if transaction.RefundedAmount >= transaction.TotalSpent {
+			transaction.Status = domain.PurchaseTransactionStatusRefunded
+		}
+		transaction.UpdatedAt = time.Now()
+		return r.mysqlPurchaseTransactionRepository.UpdateSelectedFieldWithTx(c, tx,
+			[]string{"status", "refunded_amount", "updated_at"},
+			map[string]interface{}{
+				"status":          transaction.Status,
+				"refunded_amount": transaction.RefundedAmount,
+				"updated_at":      transaction.UpdatedAt,
+			},
+			transaction.ID)
+	})
+	if err != nil {
+		if err != response.ErrPurchaseTransactionStatusInvalid && err != response.ErrRefundAmountExceeded {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+
+	result := domain.NewPurchaseTransactionResponse(transaction, false)
+	return &result, nil
+}
+
+// VoidPurchaseTransaction cancel a transaction without refunds, a voided transaction is not counted by the eligibility rules
+func (r purchaseTransactionUseCase) VoidPurchaseTransaction(beegoCtx *beegoContext.Context, customerId, id int) (*domain.PurchaseTransactionResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var transaction domain.PurchaseTransaction
+	err := r.mysqlPurchaseTransactionRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if transaction, err = r.lockCustomerTransactionWithTx(c, tx, customerId, id); err != nil {
+			return err
+		}
+		if transaction.Status != domain.PurchaseTransactionStatusCompleted {
+			return response.ErrPurchaseTransactionStatusInvalid
+		}
+
+		transaction.Status = domain.PurchaseTransactionStatusVoided
+		transaction.UpdatedAt = time.Now()
+		return r.mysqlPurchaseTransactionRepository.UpdateSelectedFieldWithTx(c, tx,
+			[]string{"status", "updated_at"},
+			map[string]interface{}{
+				"status":     transaction.Status,
+				"updated_at": transaction.UpdatedAt,
+			},
+			transaction.ID)
+	})
+	if err != nil {
+		if err != response.ErrPurchaseTransactionStatusInvalid {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		}
+		return nil, err
+	}
+
+	result := domain.NewPurchaseTransactionResponse(transaction, false)
+	return &result, nil
+}
+
+// lockCustomerTransactionWithTx lock the transaction, a transaction of another customer is not found
+func (r purchaseTransactionUseCase) lockCustomerTransactionWithTx(ctx context.Context, tx *gorm.DB, customerId, id int) (domain.PurchaseTransaction, error) {
+	transaction, err := r.mysqlPurchaseTransactionRepository.LockWithTx(ctx, tx, id)
+	if err != nil {
+		return transaction, err
+	}
+	if transaction.CustomerID != customerId {
+		return transaction, gorm.ErrRecordNotFound
+	}
+	return transaction, nil
+}
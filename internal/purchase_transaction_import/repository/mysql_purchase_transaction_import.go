@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlPurchaseTransactionImportRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlPurchaseTransactionImportRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlPurchaseTransactionImportRepository {
+	return &mysqlPurchaseTransactionImportRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlPurchaseTransactionImportRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlPurchaseTransactionImportRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c mysqlPurchaseTransactionImportRepository) Store(ctx context.Context, data domain.PurchaseTransactionImport) (domain.PurchaseTransactionImport, error) {
+
+	err := c.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+func (c mysqlPurchaseTransactionImportRepository) UpdateSelectedField(ctx context.Context, field []string, values map[string]interface{}, id int) error {
+
+	return c.db.WithContext(ctx).Table(domain.PurchaseTransactionImport{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+func (c mysqlPurchaseTransactionImportRepository) UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error {
+
+	return tx.WithContext(ctx).Table(domain.PurchaseTransactionImport{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+// Claim mark the import as processing unless another process is running it, an import still processing
+// without progress since staleBefore is taken over. False is returned when the import is running elsewhere
+func (c mysqlPurchaseTransactionImportRepository) Claim(ctx context.Context, id int, staleBefore time.Time) (bool, error) {
+
+	result := c.db.WithContext(ctx).Table(domain.PurchaseTransactionImport{}.TableName()).
+		Where("id = ?", id).
+		Where("status <> ? OR updated_at < ?", domain.PurchaseTransactionImportStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     domain.PurchaseTransactionImportStatusProcessing,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (c mysqlPurchaseTransactionImportRepository) StoreErrorsWithTx(ctx context.Context, tx *gorm.DB, data []domain.PurchaseTransactionImportError) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&data).Error
+}
+
+// FetchErrors failed rows of the import in row order, afterId is the last id of the previous page
+func (c mysqlPurchaseTransactionImportRepository) FetchErrors(ctx context.Context, importId, afterId, limit int) ([]domain.PurchaseTransactionImportError, error) {
+	var data []domain.PurchaseTransactionImportError
+
+	err := c.db.WithContext(ctx).
+		Where("purchase_transaction_import_id = ?", importId).
+		Where("id > ?", afterId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlPurchaseTransactionRefundRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlPurchaseTransactionRefundRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlPurchaseTransactionRefundRepository {
+	return &mysqlPurchaseTransactionRefundRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlPurchaseTransactionRefundRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlPurchaseTransactionRefundRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlPurchaseTransactionRefundRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.PurchaseTransactionRefund) (int, error) {
+
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
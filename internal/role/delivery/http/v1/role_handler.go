@@ -0,0 +1,260 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/radyatamaa/technical-test-aichat/internal"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/middlewares"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type RoleHandler struct {
+	ZapLogger zaplogger.Logger
+	internal.BaseController
+	response.ApiResponse
+	RoleUsecase domain.RoleUseCase
+}
+
+func NewRoleHandler(roleUsecase domain.RoleUseCase, zapLogger zaplogger.Logger) {
+	pHandler := &RoleHandler{
+		ZapLogger:   zapLogger,
+		RoleUsecase: roleUsecase,
+	}
+	beego.Router("/api/v1/admin/roles", pHandler, "get:GetRoles;post:CreateRole")
+	beego.Router("/api/v1/admin/roles/:id", pHandler, "put:UpdateRole;delete:DeleteRole")
+	beego.Router("/api/v1/admin/api-keys/:id/roles", pHandler, "put:SetApiKeyRoles")
+	beego.InsertFilterChain("/api/v1/admin/roles", middlewares.RequirePermission(domain.PermissionRolesRead, http.MethodGet))
+	beego.InsertFilterChain("/api/v1/admin/roles", middlewares.RequirePermission(domain.PermissionRolesWrite, http.MethodPost))
+	beego.InsertFilterChain("/api/v1/admin/roles/:id", middlewares.RequirePermission(domain.PermissionRolesWrite))
+	beego.InsertFilterChain("/api/v1/admin/api-keys/:id/roles", middlewares.RequirePermission(domain.PermissionApiKeysWrite))
+}
+
+func (h *RoleHandler) Prepare() {
+	// permissions are checked by the filters registered with the routes
+	h.SetLangVersion()
+}
+
+// GetRoles
+// @Title GetRoles
+// @Tags Admin
+// @Summary GetRoles
+// @Description roles with their permissions, requires the roles:read permission
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.RoleListResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    page query int false "page" default(1)
+// @Param    limit query int false "limit" default(10)
+// @Router /v1/admin/roles [get]
+func (h *RoleHandler) GetRoles() {
+	page, err := h.GetInt("page", 1)
+	if err != nil || page < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+	limit, err := h.GetInt("limit", 10)
+	if err != nil || limit < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.QueryParamInvalidCode, response.ErrorCodeText(response.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.RoleUsecase.GetRoles(h.Ctx, page, limit)
+	if err != nil {
+		h.responseRoleError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// CreateRole
+// @Title CreateRole
+// @Tags Admin
+// @Summary CreateRole
+// @Description requires the roles:write permission
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param Idempotency-Key header string false "retries with the same key get the stored response"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.RoleResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 409 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 422 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    body body domain.RoleRequest true "request payload"
+// @Router /v1/admin/roles [post]
+func (h *RoleHandler) CreateRole() {
+	var request domain.RoleRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.RoleUsecase.CreateRole(h.Ctx, request)
+	if err != nil {
+		h.responseRoleError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// UpdateRole
+// @Title UpdateRole
+// @Tags Admin
+// @Summary UpdateRole
+// @Description replace the name, description and permissions of the role, requires the roles:write permission
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=domain.RoleResponse}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id role"
+// @Param    body body domain.RoleUpdateRequest true "request payload"
+// @Router /v1/admin/roles/{id} [put]
+func (h *RoleHandler) UpdateRole() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.RoleUpdateRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	request.ID = pathParam
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.RoleUsecase.UpdateRole(h.Ctx, pathParam, request)
+	if err != nil {
+		h.responseRoleError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// DeleteRole
+// @Title DeleteRole
+// @Tags Admin
+// @Summary DeleteRole
+// @Description the api keys of the role lose its permissions, requires the roles:write permission
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id role"
+// @Router /v1/admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.RoleUsecase.DeleteRole(h.Ctx, pathParam); err != nil {
+		h.responseRoleError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+// SetApiKeyRoles
+// @Title SetApiKeyRoles
+// @Tags Admin
+// @Summary SetApiKeyRoles
+// @Description replace the roles of the api key, requires the api_keys:write permission
+// @Produce json
+// @Param Accept-Language header string false "lang"
+// @Param X-API-KEY header string true "admin api key"
+// @Success 200 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 400 {object} swagger.BadRequestErrorValidationResponse{errors=[]swagger.ValidationErrors,data=object}
+// @Failure 401 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.BaseResponse{errors=[]object,data=object}
+// @Failure 408 {object} swagger.RequestTimeoutResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param    id path int true "id api key"
+// @Param    body body domain.ApiKeyRoleRequest true "request payload"
+// @Router /v1/admin/api-keys/{id}/roles [put]
+func (h *RoleHandler) SetApiKeyRoles() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.PathParamInvalidCode, response.ErrorCodeText(response.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.ApiKeyRoleRequest
+
+	if err := json.Unmarshal(h.Ctx.Input.RequestBody, &request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if err := validator.Validate.ValidateStruct(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.ApiValidationCodeError, response.ErrorCodeText(response.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	if err := h.RoleUsecase.SetApiKeyRoles(h.Ctx, pathParam, request); err != nil {
+		h.responseRoleError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *RoleHandler) responseRoleError(err error) {
+	if errors.Is(err, response.ErrUnknownPermission) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.UnknownPermission, response.ErrorCodeText(response.UnknownPermission, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, response.DataNotFoundCodeError, response.ErrorCodeText(response.DataNotFoundCodeError, h.Locale.Lang), err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.ResponseError(h.Ctx, http.StatusRequestTimeout, response.RequestTimeoutCodeError, response.ErrorCodeText(response.RequestTimeoutCodeError, h.Locale.Lang), err)
+		return
+	}
+	h.ResponseError(h.Ctx, http.StatusInternalServerError, response.ServerErrorCode, response.ErrorCodeText(response.ServerErrorCode, h.Locale.Lang), err)
+}
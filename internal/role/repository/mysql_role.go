@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/paginator"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type mysqlRoleRepository struct {
+	zapLogger zaplogger.Logger
+	db        *gorm.DB
+}
+
+func NewMysqlRoleRepository(db *gorm.DB, zapLogger zaplogger.Logger) domain.MysqlRoleRepository {
+	return &mysqlRoleRepository{
+		db:        db,
+		zapLogger: zapLogger,
+	}
+}
+
+func (c mysqlRoleRepository) DB() *gorm.DB {
+	return c.db
+}
+
+func (c mysqlRoleRepository) CountFilter(ctx context.Context, associate []string, model interface{}, criteria []string, args ...interface{}) (int, error) {
+	var count int64
+	db := c.db.WithContext(ctx)
+
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(criteria) > 0 && len(args) == len(criteria) {
+		for i := range criteria {
+			db = db.Where(criteria[i], args[i])
+		}
+	}
+
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (c mysqlRoleRepository) FetchWithFilter(ctx context.Context, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (interface{}, error) {
+	p := paginator.NewPaginator(c.db, offset, limit, model)
+	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (c mysqlRoleRepository) SingleWithFilter(ctx context.Context, fields, associate, filter []string, model interface{}, args ...interface{}) error {
+
+	db := c.db.WithContext(ctx)
+
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+	if len(associate) > 0 {
+		for _, v := range associate {
+			db.Joins(v)
+		}
+	}
+
+	if len(filter) > 0 && len(args) == len(filter) {
+		for i := range filter {
+			db = db.Where(filter[i], args[i])
+		}
+	}
+
+	if err := db.First(model).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c mysqlRoleRepository) StoreWithTx(ctx context.Context, tx *gorm.DB, data domain.Role) (int, error) {
+
+	err := tx.WithContext(ctx).Omit("Permissions").Create(&data).Error
+	if err != nil {
+		return data.ID, err
+	}
+	return data.ID, nil
+}
+
+func (c mysqlRoleRepository) UpdateSelectedFieldWithTx(ctx context.Context, tx *gorm.DB, field []string, values map[string]interface{}, id int) error {
+
+	return tx.WithContext(ctx).Table(domain.Role{}.TableName()).Select(field).Where("id =?", id).Updates(values).Error
+}
+
+// DeleteWithTx delete the role with its permissions and api key assignments
+func (c mysqlRoleRepository) DeleteWithTx(ctx context.Context, tx *gorm.DB, id int) error {
+	db := tx.WithContext(ctx)
+
+	if err := db.Exec("delete from "+domain.RolePermission{}.TableName()+" where role_id =?", id).Error; err != nil {
+		return err
+	}
+	if err := db.Exec("delete from "+domain.ApiKeyRole{}.TableName()+" where role_id =?", id).Error; err != nil {
+		return err
+	}
+	return db.Exec("delete from "+domain.Role{}.TableName()+" where id =?", id).Error
+}
+
+func (c mysqlRoleRepository) ReplacePermissionsWithTx(ctx context.Context, tx *gorm.DB, roleId int, permissions []string) error {
+	db := tx.WithContext(ctx)
+
+	if err := db.Exec("delete from "+domain.RolePermission{}.TableName()+" where role_id =?", roleId).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]domain.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		rows = append(rows, domain.RolePermission{RoleID: roleId, Permission: permission})
+	}
+	return db.Create(&rows).Error
+}
+
+func (c mysqlRoleRepository) ReplaceApiKeyRolesWithTx(ctx context.Context, tx *gorm.DB, apiKeyId int, roleIds []int) error {
+	db := tx.WithContext(ctx)
+
+	if err := db.Exec("delete from "+domain.ApiKeyRole{}.TableName()+" where api_key_id =?", apiKeyId).Error; err != nil {
+		return err
+	}
+	if len(roleIds) == 0 {
+		return nil
+	}
+
+	rows := make([]domain.ApiKeyRole, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		rows = append(rows, domain.ApiKeyRole{ApiKeyID: apiKeyId, RoleID: roleId})
+	}
+	return db.Create(&rows).Error
+}
+
+func (c mysqlRoleRepository) FetchRolePermissions(ctx context.Context, roleIds []int) ([]domain.RolePermission, error) {
+	var result []domain.RolePermission
+	if len(roleIds) == 0 {
+		return result, nil
+	}
+
+	err := c.db.WithContext(ctx).Where("role_id IN ?", roleIds).Order("role_id ASC, permission ASC").Find(&result).Error
+	return result, err
+}
+
+func (c mysqlRoleRepository) FetchApiKeyPermissions(ctx context.Context, apiKeyId int) ([]string, error) {
+	var result []string
+
+	err := c.db.WithContext(ctx).
+		Table(domain.RolePermission{}.TableName()+" rp").
+		Joins("JOIN "+domain.ApiKeyRole{}.TableName()+" akr ON akr.role_id = rp.role_id").
+		Where("akr.api_key_id = ?", apiKeyId).
+		Distinct().
+		Pluck("rp.permission", &result).Error
+	return result, err
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/response"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/gorm"
+)
+
+type roleUseCase struct {
+	zapLogger             zaplogger.Logger
+	contextTimeout        time.Duration
+	mysqlRoleRepository   domain.MysqlRoleRepository
+	mysqlApiKeyRepository domain.MysqlApiKeyRepository
+}
+
+func NewRoleUseCase(timeout time.Duration,
+	mysqlRoleRepository domain.MysqlRoleRepository,
+	mysqlApiKeyRepository domain.MysqlApiKeyRepository,
+	zapLogger zaplogger.Logger) domain.RoleUseCase {
+	return &roleUseCase{
+		mysqlRoleRepository:   mysqlRoleRepository,
+		mysqlApiKeyRepository: mysqlApiKeyRepository,
+		contextTimeout:        timeout,
+		zapLogger:             zapLogger,
+	}
+}
+
+// QUERY ROLE
+func (r roleUseCase) singleRoleWithFilter(ctx context.Context, filter []string, args ...interface{}) (*domain.Role, error) {
+	var entity domain.Role
+	if err := r.mysqlRoleRepository.SingleWithFilter(
+		ctx,
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&entity, args...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r roleUseCase) fetchRoleWithFilter(ctx context.Context, limit, offset int, filter []string, args ...interface{}) ([]domain.Role, error) {
+
+	if role, err := r.mysqlRoleRepository.FetchWithFilter(
+		ctx,
+		limit,
+		offset,
+		"name ASC",
+		[]string{
+			"*",
+		},
+		[]string{},
+		filter,
+		&[]domain.Role{}, args...); err != nil {
+		return nil, err
+	} else {
+		if result, ok := role.(*[]domain.Role); !ok {
+			return []domain.Role{}, nil
+		} else {
+			return *result, nil
+		}
+	}
+}
+
+// permissionList trimmed permissions without duplicates, ErrUnknownPermission when one of them is not a Permissions
+func permissionList(values []string) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !domain.IsPermission(value) {
+			return nil, fmt.Errorf("%w: %s", response.ErrUnknownPermission, value)
+		}
+		if !helper.ItemExists(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+func (r roleUseCase) GetRoles(beegoCtx *beegoContext.Context, page, limit int) (*domain.RoleListResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	total, err := r.mysqlRoleRepository.CountFilter(c, []string{}, &domain.Role{}, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	roles, err := r.fetchRoleWithFilter(c, limit, (page-1)*limit, []string{})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	roleIds := make([]int, 0, len(roles))
+	for _, role := range roles {
+		roleIds = append(roleIds, role.ID)
+	}
+	permissions, err := r.mysqlRoleRepository.FetchRolePermissions(c, roleIds)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := make([]domain.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		for _, permission := range permissions {
+			if permission.RoleID == role.ID {
+				role.Permissions = append(role.Permissions, permission)
+			}
+		}
+		result = append(result, domain.NewRoleResponse(role))
+	}
+
+	return &domain.RoleListResponse{
+		Items:      result,
+		Pagination: domain.NewPaginationResponse(page, limit, total),
+	}, nil
+}
+
+func (r roleUseCase) CreateRole(beegoCtx *beegoContext.Context, request domain.RoleRequest) (*domain.RoleResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	permissions, err := permissionList(request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.Role{
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	err = r.mysqlRoleRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		id, err := r.mysqlRoleRepository.StoreWithTx(c, tx, role)
+		if err != nil {
+			return err
+		}
+		role.ID = id
+		return r.mysqlRoleRepository.ReplacePermissionsWithTx(c, tx, id, permissions)
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	return r.roleResponse(beegoCtx, c, role.ID)
+}
+
+// UpdateRole replace the name, description and permissions of the role, the api keys of the role
+// are granted the new permissions on their next request
+func (r roleUseCase) UpdateRole(beegoCtx *beegoContext.Context, id int, request domain.RoleUpdateRequest) (*domain.RoleResponse, error) {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if _, err := r.singleRoleWithFilter(c, []string{"id = ?"}, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	permissions, err := permissionList(request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.mysqlRoleRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := r.mysqlRoleRepository.UpdateSelectedFieldWithTx(c, tx,
+			[]string{"name", "description", "updated_at"},
+			map[string]interface{}{
+				"name":        request.Name,
+				"description": request.Description,
+				"updated_at":  time.Now(),
+			}, id); err != nil {
+			return err
+		}
+		return r.mysqlRoleRepository.ReplacePermissionsWithTx(c, tx, id, permissions)
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	return r.roleResponse(beegoCtx, c, id)
+}
+
+func (r roleUseCase) roleResponse(beegoCtx *beegoContext.Context, ctx context.Context, id int) (*domain.RoleResponse, error) {
+	role, err := r.singleRoleWithFilter(ctx, []string{"id = ?"}, id)
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+	role.Permissions, err = r.mysqlRoleRepository.FetchRolePermissions(ctx, []int{id})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return nil, err
+	}
+
+	result := domain.NewRoleResponse(*role)
+	return &result, nil
+}
+
+// DeleteRole delete the role, the api keys of the role lose its permissions
+func (r roleUseCase) DeleteRole(beegoCtx *beegoContext.Context, id int) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	if _, err := r.singleRoleWithFilter(c, []string{"id = ?"}, id); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+
+	err := r.mysqlRoleRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		return r.mysqlRoleRepository.DeleteWithTx(c, tx, id)
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	return nil
+}
+
+// SetApiKeyRoles replace the roles of the api key, gorm.ErrRecordNotFound when the key or one of the roles
+// does not exist
+func (r roleUseCase) SetApiKeyRoles(beegoCtx *beegoContext.Context, apiKeyId int, request domain.ApiKeyRoleRequest) error {
+	c, cancel := context.WithTimeout(beegoCtx.Request.Context(), r.contextTimeout)
+	defer cancel()
+
+	var apiKey domain.ApiKey
+	if err := r.mysqlApiKeyRepository.SingleWithFilter(c, []string{"id"}, []string{}, []string{"id = ?"}, &apiKey, apiKeyId); err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+
+	roleIds := make([]int, 0, len(request.RoleIDs))
+	for _, roleId := range request.RoleIDs {
+		if !helper.ItemExists(roleIds, roleId) {
+			roleIds = append(roleIds, roleId)
+		}
+	}
+	if len(roleIds) > 0 {
+		count, err := r.mysqlRoleRepository.CountFilter(c, []string{}, &domain.Role{}, []string{"id IN ?"}, roleIds)
+		if err != nil {
+			beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+			return err
+		}
+		if count != len(roleIds) {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	err := r.mysqlRoleRepository.DB().WithContext(c).Transaction(func(tx *gorm.DB) error {
+		return r.mysqlRoleRepository.ReplaceApiKeyRolesWithTx(c, tx, apiKeyId, roleIds)
+	})
+	if err != nil {
+		beegoCtx.Input.SetData("stackTrace", r.zapLogger.SetMessageLog(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package seeder
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+)
+
+var (
+	firstNames = []string{"Adi", "Budi", "Citra", "Dewi", "Eka", "Fajar", "Gita", "Hadi", "Indah", "Joko", "Kartika", "Lestari", "Made", "Nur", "Putri", "Rizky", "Sari", "Tono", "Wulan", "Yusuf"}
+	lastNames  = []string{"Pratama", "Saputra", "Wijaya", "Kusuma", "Santoso", "Hidayat", "Nugroho", "Lestari", "Setiawan", "Permana"}
+	genders    = []string{"Laki-Laki", "Perempuan"}
+)
+
+// voucherCodeLetters letters of seeded voucher codes
+const voucherCodeLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// customerFactory customers with unique emails within the scenario
+func customerFactory(random *rand.Rand, scenario string) *database.Factory {
+	sequence := 0
+	return database.NewFactory(func() interface{} {
+		sequence++
+		firstName := firstNames[random.Intn(len(firstNames))]
+		lastName := lastNames[random.Intn(len(lastNames))]
+		dateOfBirth := time.Date(1960+random.Intn(45), time.Month(1+random.Intn(12)), 1+random.Intn(28), 0, 0, 0, 0, time.Local)
+
+		return &domain.Customer{
+			FirstName:     firstName,
+			LastName:      lastName,
+			Gender:        genders[random.Intn(len(genders))],
+			DateOfBirth:   dateOfBirth.Format(helper.DateFormatDefault),
+			ContactNumber: fmt.Sprintf("08%010d", random.Int63n(10000000000)),
+			Email:         fmt.Sprintf("%s.%s.%d@%s.example.com", strings.ToLower(firstName), strings.ToLower(lastName), sequence, strings.ReplaceAll(scenario, "_", "-")),
+		}
+	})
+}
+
+// purchaseTransactionFactory completed purchases made within purchaseWindowDays before now
+func purchaseTransactionFactory(random *rand.Rand, now time.Time, minSpent, maxSpent money.Amount) *database.Factory {
+	return database.NewFactory(func() interface{} {
+		totalSpent := minSpent + money.Amount(random.Int63n(int64(maxSpent-minSpent)+1))
+
+		return &domain.PurchaseTransaction{
+			TotalSpent:    totalSpent,
+			TotalSaving:   money.Amount(random.Int63n(int64(totalSpent)/4 + 1)),
+			Currency:      money.DefaultCurrency,
+			TransactionAt: now.Add(-time.Duration(random.Int63n(int64(purchaseWindowDays * 24 * time.Hour)))).Truncate(time.Second),
+			Status:        domain.PurchaseTransactionStatusCompleted,
+		}
+	})
+}
+
+// voucherFactory available vouchers with a random 10 letters code
+func voucherFactory(random *rand.Rand) *database.Factory {
+	return database.NewFactory(func() interface{} {
+		code := make([]byte, 10)
+		for i := range code {
+			code[i] = voucherCodeLetters[random.Intn(len(voucherCodeLetters))]
+		}
+
+		return &domain.CustomerVoucher{
+			VoucherCode: string(code),
+		}
+	})
+}
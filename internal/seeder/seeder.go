@@ -0,0 +1,213 @@
+package seeder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"gorm.io/gorm"
+)
+
+// seed scenarios, customers whose purchases pass or fail a default eligibility rule
+const (
+	// ScenarioHappyPath customers eligible for a voucher
+	ScenarioHappyPath = "happy_path"
+	// ScenarioUnderSpend customers with enough purchases spending less than the threshold
+	ScenarioUnderSpend = "under_spend"
+	// ScenarioTooFewPurchases customers spending enough in less than the required purchases
+	ScenarioTooFewPurchases = "too_few_purchases"
+	// ScenarioExpiredBooking eligible customers whose voucher booking expired before the photo verification
+	ScenarioExpiredBooking = "expired_booking"
+)
+
+// Scenarios every seed scenario in run order
+var Scenarios = []string{ScenarioHappyPath, ScenarioUnderSpend, ScenarioTooFewPurchases, ScenarioExpiredBooking}
+
+const (
+	// CampaignName name of the seeded campaign, the scenarios book its vouchers
+	CampaignName = "Anniversary Campaign"
+	// campaignSeeder seeder of the campaign and its voucher pool, run before the scenarios
+	campaignSeeder = "campaign"
+	// purchaseWindowDays purchases are seeded within the window of the default purchase count rule
+	purchaseWindowDays = 29
+)
+
+var ErrUnknownScenario = errors.New("unknown seed scenario")
+
+// Config seeded data, the same RandomSeed and ReferenceTime generate the same records
+type Config struct {
+	RandomSeed int64
+	// ReferenceTime the campaign, purchase and booking dates are relative to it
+	ReferenceTime time.Time
+	// BatchSize records inserted per statement
+	BatchSize int
+	// Customers customers of every scenario
+	Customers int
+	// Vouchers available vouchers of the seeded campaign
+	Vouchers  int
+	Scenarios []string
+}
+
+// DefaultConfig every scenario with 100 customers relative to the current time
+func DefaultConfig() Config {
+	return Config{
+		RandomSeed:    20220713,
+		ReferenceTime: time.Now().Truncate(time.Second),
+		BatchSize:     500,
+		Customers:     100,
+		Vouchers:      1000,
+		Scenarios:     Scenarios,
+	}
+}
+
+// Seeders campaign seeder followed by the seeders of the configured scenarios
+func Seeders(config Config) ([]database.Seeder, error) {
+	scenarios := map[string]func(tx *gorm.DB, random *rand.Rand) error{
+		ScenarioHappyPath:       config.seedHappyPath,
+		ScenarioUnderSpend:      config.seedUnderSpend,
+		ScenarioTooFewPurchases: config.seedTooFewPurchases,
+		ScenarioExpiredBooking:  config.seedExpiredBooking,
+	}
+
+	seeders := []database.Seeder{{Name: campaignSeeder, Run: config.seedCampaign}}
+	for _, name := range config.Scenarios {
+		run, ok := scenarios[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownScenario, name)
+		}
+		seeders = append(seeders, database.Seeder{Name: name, Run: run})
+	}
+	return seeders, nil
+}
+
+// Seed store the configured scenarios not stored yet, returns the names of the seeders run
+func Seed(ctx context.Context, db *gorm.DB, config Config) ([]string, error) {
+	seeders, err := Seeders(config)
+	if err != nil {
+		return nil, err
+	}
+	return database.SeederData(ctx, db, config.RandomSeed, seeders...)
+}
+
+func (c Config) seedCampaign(tx *gorm.DB, random *rand.Rand) error {
+	campaign := domain.Campaign{
+		Name:                      CampaignName,
+		StartDate:                 c.ReferenceTime,
+		EndDate:                   c.ReferenceTime.AddDate(0, 1, 0),
+		Budget:                    c.Vouchers,
+		PerCustomerLimit:          1,
+		PhotoVerificationRequired: true,
+		Currency:                  money.DefaultCurrency,
+	}
+	if err := tx.Create(&campaign).Error; err != nil {
+		return err
+	}
+
+	_, err := voucherFactory(random).
+		Override(&domain.CustomerVoucher{CampaignID: campaign.ID}).
+		SaveInBatches(tx, c.Vouchers, c.BatchSize)
+	return err
+}
+
+func (c Config) seedHappyPath(tx *gorm.DB, random *rand.Rand) error {
+	_, err := c.seedCustomers(tx, random, ScenarioHappyPath, 3, 5, money.FromFloat(40), money.FromFloat(150))
+	return err
+}
+
+// seedUnderSpend at most 5 purchases of 19.00, below the 100.00 spend threshold
+func (c Config) seedUnderSpend(tx *gorm.DB, random *rand.Rand) error {
+	_, err := c.seedCustomers(tx, random, ScenarioUnderSpend, 3, 5, money.FromFloat(5), money.FromFloat(19))
+	return err
+}
+
+func (c Config) seedTooFewPurchases(tx *gorm.DB, random *rand.Rand) error {
+	_, err := c.seedCustomers(tx, random, ScenarioTooFewPurchases, 1, 2, money.FromFloat(100), money.FromFloat(250))
+	return err
+}
+
+// seedExpiredBooking eligible customers with a booked voucher past its expired date, released by the booking sweep.
+// The vouchers are reserved from the pool of the campaign like a link voucher request reserves them, so the campaign
+// keeps its budget and the vouchers no customer redeemed have no customer. Customers beyond the free vouchers
+// of the pool are seeded without a booking.
+func (c Config) seedExpiredBooking(tx *gorm.DB, random *rand.Rand) error {
+	var campaign domain.Campaign
+	if err := tx.Where("name = ?", CampaignName).First(&campaign).Error; err != nil {
+		return err
+	}
+
+	customers, err := c.seedCustomers(tx, random, ScenarioExpiredBooking, 3, 5, money.FromFloat(40), money.FromFloat(150))
+	if err != nil {
+		return err
+	}
+
+	var vouchers []domain.CustomerVoucher
+	if err := tx.Where("campaign_id = ? AND is_redeem = ? AND customer_id IS NULL AND reserved_until IS NULL", campaign.ID, false).
+		Order("id").
+		Limit(len(customers)).
+		Find(&vouchers).Error; err != nil {
+		return err
+	}
+
+	now := c.ReferenceTime.Truncate(time.Second)
+	for i, voucher := range vouchers {
+		customer := customers[i]
+		expiredDate := now.Add(-time.Duration(1+random.Intn(48)) * time.Hour)
+
+		if err := tx.Model(&domain.CustomerVoucher{}).Where("id = ?", voucher.ID).Update("reserved_until", expiredDate).Error; err != nil {
+			return err
+		}
+
+		book := domain.CustomerVoucherBook{
+			CustomerID:        customer.ID,
+			CustomerVoucherID: voucher.ID,
+			CampaignID:        campaign.ID,
+			ExpiredDate:       expiredDate,
+			Status:            domain.CustomerVoucherBookStatusBooked,
+		}
+		if err := tx.Create(&book).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&domain.CustomerVoucherBookEvent{
+			CustomerVoucherBookID: book.ID,
+			CustomerID:            customer.ID,
+			ToStatus:              domain.CustomerVoucherBookStatusBooked,
+			Reason:                "voucher booked",
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedCustomers store the customers of the scenario with minPurchases to maxPurchases completed purchases
+// each, spending between minSpent and maxSpent per purchase
+func (c Config) seedCustomers(tx *gorm.DB, random *rand.Rand, scenario string, minPurchases, maxPurchases int, minSpent, maxSpent money.Amount) ([]*domain.Customer, error) {
+	if c.Customers <= 0 {
+		return nil, nil
+	}
+
+	records, err := customerFactory(random, scenario).SaveInBatches(tx, c.Customers, c.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	customers := records.([]*domain.Customer)
+
+	now := c.ReferenceTime.Truncate(time.Second)
+	var transactions []*domain.PurchaseTransaction
+	for _, customer := range customers {
+		count := minPurchases + random.Intn(maxPurchases-minPurchases+1)
+		generated := purchaseTransactionFactory(random, now, minSpent, maxSpent).
+			Override(&domain.PurchaseTransaction{CustomerID: customer.ID}).
+			Generate(count).([]*domain.PurchaseTransaction)
+		transactions = append(transactions, generated...)
+	}
+	if err := tx.CreateInBatches(transactions, c.BatchSize).Error; err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
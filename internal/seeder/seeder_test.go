@@ -0,0 +1,134 @@
+package seeder_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/seeder"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"gorm.io/gorm"
+)
+
+// seededRecords records of the seeded tables without their generated ids and creation dates
+type seededRecords struct {
+	Campaigns []map[string]interface{}
+	Customers []map[string]interface{}
+	Purchases []map[string]interface{}
+	Vouchers  []map[string]interface{}
+	Bookings  []map[string]interface{}
+}
+
+func seed(t *testing.T, config seeder.Config) *gorm.DB {
+	t.Helper()
+
+	db := testutil.NewSQLiteDB(t)
+	if _, err := seeder.Seed(context.Background(), db, config); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func readSeededRecords(t *testing.T, db *gorm.DB) seededRecords {
+	t.Helper()
+
+	var records seededRecords
+	for _, query := range []struct {
+		model  interface{}
+		fields string
+		result *[]map[string]interface{}
+	}{
+		{&domain.Campaign{}, "name, start_date, end_date, budget", &records.Campaigns},
+		{&domain.Customer{}, "first_name, last_name, gender, date_of_birth, contact_number, email", &records.Customers},
+		{&domain.PurchaseTransaction{}, "customer_id, total_spent, total_saving, transaction_at", &records.Purchases},
+		{&domain.CustomerVoucher{}, "voucher_code, customer_id, is_redeem, reserved_until", &records.Vouchers},
+		{&domain.CustomerVoucherBook{}, "customer_id, customer_voucher_id, expired_date, status", &records.Bookings},
+	} {
+		if err := db.Model(query.model).Select(query.fields).Order("id").Find(query.result).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return records
+}
+
+func TestSeedIsReproducible(t *testing.T) {
+	config := seeder.DefaultConfig()
+	config.ReferenceTime = time.Date(2022, 7, 13, 10, 0, 0, 0, time.Local)
+	config.Customers = 5
+	config.Vouchers = 20
+
+	first := readSeededRecords(t, seed(t, config))
+	second := readSeededRecords(t, seed(t, config))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("seeds of the same random seed and reference time differ")
+	}
+
+	for _, campaign := range first.Campaigns {
+		if got := campaign["start_date"].(time.Time); !got.Equal(config.ReferenceTime) {
+			t.Errorf("campaign start date = %v, want %v", got, config.ReferenceTime)
+		}
+	}
+	for _, purchase := range first.Purchases {
+		transactionAt := purchase["transaction_at"].(time.Time)
+		if transactionAt.After(config.ReferenceTime) || transactionAt.Before(config.ReferenceTime.AddDate(0, 0, -30)) {
+			t.Errorf("purchase at %v, want within 30 days before %v", transactionAt, config.ReferenceTime)
+		}
+	}
+
+	config.ReferenceTime = config.ReferenceTime.Add(time.Hour)
+	if later := readSeededRecords(t, seed(t, config)); reflect.DeepEqual(first.Purchases, later.Purchases) {
+		t.Errorf("purchases do not move with the reference time")
+	}
+}
+
+func TestSeedExpiredBookingKeepsTheBudget(t *testing.T) {
+	config := seeder.DefaultConfig()
+	config.ReferenceTime = time.Date(2022, 7, 13, 10, 0, 0, 0, time.Local)
+	config.Customers = 5
+	config.Vouchers = 3
+	config.Scenarios = []string{seeder.ScenarioExpiredBooking}
+	db := seed(t, config)
+
+	var campaign domain.Campaign
+	if err := db.Where("name = ?", seeder.CampaignName).First(&campaign).Error; err != nil {
+		t.Fatal(err)
+	}
+	var vouchers []domain.CustomerVoucher
+	if err := db.Where("campaign_id = ?", campaign.ID).Find(&vouchers).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(vouchers) != campaign.Budget {
+		t.Errorf("campaign has %d vouchers, want its budget %d", len(vouchers), campaign.Budget)
+	}
+	for _, voucher := range vouchers {
+		if voucher.CustomerID != nil {
+			t.Errorf("voucher %d is claimed by customer %d", voucher.ID, *voucher.CustomerID)
+		}
+		if voucher.IsRedeem {
+			t.Errorf("voucher %d is redeemed", voucher.ID)
+		}
+		if voucher.ReservedUntil == nil || !voucher.ReservedUntil.Before(config.ReferenceTime) {
+			t.Errorf("voucher %d is reserved until %v, want before %v", voucher.ID, voucher.ReservedUntil, config.ReferenceTime)
+		}
+	}
+
+	var books []domain.CustomerVoucherBook
+	if err := db.Find(&books).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != len(vouchers) {
+		t.Fatalf("%d bookings of %d customers, want one per voucher %d", len(books), config.Customers, len(vouchers))
+	}
+	customers := map[int]bool{}
+	for _, book := range books {
+		if book.Status != domain.CustomerVoucherBookStatusBooked || !book.ExpiredDate.Before(config.ReferenceTime) {
+			t.Errorf("booking %d is %s until %v, want an expired booking", book.ID, book.Status, book.ExpiredDate)
+		}
+		if customers[book.CustomerID] {
+			t.Errorf("customer %d has more than one booking", book.CustomerID)
+		}
+		customers[book.CustomerID] = true
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+)
+
+type localStorage struct {
+	root string
+}
+
+// NewLocalStorage storage writing objects as files below root
+func NewLocalStorage(root string) domain.Storage {
+	return &localStorage{root: root}
+}
+
+func (s localStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see a partial object
+	tmp, err := ioutil.TempFile(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s localStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(s.root, filepath.FromSlash(key)))
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Config settings of every driver, Driver selects the one in use
+type Config struct {
+	Driver    string
+	Path      string
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Timeout   time.Duration
+}
+
+// NewStorage storage selected by the config
+func NewStorage(config Config) (domain.Storage, error) {
+	switch config.Driver {
+	case DriverLocal:
+		return NewLocalStorage(config.Path), nil
+	case DriverS3:
+		if config.Endpoint == "" || config.Bucket == "" {
+			return nil, fmt.Errorf("storage driver %s requires endpoint and bucket", config.Driver)
+		}
+		return NewS3Storage(config.Endpoint, config.Bucket, config.Region, config.AccessKey, config.SecretKey, config.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", config.Driver)
+	}
+}
+
+// cleanKey reject keys escaping the storage root
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,131 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/migrations"
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewSQLiteDB file backed sqlite database migrated by the sql files of the migrations, removed with the test.
+// Every transaction takes the write lock when it begins, so parallel transactions run one after another and
+// the row locks of the repositories are never contended, tests of the locks use NewIntegrationDB.
+func NewSQLiteDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=30000&_txlock=immediate&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(8)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	return db
+}
+
+// NewIntegrationDB database of TEST_DATABASE_DRIVER (mysql, postgres or mssql) at TEST_DATABASE_DSN, the test
+// is skipped when they are not set. Every migration is reverted and applied again before the test and reverted
+// after it, the dsn must point to a database used only by the tests. Its transactions run concurrently so
+// parallel requests contend for the row locks of the repositories.
+func NewIntegrationDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	driver, dsn := os.Getenv("TEST_DATABASE_DRIVER"), os.Getenv("TEST_DATABASE_DSN")
+	if driver == "" || dsn == "" {
+		t.Skip("TEST_DATABASE_DRIVER and TEST_DATABASE_DSN are not set")
+	}
+
+	var dialector gorm.Dialector
+	switch driver {
+	case database.MysqlDriver:
+		dialector = mysql.Open(dsn)
+	case database.PostgresDriver:
+		dialector = postgres.Open(dsn)
+	case database.SqlServerDriver:
+		dialector = sqlserver.Open(dsn)
+	default:
+		t.Fatalf("unsupported TEST_DATABASE_DRIVER %s", driver)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open %s: %v", driver, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open %s: %v", driver, err)
+	}
+	sqlDB.SetMaxOpenConns(16)
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		t.Fatalf("migrate %s: %v", driver, err)
+	}
+	revert := func() error {
+		status, err := migrator.Status(context.Background())
+		if err != nil {
+			return err
+		}
+		_, err = migrator.Down(context.Background(), len(status))
+		return err
+	}
+	if err := revert(); err != nil {
+		t.Fatalf("migrate %s: %v", driver, err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate %s: %v", driver, err)
+	}
+	t.Cleanup(func() {
+		if err := revert(); err != nil {
+			t.Errorf("migrate %s: %v", driver, err)
+		}
+		_ = sqlDB.Close()
+	})
+	return db
+}
+
+// NewLogger logger writing to a file of the test
+func NewLogger(t testing.TB) zaplogger.Logger {
+	t.Helper()
+
+	return zaplogger.NewZapLogger(filepath.Join(t.TempDir(), "test.log"), "")
+}
+
+// NewContext beego context of the request as the usecases receive it from a handler
+func NewContext(request *http.Request) *beegoContext.Context {
+	ctx := beegoContext.NewContext()
+	ctx.Reset(httptest.NewRecorder(), request)
+	return ctx
+}
@@ -0,0 +1,77 @@
+package testutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// photoSize width and height of the generated photos, above the minimum of the image validator
+const photoSize = 300
+
+var (
+	skinColor       = color.RGBA{R: 220, G: 170, B: 140, A: 255}
+	backgroundColor = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+)
+
+// NewPhoto png photo whose center region, where the local face verifier samples, holds skinRatio skin tone pixels.
+// The local face verifier with the default ratios verifies 1, finds 0.2 inconclusive and rejects 0.
+func NewPhoto(t testing.TB, skinRatio float64) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, photoSize, photoSize))
+	margin := photoSize / 5
+	skinRows := int(float64(photoSize-2*margin) * skinRatio)
+	for y := 0; y < photoSize; y++ {
+		for x := 0; x < photoSize; x++ {
+			img.Set(x, y, backgroundColor)
+			if y >= margin && y < margin+skinRows && x >= margin && x < photoSize-margin {
+				img.Set(x, y, skinColor)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode photo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// NewMultipartRequest request uploading data as the file of the form field
+func NewMultipartRequest(t testing.TB, method, target, field, filename string, data []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	request := httptest.NewRequest(method, target, &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+// NewFileHeader uploaded file as the handlers pass it to the usecases
+func NewFileHeader(t testing.TB, filename string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	request := NewMultipartRequest(t, http.MethodPost, "/", "file", filename, data)
+	if err := request.ParseMultipartForm(32 << 20); err != nil {
+		t.Fatalf("parse form: %v", err)
+	}
+	return request.MultipartForm.File["file"][0]
+}
@@ -0,0 +1,142 @@
+package testutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	campaignRepository "github.com/radyatamaa/technical-test-aichat/internal/campaign/repository"
+	campaignRuleRepository "github.com/radyatamaa/technical-test-aichat/internal/campaign_rule/repository"
+	currencyRateRepository "github.com/radyatamaa/technical-test-aichat/internal/currency_rate/repository"
+	currencyRateUsecase "github.com/radyatamaa/technical-test-aichat/internal/currency_rate/usecase"
+	customerRepository "github.com/radyatamaa/technical-test-aichat/internal/customer/repository"
+	customerUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer/usecase"
+	customerVoucherRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher/repository"
+	customerVoucherBookRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/repository"
+	customerVoucherBookUsecase "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book/usecase"
+	customerVoucherBookAttemptRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book_attempt/repository"
+	customerVoucherBookEventRepository "github.com/radyatamaa/technical-test-aichat/internal/customer_voucher_book_event/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/eligibility"
+	"github.com/radyatamaa/technical-test-aichat/internal/faceverification"
+	purchaseTransactionRepository "github.com/radyatamaa/technical-test-aichat/internal/purchase_transaction/repository"
+	"github.com/radyatamaa/technical-test-aichat/internal/storage"
+	"github.com/radyatamaa/technical-test-aichat/pkg/imagevalidator"
+	"gorm.io/gorm"
+)
+
+// UseCases usecases of the voucher booking wired like cmd/app.go on a test database
+type UseCases struct {
+	DB                  *gorm.DB
+	Customer            domain.CustomerUseCase
+	CustomerVoucherBook domain.CustomerVoucherBookUseCase
+	Storage             domain.Storage
+}
+
+// NewUseCases usecases on the database without default eligibility rules, with the local face verifier
+// and the photos stored below a directory of the test
+func NewUseCases(t testing.TB, db *gorm.DB, photoVerificationConfig customerUsecase.PhotoVerificationConfig) UseCases {
+	t.Helper()
+
+	zapLog := NewLogger(t)
+	timeout := 30 * time.Second
+
+	customerRepo := customerRepository.NewMysqlCustomerRepository(db, zapLog)
+	customerVoucherRepo := customerVoucherRepository.NewMysqlCustomerVoucherRepository(db, zapLog)
+	customerVoucherBookRepo := customerVoucherBookRepository.NewMysqlCCustomerVoucherBookRepository(db, zapLog)
+	purchaseTransactionRepo := purchaseTransactionRepository.NewPurchaseTransactionRepository(db, zapLog)
+	campaignRepo := campaignRepository.NewMysqlCampaignRepository(db, zapLog)
+	campaignRuleRepo := campaignRuleRepository.NewMysqlCampaignRuleRepository(db, zapLog)
+	customerVoucherBookEventRepo := customerVoucherBookEventRepository.NewMysqlCustomerVoucherBookEventRepository(db, zapLog)
+	customerVoucherBookAttemptRepo := customerVoucherBookAttemptRepository.NewMysqlCustomerVoucherBookAttemptRepository(db, zapLog)
+	currencyRateRepo := currencyRateRepository.NewMysqlCurrencyRateRepository(db, zapLog)
+
+	fileStorage := storage.NewLocalStorage(filepath.Join(t.TempDir(), "storage"))
+	eligibilityEngine := eligibility.NewEligibilityEngine(nil,
+		campaignRuleRepo,
+		purchaseTransactionRepo,
+		customerVoucherRepo,
+		currencyRateUsecase.NewCurrencyRateUseCase(timeout, currencyRateRepo, zapLog))
+
+	customerVoucherBookUcase := customerVoucherBookUsecase.NewCustomerVoucherBookUseCase(timeout,
+		customerRepo,
+		customerVoucherRepo,
+		customerVoucherBookRepo,
+		customerVoucherBookEventRepo,
+		customerVoucherBookAttemptRepo,
+		fileStorage,
+		zapLog)
+	customerUcase := customerUsecase.NewCustomerUseCase(timeout,
+		customerRepo,
+		customerVoucherRepo,
+		customerVoucherBookRepo,
+		purchaseTransactionRepo,
+		campaignRepo,
+		eligibilityEngine,
+		customerVoucherBookUcase,
+		imagevalidator.NewValidator(imagevalidator.DefaultConfig()),
+		faceverification.NewLocalFaceVerifier(0.25, 0.1),
+		fileStorage,
+		customerVoucherBookAttemptRepo,
+		photoVerificationConfig,
+		zapLog)
+
+	return UseCases{
+		DB:                  db,
+		Customer:            customerUcase,
+		CustomerVoucherBook: customerVoucherBookUcase,
+		Storage:             fileStorage,
+	}
+}
+
+// CreateCampaign running campaign with the given budget and as many vouchers
+func CreateCampaign(t testing.TB, db *gorm.DB, budget, vouchers int, photoVerificationRequired bool) domain.Campaign {
+	t.Helper()
+
+	now := time.Now()
+	campaign := domain.Campaign{
+		Name:                      "test campaign",
+		StartDate:                 now.Add(-time.Hour),
+		EndDate:                   now.Add(24 * time.Hour),
+		Budget:                    budget,
+		PerCustomerLimit:          1,
+		PhotoVerificationRequired: photoVerificationRequired,
+		Currency:                  "USD",
+	}
+	if err := db.Create(&campaign).Error; err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	for i := 0; i < vouchers; i++ {
+		voucher := domain.CustomerVoucher{
+			CampaignID:  campaign.ID,
+			VoucherCode: fmt.Sprintf("VOUCHER-%d-%d", campaign.ID, i),
+		}
+		if err := db.Create(&voucher).Error; err != nil {
+			t.Fatalf("create voucher: %v", err)
+		}
+	}
+	return campaign
+}
+
+// CreateCustomers stores count customers and returns their ids
+func CreateCustomers(t testing.TB, db *gorm.DB, count int) []int {
+	t.Helper()
+
+	ids := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		customer := domain.Customer{
+			FirstName:   "Test",
+			LastName:    "Customer",
+			Gender:      "female",
+			DateOfBirth: "1990-01-01",
+			Email:       "customer@example.com",
+		}
+		if err := db.Create(&customer).Error; err != nil {
+			t.Fatalf("create customer: %v", err)
+		}
+		ids = append(ids, customer.ID)
+	}
+	return ids
+}
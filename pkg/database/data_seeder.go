@@ -1,7 +1,68 @@
 package database
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"time"
 
-func SeederData(db *gorm.DB,model interface{},count int)  {
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
+// Seeder named set of records. Run stores the records with tx and draws every random value
+// from random so the seeder generates the same records on every database
+type Seeder struct {
+	Name string
+	Run  func(tx *gorm.DB, random *rand.Rand) error
+}
+
+// SeederRun seeder stored in the database, a seeder is stored once
+type SeederRun struct {
+	Name      string    `gorm:"type:varchar(100);column:name;primarykey"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+// TableName name of table
+func (r SeederRun) TableName() string {
+	return "seeder_runs"
+}
+
+// SeederData run the seeders not stored yet in order, returns the names of the seeders run.
+// Every seeder runs in its own transaction together with its seeder_runs row, an instance seeding
+// at the same time waits for the row and skips the seeder. The random source of a seeder is seeded
+// with randomSeed and the seeder name so its records do not depend on the seeders run before it
+func SeederData(ctx context.Context, db *gorm.DB, randomSeed int64, seeders ...Seeder) ([]string, error) {
+	var result []string
+	for _, seeder := range seeders {
+		seeded := false
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			run := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&SeederRun{
+				Name:      seeder.Name,
+				AppliedAt: time.Now(),
+			})
+			if run.Error != nil {
+				return run.Error
+			}
+			if run.RowsAffected == 0 {
+				return nil
+			}
+
+			seeded = true
+			return seeder.Run(tx, rand.New(rand.NewSource(seederRandomSeed(randomSeed, seeder.Name))))
+		})
+		if err != nil {
+			return result, err
+		}
+		if seeded {
+			result = append(result, seeder.Name)
+		}
+	}
+	return result, nil
+}
+
+func seederRandomSeed(randomSeed int64, name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	return randomSeed ^ int64(hash.Sum64())
 }
@@ -3,6 +3,7 @@ package database
 import (
 	"gorm.io/gorm"
 	"reflect"
+	"time"
 
 	"github.com/imdario/mergo"
 )
@@ -53,7 +54,7 @@ func (f *Factory) Generate(count int) interface{} {
 			slice = reflect.MakeSlice(reflect.SliceOf(t), 0, count)
 		}
 		if f.override != nil {
-			if err := mergo.Merge(record, f.override, mergo.WithOverride); err != nil {
+			if err := mergo.Merge(record, f.override, mergo.WithOverride, mergo.WithTransformers(timeTransformer{})); err != nil {
 				panic(err)
 			}
 		}
@@ -76,3 +77,38 @@ func (f *Factory) Save(db * gorm.DB, count int) interface{} {
 	}
 	return records
 }
+
+// SaveInBatches generate a number of records using the given factory and
+// insert them in the database batchSize records per statement.
+// The returned slice is a slice of the actual type of the generated records,
+// meaning you can type-assert safely.
+//
+//  records, err := factory.SaveInBatches(tx, 1000, 100)
+//  users := records.([]*User)
+func (f *Factory) SaveInBatches(db *gorm.DB, count, batchSize int) (interface{}, error) {
+	records := f.Generate(count)
+	if count <= 0 {
+		return records, nil
+	}
+
+	if err := db.CreateInBatches(records, batchSize).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// timeTransformer merge time.Time as a value, a zero time of the override keeps the generated time
+// instead of merging the unexported fields of time.Time
+type timeTransformer struct{}
+
+func (timeTransformer) Transformer(t reflect.Type) func(dst, src reflect.Value) error {
+	if t != reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+	return func(dst, src reflect.Value) error {
+		if dst.CanSet() && !src.Interface().(time.Time).IsZero() {
+			dst.Set(src)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,16 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockForUpdate selects from the table with a row lock held until the end of the transaction,
+// with FOR UPDATE on mysql and postgres and the UPDLOCK, ROWLOCK table hints on sql server,
+// which does not support the FOR UPDATE clause.
+func LockForUpdate(tx *gorm.DB, table string) *gorm.DB {
+	if tx.Dialector.Name() == "sqlserver" {
+		return tx.Table(table + " WITH (UPDLOCK, ROWLOCK)")
+	}
+	return tx.Table(table).Clauses(clause.Locking{Strength: "UPDATE"})
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"gorm.io/gorm"
+)
+
+type lockedRow struct {
+	ID int
+}
+
+func TestLockForUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		typesConn string
+		want      string
+		notWant   string
+	}{
+		{
+			name:      "postgres locks with for update",
+			typesConn: "postgres",
+			want:      `FROM "locked_rows" WHERE id = 1 ORDER BY "locked_rows"."id" LIMIT 1 FOR UPDATE`,
+			notWant:   "UPDLOCK",
+		},
+		{
+			name:      "sql server locks with table hints",
+			typesConn: "sql",
+			want:      `FROM locked_rows WITH (UPDLOCK, ROWLOCK) WHERE id = 1 ORDER BY "locked_rows"."id" OFFSET 0 ROW FETCH NEXT 1 ROWS ONLY`,
+			notWant:   "FOR UPDATE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, err := helper.NewMockDB(tt.typesConn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var row lockedRow
+				return LockForUpdate(tx, "locked_rows").Where("id = ?", 1).First(&row)
+			})
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("sql = %s, want %s", sql, tt.want)
+			}
+			if strings.Contains(sql, tt.notWant) {
+				t.Errorf("sql = %s, must not contain %s", sql, tt.notWant)
+			}
+		})
+	}
+}
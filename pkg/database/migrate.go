@@ -0,0 +1,428 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// migrationTable applied versions of the schema
+	migrationTable = "schema_migrations"
+	// migrationLockName name of the lock held while migrating, postgres uses migrationLockKey
+	migrationLockName       = "schema_migrations"
+	migrationLockKey  int64 = 7318420965
+
+	DefaultMigrationLockTimeout = 60 * time.Second
+
+	// SqliteDriver migrations of the sqlite databases of the tests, sqlite has no server and no migration lock
+	SqliteDriver = "sqlite"
+)
+
+var (
+	ErrMigrationLocked  = errors.New("migration lock is held by another instance")
+	ErrMigrationUnknown = errors.New("applied migration has no migration file")
+
+	migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+// Migration a versioned schema change, Up and Down are the statements of the database driver
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// MigrationStatus migration with the time it was applied, AppliedAt is nil for a pending migration
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator apply the migrations of files/<driver> in version order. Every command holds a database lock
+// so instances started together do not migrate concurrently, waiting at most LockTimeout for it
+type Migrator struct {
+	LockTimeout time.Duration
+
+	db         *gorm.DB
+	driver     string
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB, files fs.FS) (*Migrator, error) {
+	driver, err := migrationDriver(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := loadMigrations(files, driver)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		LockTimeout: DefaultMigrationLockTimeout,
+		db:          db,
+		driver:      driver,
+		migrations:  migrations,
+	}, nil
+}
+
+// migrationDriver directory of the migrations of the gorm dialector
+func migrationDriver(dialector string) (string, error) {
+	switch dialector {
+	case "mysql":
+		return MysqlDriver, nil
+	case "postgres":
+		return PostgresDriver, nil
+	case "sqlserver":
+		return SqlServerDriver, nil
+	case "sqlite":
+		return SqliteDriver, nil
+	default:
+		return "", fmt.Errorf("unsupported driver database %s", dialector)
+	}
+}
+
+func loadMigrations(files fs.FS, driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(files, path.Join(driver, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = splitStatements(string(content))
+		} else {
+			migration.Down = splitStatements(string(content))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if len(migration.Up) == 0 {
+			return nil, fmt.Errorf("migration %d_%s has no up statement", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements statements of a migration file, a statement ends with a semicolon at the end of a line
+// and lines starting with -- are comments
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(line, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// Up apply the pending migrations, returns the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var result []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Up,
+				fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)",
+					migrationTable, m.placeholder(1), m.placeholder(2), m.placeholder(3)),
+				migration.Version, migration.Name, time.Now()); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			result = append(result, migration)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Down revert the last steps applied migrations, newest first, returns the reverted ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var result []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for i := 0; i < steps && i < len(versions); i++ {
+			migration, ok := m.find(versions[i])
+			if !ok {
+				return fmt.Errorf("%w: version %d", ErrMigrationUnknown, versions[i])
+			}
+			if err := m.apply(ctx, conn, migration.Down,
+				fmt.Sprintf("DELETE FROM %s WHERE version = %s", migrationTable, m.placeholder(1)),
+				migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			result = append(result, migration)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Status every known migration in version order followed by applied versions without a migration file
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var result []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if item, ok := applied[migration.Version]; ok {
+				status.AppliedAt = item.AppliedAt
+				delete(applied, migration.Version)
+			}
+			result = append(result, status)
+		}
+
+		unknown := make([]MigrationStatus, 0, len(applied))
+		for _, item := range applied {
+			unknown = append(unknown, item)
+		}
+		sort.Slice(unknown, func(i, j int) bool {
+			return unknown[i].Version < unknown[j].Version
+		})
+		result = append(result, unknown...)
+		return nil
+	})
+	return result, err
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// apply run the statements and the version bookkeeping in one transaction. Mysql commits ddl
+// implicitly, a failing mysql migration may be left partially applied and is not recorded
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, statements []string, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]MigrationStatus, error) {
+	if _, err := conn.ExecContext(ctx, m.createTableStatement()); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, applied_at FROM %s", migrationTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[int64]MigrationStatus{}
+	for rows.Next() {
+		var status MigrationStatus
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&status.Version, &status.Name, &appliedAt); err != nil {
+			return nil, err
+		}
+		if appliedAt.Valid {
+			status.AppliedAt = &appliedAt.Time
+		}
+		result[status.Version] = status
+	}
+	return result, rows.Err()
+}
+
+func (m *Migrator) createTableStatement() string {
+	switch m.driver {
+	case SqlServerDriver:
+		return fmt.Sprintf("IF OBJECT_ID(N'%s', N'U') IS NULL CREATE TABLE %s (version BIGINT NOT NULL PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIMEOFFSET NOT NULL)",
+			migrationTable, migrationTable)
+	case PostgresDriver:
+		return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)",
+			migrationTable)
+	case SqliteDriver:
+		// the sqlite driver scans a column declared DATETIME, not DATETIME(3), as a time
+		return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)",
+			migrationTable)
+	default:
+		return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME(3) NOT NULL)",
+			migrationTable)
+	}
+}
+
+func (m *Migrator) placeholder(n int) string {
+	switch m.driver {
+	case PostgresDriver:
+		return "$" + strconv.Itoa(n)
+	case SqlServerDriver:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// withLock run fn on a dedicated connection holding the migration lock, session locks belong to
+// the connection so the lock and the migration must share it
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer m.unlock(conn)
+
+	return fn(conn)
+}
+
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) error {
+	timeout := int(m.LockTimeout / time.Second)
+
+	switch m.driver {
+	case SqliteDriver:
+		return nil
+	case PostgresDriver:
+		// pg_advisory_lock has no timeout, poll the try variant until the deadline
+		deadline := time.Now().Add(m.LockTimeout)
+		for {
+			var locked bool
+			if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked); err != nil {
+				return err
+			}
+			if locked {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return ErrMigrationLocked
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	case SqlServerDriver:
+		var result int
+		if err := conn.QueryRowContext(ctx,
+			"DECLARE @result INT; EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2; SELECT @result",
+			migrationLockName, timeout*1000).Scan(&result); err != nil {
+			return err
+		}
+		if result < 0 {
+			return ErrMigrationLocked
+		}
+		return nil
+	default:
+		var result sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, timeout).Scan(&result); err != nil {
+			return err
+		}
+		if !result.Valid || result.Int64 != 1 {
+			return ErrMigrationLocked
+		}
+		return nil
+	}
+}
+
+// unlock release the migration lock, it uses a fresh context so a cancelled command still releases it
+func (m *Migrator) unlock(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch m.driver {
+	case SqliteDriver:
+	case PostgresDriver:
+		_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	case SqlServerDriver:
+		_, _ = conn.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", migrationLockName)
+	default:
+		_, _ = conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName)
+	}
+}
@@ -0,0 +1,12 @@
+// Package migrations versioned schema of the service, one directory per database driver holding
+// NNNN_name.up.sql and NNNN_name.down.sql files. Statements end with a semicolon at the end of a line.
+//
+// 0001 to 0004 are the tables of the first release as AutoMigrate created them, every column, index and table
+// added since has its own migration so a database created by AutoMigrate is upgraded instead of skipped.
+// The sqlite directory builds the databases of the tests
+package migrations
+
+import "embed"
+
+//go:embed mysql/*.sql postgres/*.sql mssql/*.sql sqlite/*.sql
+var FS embed.FS
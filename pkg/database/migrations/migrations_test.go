@@ -0,0 +1,111 @@
+package migrations_test
+
+import (
+	"context"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/radyatamaa/technical-test-aichat/internal/domain"
+	"github.com/radyatamaa/technical-test-aichat/internal/testutil"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database"
+	"github.com/radyatamaa/technical-test-aichat/pkg/database/migrations"
+	"gorm.io/gorm"
+)
+
+var models = []interface{}{
+	&domain.Customer{},
+	&domain.Campaign{},
+	&domain.CampaignRule{},
+	&domain.CustomerVoucher{},
+	&domain.CustomerVoucherBook{},
+	&domain.CustomerVoucherBookEvent{},
+	&domain.CustomerVoucherBookAttempt{},
+	&domain.PurchaseTransaction{},
+	&domain.PurchaseTransactionImport{},
+	&domain.PurchaseTransactionImportError{},
+	&domain.PurchaseTransactionRefund{},
+	&domain.CurrencyRate{},
+	&domain.ApiKey{},
+	&domain.Role{},
+	&domain.RolePermission{},
+	&domain.ApiKeyRole{},
+	&domain.IdempotencyKey{},
+	&database.SeederRun{},
+}
+
+func TestEveryDriverHasTheSameMigrations(t *testing.T) {
+	names := func(driver string) []string {
+		entries, err := fs.ReadDir(migrations.FS, driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Name())
+		}
+		return result
+	}
+
+	want := names(database.MysqlDriver)
+	for _, driver := range []string{database.PostgresDriver, database.SqlServerDriver, database.SqliteDriver} {
+		if got := names(driver); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s migrations = %v, want %v", driver, got, want)
+		}
+	}
+}
+
+func TestMigrationsMatchTheModels(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatal(err)
+		}
+		table := stmt.Schema.Table
+		if !db.Migrator().HasTable(model) {
+			t.Errorf("table %s is missing", table)
+			continue
+		}
+		for _, column := range stmt.Schema.DBNames {
+			if !db.Migrator().HasColumn(model, column) {
+				t.Errorf("column %s.%s is missing", table, column)
+			}
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if !db.Migrator().HasIndex(model, index.Name) {
+				t.Errorf("index %s of %s is missing", index.Name, table)
+			}
+		}
+	}
+}
+
+func TestDownRevertsEveryMigration(t *testing.T) {
+	db := testutil.NewSQLiteDB(t)
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := migrator.Down(context.Background(), len(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(status) {
+		t.Fatalf("reverted %d migrations, want %d", len(reverted), len(status))
+	}
+	for _, model := range models {
+		if db.Migrator().HasTable(model) {
+			t.Errorf("table of %T is left after down", model)
+		}
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("up after down: %v", err)
+	}
+}
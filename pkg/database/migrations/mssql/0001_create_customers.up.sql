@@ -0,0 +1,14 @@
+-- customers of the first release, later columns are added by their own migration
+
+IF OBJECT_ID(N'customers', N'U') IS NULL CREATE TABLE customers (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    first_name NVARCHAR(255) NULL,
+    last_name NVARCHAR(255) NULL,
+    gender NVARCHAR(50) NULL,
+    date_of_birth DATE NULL,
+    contact_number NVARCHAR(50) NULL,
+    email NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_customers PRIMARY KEY (id)
+);
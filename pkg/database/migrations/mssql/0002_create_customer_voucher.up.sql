@@ -0,0 +1,12 @@
+-- vouchers of the first release, later columns are added by their own migration
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'customer_voucher', N'U') IS NULL CREATE TABLE customer_voucher (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    customer_id BIGINT NULL,
+    voucher_code NVARCHAR(255) NULL,
+    is_redeem BIT DEFAULT 0 NULL,
+    CONSTRAINT pk_customer_voucher PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
@@ -0,0 +1,13 @@
+-- voucher bookings of the first release, later columns are added by their own migration
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'customer_voucher_books', N'U') IS NULL CREATE TABLE customer_voucher_books (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    customer_id BIGINT NULL,
+    customer_voucher_id BIGINT NULL,
+    expired_date DATETIMEOFFSET NULL,
+    CONSTRAINT pk_customer_voucher_books PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_books_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    CONSTRAINT fk_customer_voucher_books_customer_voucher_id FOREIGN KEY (customer_voucher_id) REFERENCES customer_voucher (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
@@ -0,0 +1,13 @@
+-- purchase transactions of the first release, later columns are added by their own migration
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'purchase_transactions', N'U') IS NULL CREATE TABLE purchase_transactions (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    customer_id BIGINT NULL,
+    total_spent DECIMAL(10,2) NULL,
+    total_saving DECIMAL(10,2) NULL,
+    transaction_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_purchase_transactions PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transactions_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
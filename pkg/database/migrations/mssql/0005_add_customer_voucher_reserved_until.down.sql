@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_customer_voucher_customer_id ON customer_voucher;
+DROP INDEX IF EXISTS idx_customer_voucher_reserved_until ON customer_voucher;
+IF COL_LENGTH(N'customer_voucher', N'reserved_until') IS NOT NULL ALTER TABLE customer_voucher DROP COLUMN reserved_until;
@@ -0,0 +1,7 @@
+-- vouchers reserved by a booking until it is confirmed or released
+
+IF COL_LENGTH(N'customer_voucher', N'reserved_until') IS NULL ALTER TABLE customer_voucher ADD reserved_until DATETIMEOFFSET NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_reserved_until') CREATE INDEX idx_customer_voucher_reserved_until ON customer_voucher (reserved_until);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_customer_id') CREATE INDEX idx_customer_voucher_customer_id ON customer_voucher (customer_id);
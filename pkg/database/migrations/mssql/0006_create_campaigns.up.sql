@@ -0,0 +1,16 @@
+-- voucher campaigns
+
+IF OBJECT_ID(N'campaigns', N'U') IS NULL CREATE TABLE campaigns (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    name NVARCHAR(255) NULL,
+    start_date DATETIMEOFFSET NULL,
+    end_date DATETIMEOFFSET NULL,
+    budget BIGINT NULL,
+    per_customer_limit BIGINT NULL,
+    photo_verification_required BIT DEFAULT 0 NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    deleted_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_campaigns PRIMARY KEY (id),
+    INDEX idx_campaigns_deleted_at (deleted_at)
+);
@@ -0,0 +1,6 @@
+IF OBJECT_ID(N'fk_customer_voucher_books_campaign_id', N'F') IS NOT NULL ALTER TABLE customer_voucher_books DROP CONSTRAINT fk_customer_voucher_books_campaign_id;
+DROP INDEX IF EXISTS idx_customer_voucher_books_campaign_id ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'campaign_id') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN campaign_id;
+IF OBJECT_ID(N'fk_customer_voucher_campaign_id', N'F') IS NOT NULL ALTER TABLE customer_voucher DROP CONSTRAINT fk_customer_voucher_campaign_id;
+DROP INDEX IF EXISTS idx_customer_voucher_campaign_id ON customer_voucher;
+IF COL_LENGTH(N'customer_voucher', N'campaign_id') IS NOT NULL ALTER TABLE customer_voucher DROP COLUMN campaign_id;
@@ -0,0 +1,15 @@
+-- campaign of the vouchers and the bookings
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF COL_LENGTH(N'customer_voucher', N'campaign_id') IS NULL ALTER TABLE customer_voucher ADD campaign_id BIGINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_campaign_id') CREATE INDEX idx_customer_voucher_campaign_id ON customer_voucher (campaign_id);
+
+IF OBJECT_ID(N'fk_customer_voucher_campaign_id', N'F') IS NULL ALTER TABLE customer_voucher ADD CONSTRAINT fk_customer_voucher_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE NO ACTION ON DELETE NO ACTION;
+
+IF COL_LENGTH(N'customer_voucher_books', N'campaign_id') IS NULL ALTER TABLE customer_voucher_books ADD campaign_id BIGINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_campaign_id') CREATE INDEX idx_customer_voucher_books_campaign_id ON customer_voucher_books (campaign_id);
+
+IF OBJECT_ID(N'fk_customer_voucher_books_campaign_id', N'F') IS NULL ALTER TABLE customer_voucher_books ADD CONSTRAINT fk_customer_voucher_books_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE NO ACTION ON DELETE NO ACTION;
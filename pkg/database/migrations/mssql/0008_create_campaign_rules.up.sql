@@ -0,0 +1,16 @@
+-- eligibility rules of the campaigns
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'campaign_rules', N'U') IS NULL CREATE TABLE campaign_rules (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    campaign_id BIGINT NULL,
+    rule_type NVARCHAR(50) NULL,
+    threshold DECIMAL(12,2) NULL,
+    window_days BIGINT NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_campaign_rules PRIMARY KEY (id),
+    CONSTRAINT fk_campaign_rules_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    INDEX idx_campaign_rules_campaign_id (campaign_id)
+);
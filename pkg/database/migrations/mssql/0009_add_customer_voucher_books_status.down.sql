@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_customer_voucher_books_customer_voucher_id ON customer_voucher_books;
+DROP INDEX IF EXISTS idx_customer_voucher_books_customer_id ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'updated_at') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN updated_at;
+IF COL_LENGTH(N'customer_voucher_books', N'created_at') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN created_at;
+DROP INDEX IF EXISTS idx_customer_voucher_books_status ON customer_voucher_books;
+IF OBJECT_ID(N'df_customer_voucher_books_status', N'D') IS NOT NULL ALTER TABLE customer_voucher_books DROP CONSTRAINT df_customer_voucher_books_status;
+IF COL_LENGTH(N'customer_voucher_books', N'status') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN status;
@@ -0,0 +1,13 @@
+-- lifecycle status of the bookings, existing bookings are booked
+
+IF COL_LENGTH(N'customer_voucher_books', N'status') IS NULL ALTER TABLE customer_voucher_books ADD status NVARCHAR(20) CONSTRAINT df_customer_voucher_books_status DEFAULT 'booked' NULL WITH VALUES;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_status') CREATE INDEX idx_customer_voucher_books_status ON customer_voucher_books (status);
+
+IF COL_LENGTH(N'customer_voucher_books', N'created_at') IS NULL ALTER TABLE customer_voucher_books ADD created_at DATETIMEOFFSET NULL;
+
+IF COL_LENGTH(N'customer_voucher_books', N'updated_at') IS NULL ALTER TABLE customer_voucher_books ADD updated_at DATETIMEOFFSET NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_customer_id') CREATE INDEX idx_customer_voucher_books_customer_id ON customer_voucher_books (customer_id);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_customer_voucher_id') CREATE INDEX idx_customer_voucher_books_customer_voucher_id ON customer_voucher_books (customer_voucher_id);
@@ -0,0 +1,17 @@
+-- status history of the bookings
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'customer_voucher_book_events', N'U') IS NULL CREATE TABLE customer_voucher_book_events (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    customer_voucher_book_id BIGINT NULL,
+    customer_id BIGINT NULL,
+    from_status NVARCHAR(20) NULL,
+    to_status NVARCHAR(20) NULL,
+    reason NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_customer_voucher_book_events PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_book_events_customer_voucher_book_id FOREIGN KEY (customer_voucher_book_id) REFERENCES customer_voucher_books (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    INDEX idx_customer_voucher_book_events_customer_voucher_book_id (customer_voucher_book_id),
+    INDEX idx_customer_voucher_book_events_customer_id (customer_id)
+);
@@ -0,0 +1,2 @@
+IF COL_LENGTH(N'customer_voucher_books', N'photo_path') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_path;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_hash') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_hash;
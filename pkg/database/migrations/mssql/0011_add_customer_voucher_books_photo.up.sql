@@ -0,0 +1,5 @@
+-- stored verification photo of the bookings
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_hash') IS NULL ALTER TABLE customer_voucher_books ADD photo_hash NVARCHAR(64) NULL;
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_path') IS NULL ALTER TABLE customer_voucher_books ADD photo_path NVARCHAR(255) NULL;
@@ -0,0 +1,5 @@
+IF COL_LENGTH(N'customer_voucher_books', N'flag_reason') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN flag_reason;
+IF OBJECT_ID(N'df_customer_voucher_books_flagged', N'D') IS NOT NULL ALTER TABLE customer_voucher_books DROP CONSTRAINT df_customer_voucher_books_flagged;
+IF COL_LENGTH(N'customer_voucher_books', N'flagged') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN flagged;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash;
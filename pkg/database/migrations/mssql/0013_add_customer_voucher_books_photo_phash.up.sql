@@ -0,0 +1,9 @@
+-- perceptual hash of the verification photos and bookings flagged as duplicates
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash BIGINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash') CREATE INDEX idx_customer_voucher_books_photo_phash ON customer_voucher_books (photo_phash);
+
+IF COL_LENGTH(N'customer_voucher_books', N'flagged') IS NULL ALTER TABLE customer_voucher_books ADD flagged BIT CONSTRAINT df_customer_voucher_books_flagged DEFAULT 0 NULL WITH VALUES;
+
+IF COL_LENGTH(N'customer_voucher_books', N'flag_reason') IS NULL ALTER TABLE customer_voucher_books ADD flag_reason NVARCHAR(255) NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_customers_deleted_at ON customers;
+IF COL_LENGTH(N'customers', N'deleted_at') IS NOT NULL ALTER TABLE customers DROP COLUMN deleted_at;
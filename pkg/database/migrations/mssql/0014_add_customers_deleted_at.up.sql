@@ -0,0 +1,5 @@
+-- soft deleted customers
+
+IF COL_LENGTH(N'customers', N'deleted_at') IS NULL ALTER TABLE customers ADD deleted_at DATETIMEOFFSET NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customers_deleted_at') CREATE INDEX idx_customers_deleted_at ON customers (deleted_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_purchase_transactions_customer_id ON purchase_transactions;
+IF COL_LENGTH(N'purchase_transactions', N'created_at') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN created_at;
+DROP INDEX IF EXISTS idx_purchase_transactions_external_reference ON purchase_transactions;
+IF COL_LENGTH(N'purchase_transactions', N'external_reference') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN external_reference;
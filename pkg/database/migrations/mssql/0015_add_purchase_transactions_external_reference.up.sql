@@ -0,0 +1,9 @@
+-- reference of the ingested purchase transactions in the source system
+
+IF COL_LENGTH(N'purchase_transactions', N'external_reference') IS NULL ALTER TABLE purchase_transactions ADD external_reference NVARCHAR(100) NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_purchase_transactions_external_reference') CREATE UNIQUE INDEX idx_purchase_transactions_external_reference ON purchase_transactions (external_reference) WHERE external_reference IS NOT NULL;
+
+IF COL_LENGTH(N'purchase_transactions', N'created_at') IS NULL ALTER TABLE purchase_transactions ADD created_at DATETIMEOFFSET NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_purchase_transactions_customer_id') CREATE INDEX idx_purchase_transactions_customer_id ON purchase_transactions (customer_id);
@@ -0,0 +1,32 @@
+-- csv imports of purchase transactions and their failed rows
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'purchase_transaction_imports', N'U') IS NULL CREATE TABLE purchase_transaction_imports (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    checksum NVARCHAR(64) NULL,
+    file_name NVARCHAR(255) NULL,
+    status NVARCHAR(20) DEFAULT 'pending' NULL,
+    last_row BIGINT NULL,
+    imported_rows BIGINT NULL,
+    duplicate_rows BIGINT NULL,
+    failed_rows BIGINT NULL,
+    message NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_purchase_transaction_imports PRIMARY KEY (id)
+);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_purchase_transaction_imports_checksum') CREATE UNIQUE INDEX idx_purchase_transaction_imports_checksum ON purchase_transaction_imports (checksum);
+
+IF OBJECT_ID(N'purchase_transaction_import_errors', N'U') IS NULL CREATE TABLE purchase_transaction_import_errors (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    purchase_transaction_import_id BIGINT NULL,
+    [row] BIGINT NULL,
+    external_reference NVARCHAR(100) NULL,
+    message NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_purchase_transaction_import_errors PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_import_errors_purchase_transaction_import_id FOREIGN KEY (purchase_transaction_import_id) REFERENCES purchase_transaction_imports (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    INDEX idx_purchase_transaction_import_errors_purchase_transaction_import_id (purchase_transaction_import_id)
+);
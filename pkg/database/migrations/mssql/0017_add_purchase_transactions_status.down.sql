@@ -0,0 +1,6 @@
+IF COL_LENGTH(N'purchase_transactions', N'updated_at') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN updated_at;
+IF OBJECT_ID(N'df_purchase_transactions_refunded_amount', N'D') IS NOT NULL ALTER TABLE purchase_transactions DROP CONSTRAINT df_purchase_transactions_refunded_amount;
+IF COL_LENGTH(N'purchase_transactions', N'refunded_amount') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN refunded_amount;
+DROP INDEX IF EXISTS idx_purchase_transactions_status ON purchase_transactions;
+IF OBJECT_ID(N'df_purchase_transactions_status', N'D') IS NOT NULL ALTER TABLE purchase_transactions DROP CONSTRAINT df_purchase_transactions_status;
+IF COL_LENGTH(N'purchase_transactions', N'status') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN status;
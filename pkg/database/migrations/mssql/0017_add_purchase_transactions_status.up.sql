@@ -0,0 +1,9 @@
+-- refunded and voided purchase transactions, existing transactions are completed
+
+IF COL_LENGTH(N'purchase_transactions', N'status') IS NULL ALTER TABLE purchase_transactions ADD status NVARCHAR(20) CONSTRAINT df_purchase_transactions_status DEFAULT 'completed' NULL WITH VALUES;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_purchase_transactions_status') CREATE INDEX idx_purchase_transactions_status ON purchase_transactions (status);
+
+IF COL_LENGTH(N'purchase_transactions', N'refunded_amount') IS NULL ALTER TABLE purchase_transactions ADD refunded_amount DECIMAL(10,2) CONSTRAINT df_purchase_transactions_refunded_amount DEFAULT 0 NULL WITH VALUES;
+
+IF COL_LENGTH(N'purchase_transactions', N'updated_at') IS NULL ALTER TABLE purchase_transactions ADD updated_at DATETIMEOFFSET NULL;
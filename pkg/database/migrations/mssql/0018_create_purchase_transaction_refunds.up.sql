@@ -0,0 +1,14 @@
+-- refunds of the purchase transactions
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'purchase_transaction_refunds', N'U') IS NULL CREATE TABLE purchase_transaction_refunds (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    purchase_transaction_id BIGINT NULL,
+    amount DECIMAL(10,2) NULL,
+    reason NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_purchase_transaction_refunds PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_refunds_purchase_transaction_id FOREIGN KEY (purchase_transaction_id) REFERENCES purchase_transactions (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    INDEX idx_purchase_transaction_refunds_purchase_transaction_id (purchase_transaction_id)
+);
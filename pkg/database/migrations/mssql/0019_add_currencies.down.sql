@@ -0,0 +1,5 @@
+IF OBJECT_ID(N'df_purchase_transactions_currency', N'D') IS NOT NULL ALTER TABLE purchase_transactions DROP CONSTRAINT df_purchase_transactions_currency;
+IF COL_LENGTH(N'purchase_transactions', N'currency') IS NOT NULL ALTER TABLE purchase_transactions DROP COLUMN currency;
+IF COL_LENGTH(N'campaign_rules', N'currency') IS NOT NULL ALTER TABLE campaign_rules DROP COLUMN currency;
+IF OBJECT_ID(N'df_campaigns_currency', N'D') IS NOT NULL ALTER TABLE campaigns DROP CONSTRAINT df_campaigns_currency;
+IF COL_LENGTH(N'campaigns', N'currency') IS NOT NULL ALTER TABLE campaigns DROP COLUMN currency;
@@ -0,0 +1,7 @@
+-- currency of the campaigns, the rule thresholds and the purchase transactions, existing rows are in USD
+
+IF COL_LENGTH(N'campaigns', N'currency') IS NULL ALTER TABLE campaigns ADD currency NVARCHAR(3) CONSTRAINT df_campaigns_currency DEFAULT 'USD' NULL WITH VALUES;
+
+IF COL_LENGTH(N'campaign_rules', N'currency') IS NULL ALTER TABLE campaign_rules ADD currency NVARCHAR(3) NULL;
+
+IF COL_LENGTH(N'purchase_transactions', N'currency') IS NULL ALTER TABLE purchase_transactions ADD currency NVARCHAR(3) CONSTRAINT df_purchase_transactions_currency DEFAULT 'USD' NULL WITH VALUES;
@@ -0,0 +1,13 @@
+-- exchange rates of currency pairs
+
+IF OBJECT_ID(N'currency_rates', N'U') IS NULL CREATE TABLE currency_rates (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    base_currency NVARCHAR(3) NULL,
+    quote_currency NVARCHAR(3) NULL,
+    rate DECIMAL(18,8) NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_currency_rates PRIMARY KEY (id)
+);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_currency_rates_pair') CREATE UNIQUE INDEX idx_currency_rates_pair ON currency_rates (base_currency, quote_currency);
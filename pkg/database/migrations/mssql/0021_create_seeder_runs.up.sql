@@ -0,0 +1,7 @@
+-- seeders already stored in the database
+
+IF OBJECT_ID(N'seeder_runs', N'U') IS NULL CREATE TABLE seeder_runs (
+    name NVARCHAR(100) NOT NULL,
+    applied_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_seeder_runs PRIMARY KEY (name)
+);
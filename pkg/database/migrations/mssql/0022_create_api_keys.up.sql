@@ -0,0 +1,17 @@
+-- hashed api keys of the partner integrations
+
+IF OBJECT_ID(N'api_keys', N'U') IS NULL CREATE TABLE api_keys (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    owner NVARCHAR(255) NULL,
+    prefix NVARCHAR(16) NULL,
+    key_hash NVARCHAR(64) NULL,
+    scopes NVARCHAR(1000) NULL,
+    expires_at DATETIMEOFFSET NULL,
+    revoked_at DATETIMEOFFSET NULL,
+    last_used_at DATETIMEOFFSET NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_api_keys PRIMARY KEY (id)
+);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_api_keys_key_hash') CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,30 @@
+-- roles granting permissions to the api keys
+
+-- sql server rejects multiple cascade paths, rows are deleted by the application
+
+IF OBJECT_ID(N'roles', N'U') IS NULL CREATE TABLE roles (
+    id BIGINT IDENTITY(1,1) NOT NULL,
+    name NVARCHAR(100) NULL,
+    description NVARCHAR(255) NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_roles PRIMARY KEY (id)
+);
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_roles_name') CREATE UNIQUE INDEX idx_roles_name ON roles (name);
+
+IF OBJECT_ID(N'role_permissions', N'U') IS NULL CREATE TABLE role_permissions (
+    role_id BIGINT NOT NULL,
+    permission NVARCHAR(100) NOT NULL,
+    CONSTRAINT pk_role_permissions PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+IF OBJECT_ID(N'api_key_roles', N'U') IS NULL CREATE TABLE api_key_roles (
+    api_key_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    CONSTRAINT pk_api_key_roles PRIMARY KEY (api_key_id, role_id),
+    CONSTRAINT fk_api_key_roles_api_key_id FOREIGN KEY (api_key_id) REFERENCES api_keys (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    CONSTRAINT fk_api_key_roles_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    INDEX idx_api_key_roles_role_id (role_id)
+);
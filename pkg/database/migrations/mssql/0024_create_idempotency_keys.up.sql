@@ -0,0 +1,14 @@
+-- responses replayed to the retries of requests sent with an Idempotency-Key header
+
+IF OBJECT_ID(N'idempotency_keys', N'U') IS NULL CREATE TABLE idempotency_keys (
+    key_hash NVARCHAR(64) NOT NULL,
+    fingerprint NVARCHAR(64) NULL,
+    status_code BIGINT NULL,
+    content_type NVARCHAR(255) NULL,
+    body VARBINARY(MAX) NULL,
+    expires_at DATETIMEOFFSET NULL,
+    created_at DATETIMEOFFSET NULL,
+    updated_at DATETIMEOFFSET NULL,
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY (key_hash),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
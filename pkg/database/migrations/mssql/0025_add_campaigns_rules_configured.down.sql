@@ -0,0 +1,2 @@
+IF OBJECT_ID(N'df_campaigns_rules_configured', N'D') IS NOT NULL ALTER TABLE campaigns DROP CONSTRAINT df_campaigns_rules_configured;
+IF COL_LENGTH(N'campaigns', N'rules_configured') IS NOT NULL ALTER TABLE campaigns DROP COLUMN rules_configured;
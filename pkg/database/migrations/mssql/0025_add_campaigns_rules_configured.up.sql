@@ -0,0 +1,5 @@
+-- campaigns saved with an empty rule set have no rules, campaigns never configured use the default rules
+
+IF COL_LENGTH(N'campaigns', N'rules_configured') IS NULL ALTER TABLE campaigns ADD rules_configured BIT CONSTRAINT df_campaigns_rules_configured DEFAULT 0 NULL WITH VALUES;
+
+UPDATE campaigns SET rules_configured = 1 WHERE id IN (SELECT campaign_id FROM campaign_rules);
@@ -0,0 +1 @@
+IF COL_LENGTH(N'customer_voucher_book_attempts', N'photo_path') IS NOT NULL ALTER TABLE customer_voucher_book_attempts DROP COLUMN photo_path;
@@ -0,0 +1,3 @@
+-- storage key of the photo of every attempt, rejected photos are kept as evidence of disputed redemptions
+
+IF COL_LENGTH(N'customer_voucher_book_attempts', N'photo_path') IS NULL ALTER TABLE customer_voucher_book_attempts ADD photo_path NVARCHAR(255) NULL;
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band7 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band7') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band7;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band6 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band6') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band6;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band5 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band5') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band5;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band4 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band4') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band4;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band3 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band3') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band3;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band2 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band2') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band2;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band1 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band1') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band1;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band0 ON customer_voucher_books;
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band0') IS NOT NULL ALTER TABLE customer_voucher_books DROP COLUMN photo_phash_band0;
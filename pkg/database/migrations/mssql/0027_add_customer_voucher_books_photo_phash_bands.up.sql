@@ -0,0 +1,44 @@
+-- bytes of the perceptual hash of the photos, hashes within 7 bits share a byte so the duplicate check reads the bookings sharing one
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band0') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band0 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band0') CREATE INDEX idx_customer_voucher_books_photo_phash_band0 ON customer_voucher_books (photo_phash_band0);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band1') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band1 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band1') CREATE INDEX idx_customer_voucher_books_photo_phash_band1 ON customer_voucher_books (photo_phash_band1);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band2') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band2 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band2') CREATE INDEX idx_customer_voucher_books_photo_phash_band2 ON customer_voucher_books (photo_phash_band2);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band3') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band3 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band3') CREATE INDEX idx_customer_voucher_books_photo_phash_band3 ON customer_voucher_books (photo_phash_band3);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band4') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band4 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band4') CREATE INDEX idx_customer_voucher_books_photo_phash_band4 ON customer_voucher_books (photo_phash_band4);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band5') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band5 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band5') CREATE INDEX idx_customer_voucher_books_photo_phash_band5 ON customer_voucher_books (photo_phash_band5);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band6') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band6 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band6') CREATE INDEX idx_customer_voucher_books_photo_phash_band6 ON customer_voucher_books (photo_phash_band6);
+
+IF COL_LENGTH(N'customer_voucher_books', N'photo_phash_band7') IS NULL ALTER TABLE customer_voucher_books ADD photo_phash_band7 SMALLINT NULL;
+
+IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'idx_customer_voucher_books_photo_phash_band7') CREATE INDEX idx_customer_voucher_books_photo_phash_band7 ON customer_voucher_books (photo_phash_band7);
+
+UPDATE customer_voucher_books SET
+    photo_phash_band0 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 1, 1) AS SMALLINT),
+    photo_phash_band1 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 2, 1) AS SMALLINT),
+    photo_phash_band2 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 3, 1) AS SMALLINT),
+    photo_phash_band3 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 4, 1) AS SMALLINT),
+    photo_phash_band4 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 5, 1) AS SMALLINT),
+    photo_phash_band5 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 6, 1) AS SMALLINT),
+    photo_phash_band6 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 7, 1) AS SMALLINT),
+    photo_phash_band7 = CAST(SUBSTRING(CAST(photo_phash AS BINARY(8)), 8, 1) AS SMALLINT)
+WHERE photo_phash IS NOT NULL;
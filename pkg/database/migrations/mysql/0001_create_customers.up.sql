@@ -0,0 +1,14 @@
+-- customers of the first release, later columns are added by their own migration
+
+CREATE TABLE IF NOT EXISTS customers (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    first_name VARCHAR(255) NULL,
+    last_name VARCHAR(255) NULL,
+    gender VARCHAR(50) NULL,
+    date_of_birth DATE NULL,
+    contact_number VARCHAR(50) NULL,
+    email VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_customers PRIMARY KEY (id)
+);
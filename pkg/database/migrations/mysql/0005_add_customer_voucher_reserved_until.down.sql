@@ -0,0 +1,3 @@
+DROP INDEX idx_customer_voucher_customer_id ON customer_voucher;
+DROP INDEX idx_customer_voucher_reserved_until ON customer_voucher;
+ALTER TABLE customer_voucher DROP COLUMN reserved_until;
@@ -0,0 +1,16 @@
+-- voucher campaigns
+
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NULL,
+    start_date DATETIME(3) NULL,
+    end_date DATETIME(3) NULL,
+    budget BIGINT NULL,
+    per_customer_limit BIGINT NULL,
+    photo_verification_required BOOLEAN DEFAULT FALSE NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    CONSTRAINT pk_campaigns PRIMARY KEY (id),
+    INDEX idx_campaigns_deleted_at (deleted_at)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE customer_voucher_books DROP FOREIGN KEY fk_customer_voucher_books_campaign_id;
+DROP INDEX idx_customer_voucher_books_campaign_id ON customer_voucher_books;
+ALTER TABLE customer_voucher_books DROP COLUMN campaign_id;
+ALTER TABLE customer_voucher DROP FOREIGN KEY fk_customer_voucher_campaign_id;
+DROP INDEX idx_customer_voucher_campaign_id ON customer_voucher;
+ALTER TABLE customer_voucher DROP COLUMN campaign_id;
@@ -0,0 +1,13 @@
+-- campaign of the vouchers and the bookings
+
+ALTER TABLE customer_voucher ADD COLUMN campaign_id BIGINT NULL;
+
+CREATE INDEX idx_customer_voucher_campaign_id ON customer_voucher (campaign_id);
+
+ALTER TABLE customer_voucher ADD CONSTRAINT fk_customer_voucher_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE customer_voucher_books ADD COLUMN campaign_id BIGINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_campaign_id ON customer_voucher_books (campaign_id);
+
+ALTER TABLE customer_voucher_books ADD CONSTRAINT fk_customer_voucher_books_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+-- eligibility rules of the campaigns
+
+CREATE TABLE IF NOT EXISTS campaign_rules (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    campaign_id BIGINT NULL,
+    rule_type VARCHAR(50) NULL,
+    threshold DECIMAL(12,2) NULL,
+    window_days BIGINT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_campaign_rules PRIMARY KEY (id),
+    CONSTRAINT fk_campaign_rules_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_campaign_rules_campaign_id (campaign_id)
+);
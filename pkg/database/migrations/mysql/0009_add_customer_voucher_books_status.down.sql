@@ -0,0 +1,6 @@
+DROP INDEX idx_customer_voucher_books_customer_voucher_id ON customer_voucher_books;
+DROP INDEX idx_customer_voucher_books_customer_id ON customer_voucher_books;
+ALTER TABLE customer_voucher_books DROP COLUMN updated_at;
+ALTER TABLE customer_voucher_books DROP COLUMN created_at;
+DROP INDEX idx_customer_voucher_books_status ON customer_voucher_books;
+ALTER TABLE customer_voucher_books DROP COLUMN status;
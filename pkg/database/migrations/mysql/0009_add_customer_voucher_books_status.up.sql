@@ -0,0 +1,13 @@
+-- lifecycle status of the bookings, existing bookings are booked
+
+ALTER TABLE customer_voucher_books ADD COLUMN status VARCHAR(20) DEFAULT 'booked' NULL;
+
+CREATE INDEX idx_customer_voucher_books_status ON customer_voucher_books (status);
+
+ALTER TABLE customer_voucher_books ADD COLUMN created_at DATETIME(3) NULL;
+
+ALTER TABLE customer_voucher_books ADD COLUMN updated_at DATETIME(3) NULL;
+
+CREATE INDEX idx_customer_voucher_books_customer_id ON customer_voucher_books (customer_id);
+
+CREATE INDEX idx_customer_voucher_books_customer_voucher_id ON customer_voucher_books (customer_voucher_id);
@@ -0,0 +1,2 @@
+ALTER TABLE customer_voucher_books DROP COLUMN photo_path;
+ALTER TABLE customer_voucher_books DROP COLUMN photo_hash;
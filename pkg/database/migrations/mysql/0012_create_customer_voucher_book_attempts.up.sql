@@ -0,0 +1,15 @@
+-- photo verification attempts of the bookings
+
+CREATE TABLE IF NOT EXISTS customer_voucher_book_attempts (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    customer_voucher_book_id BIGINT NULL,
+    customer_id BIGINT NULL,
+    outcome VARCHAR(20) NULL,
+    reason_code VARCHAR(50) NULL,
+    photo_hash VARCHAR(64) NULL,
+    created_at DATETIME(3) NULL,
+    CONSTRAINT pk_customer_voucher_book_attempts PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_book_attempts_customer_voucher_book_id FOREIGN KEY (customer_voucher_book_id) REFERENCES customer_voucher_books (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_customer_voucher_book_attempts_customer_voucher_book_id (customer_voucher_book_id),
+    INDEX idx_customer_voucher_book_attempts_customer_id (customer_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE customer_voucher_books DROP COLUMN flag_reason;
+ALTER TABLE customer_voucher_books DROP COLUMN flagged;
+DROP INDEX idx_customer_voucher_books_photo_phash ON customer_voucher_books;
+ALTER TABLE customer_voucher_books DROP COLUMN photo_phash;
@@ -0,0 +1,2 @@
+DROP INDEX idx_customers_deleted_at ON customers;
+ALTER TABLE customers DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+-- soft deleted customers
+
+ALTER TABLE customers ADD COLUMN deleted_at DATETIME(3) NULL;
+
+CREATE INDEX idx_customers_deleted_at ON customers (deleted_at);
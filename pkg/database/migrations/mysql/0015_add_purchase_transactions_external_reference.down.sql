@@ -0,0 +1,4 @@
+DROP INDEX idx_purchase_transactions_customer_id ON purchase_transactions;
+ALTER TABLE purchase_transactions DROP COLUMN created_at;
+DROP INDEX idx_purchase_transactions_external_reference ON purchase_transactions;
+ALTER TABLE purchase_transactions DROP COLUMN external_reference;
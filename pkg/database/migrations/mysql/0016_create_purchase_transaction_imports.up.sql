@@ -0,0 +1,29 @@
+-- csv imports of purchase transactions and their failed rows
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_imports (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    checksum VARCHAR(64) NULL,
+    file_name VARCHAR(255) NULL,
+    status VARCHAR(20) DEFAULT 'pending' NULL,
+    last_row BIGINT NULL,
+    imported_rows BIGINT NULL,
+    duplicate_rows BIGINT NULL,
+    failed_rows BIGINT NULL,
+    message VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_purchase_transaction_imports PRIMARY KEY (id),
+    UNIQUE INDEX idx_purchase_transaction_imports_checksum (checksum)
+);
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_import_errors (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    purchase_transaction_import_id BIGINT NULL,
+    `row` BIGINT NULL,
+    external_reference VARCHAR(100) NULL,
+    message VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    CONSTRAINT pk_purchase_transaction_import_errors PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_import_errors_purchase_transaction_import_id FOREIGN KEY (purchase_transaction_import_id) REFERENCES purchase_transaction_imports (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_purchase_transaction_import_errors_purchase_transaction_import_id (purchase_transaction_import_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE purchase_transactions DROP COLUMN updated_at;
+ALTER TABLE purchase_transactions DROP COLUMN refunded_amount;
+DROP INDEX idx_purchase_transactions_status ON purchase_transactions;
+ALTER TABLE purchase_transactions DROP COLUMN status;
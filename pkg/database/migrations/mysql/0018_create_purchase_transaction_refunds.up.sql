@@ -0,0 +1,12 @@
+-- refunds of the purchase transactions
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_refunds (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    purchase_transaction_id BIGINT NULL,
+    amount DECIMAL(10,2) NULL,
+    reason VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    CONSTRAINT pk_purchase_transaction_refunds PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_refunds_purchase_transaction_id FOREIGN KEY (purchase_transaction_id) REFERENCES purchase_transactions (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_purchase_transaction_refunds_purchase_transaction_id (purchase_transaction_id)
+);
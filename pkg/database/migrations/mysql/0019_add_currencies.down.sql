@@ -0,0 +1,3 @@
+ALTER TABLE purchase_transactions DROP COLUMN currency;
+ALTER TABLE campaign_rules DROP COLUMN currency;
+ALTER TABLE campaigns DROP COLUMN currency;
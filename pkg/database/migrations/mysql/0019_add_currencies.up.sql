@@ -0,0 +1,7 @@
+-- currency of the campaigns, the rule thresholds and the purchase transactions, existing rows are in USD
+
+ALTER TABLE campaigns ADD COLUMN currency VARCHAR(3) DEFAULT 'USD' NULL;
+
+ALTER TABLE campaign_rules ADD COLUMN currency VARCHAR(3) NULL;
+
+ALTER TABLE purchase_transactions ADD COLUMN currency VARCHAR(3) DEFAULT 'USD' NULL;
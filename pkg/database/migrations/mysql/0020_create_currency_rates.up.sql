@@ -0,0 +1,12 @@
+-- exchange rates of currency pairs
+
+CREATE TABLE IF NOT EXISTS currency_rates (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    base_currency VARCHAR(3) NULL,
+    quote_currency VARCHAR(3) NULL,
+    rate DECIMAL(18,8) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_currency_rates PRIMARY KEY (id),
+    UNIQUE INDEX idx_currency_rates_pair (base_currency, quote_currency)
+);
@@ -0,0 +1,16 @@
+-- hashed api keys of the partner integrations
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    owner VARCHAR(255) NULL,
+    prefix VARCHAR(16) NULL,
+    key_hash VARCHAR(64) NULL,
+    scopes VARCHAR(1000) NULL,
+    expires_at DATETIME(3) NULL,
+    revoked_at DATETIME(3) NULL,
+    last_used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_api_keys PRIMARY KEY (id),
+    UNIQUE INDEX idx_api_keys_key_hash (key_hash)
+);
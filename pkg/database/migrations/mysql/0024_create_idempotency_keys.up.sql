@@ -0,0 +1,14 @@
+-- responses replayed to the retries of requests sent with an Idempotency-Key header
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash VARCHAR(64) NOT NULL,
+    fingerprint VARCHAR(64) NULL,
+    status_code BIGINT NULL,
+    content_type VARCHAR(255) NULL,
+    body LONGBLOB NULL,
+    expires_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY (key_hash),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
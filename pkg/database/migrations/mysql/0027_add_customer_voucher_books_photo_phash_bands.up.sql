@@ -0,0 +1,44 @@
+-- bytes of the perceptual hash of the photos, hashes within 7 bits share a byte so the duplicate check reads the bookings sharing one
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band0 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band0 ON customer_voucher_books (photo_phash_band0);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band1 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band1 ON customer_voucher_books (photo_phash_band1);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band2 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band2 ON customer_voucher_books (photo_phash_band2);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band3 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band3 ON customer_voucher_books (photo_phash_band3);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band4 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band4 ON customer_voucher_books (photo_phash_band4);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band5 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band5 ON customer_voucher_books (photo_phash_band5);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band6 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band6 ON customer_voucher_books (photo_phash_band6);
+
+ALTER TABLE customer_voucher_books ADD COLUMN photo_phash_band7 SMALLINT NULL;
+
+CREATE INDEX idx_customer_voucher_books_photo_phash_band7 ON customer_voucher_books (photo_phash_band7);
+
+UPDATE customer_voucher_books SET
+    photo_phash_band0 = (photo_phash >> 56) & 255,
+    photo_phash_band1 = (photo_phash >> 48) & 255,
+    photo_phash_band2 = (photo_phash >> 40) & 255,
+    photo_phash_band3 = (photo_phash >> 32) & 255,
+    photo_phash_band4 = (photo_phash >> 24) & 255,
+    photo_phash_band5 = (photo_phash >> 16) & 255,
+    photo_phash_band6 = (photo_phash >> 8) & 255,
+    photo_phash_band7 = (photo_phash >> 0) & 255
+WHERE photo_phash IS NOT NULL;
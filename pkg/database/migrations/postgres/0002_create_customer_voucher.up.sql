@@ -0,0 +1,10 @@
+-- vouchers of the first release, later columns are added by their own migration
+
+CREATE TABLE IF NOT EXISTS customer_voucher (
+    id BIGSERIAL NOT NULL,
+    customer_id BIGINT NULL,
+    voucher_code VARCHAR(255) NULL,
+    is_redeem BOOLEAN DEFAULT FALSE NULL,
+    CONSTRAINT pk_customer_voucher PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+-- voucher bookings of the first release, later columns are added by their own migration
+
+CREATE TABLE IF NOT EXISTS customer_voucher_books (
+    id BIGSERIAL NOT NULL,
+    customer_id BIGINT NULL,
+    customer_voucher_id BIGINT NULL,
+    expired_date TIMESTAMPTZ NULL,
+    CONSTRAINT pk_customer_voucher_books PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_books_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_customer_voucher_books_customer_voucher_id FOREIGN KEY (customer_voucher_id) REFERENCES customer_voucher (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+-- vouchers reserved by a booking until it is confirmed or released
+
+ALTER TABLE customer_voucher ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_reserved_until ON customer_voucher (reserved_until);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_customer_id ON customer_voucher (customer_id);
@@ -0,0 +1,17 @@
+-- voucher campaigns
+
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGSERIAL NOT NULL,
+    name VARCHAR(255) NULL,
+    start_date TIMESTAMPTZ NULL,
+    end_date TIMESTAMPTZ NULL,
+    budget BIGINT NULL,
+    per_customer_limit BIGINT NULL,
+    photo_verification_required BOOLEAN DEFAULT FALSE NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL,
+    deleted_at TIMESTAMPTZ NULL,
+    CONSTRAINT pk_campaigns PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns (deleted_at);
@@ -0,0 +1,6 @@
+ALTER TABLE customer_voucher_books DROP CONSTRAINT IF EXISTS fk_customer_voucher_books_campaign_id;
+DROP INDEX IF EXISTS idx_customer_voucher_books_campaign_id;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS campaign_id;
+ALTER TABLE customer_voucher DROP CONSTRAINT IF EXISTS fk_customer_voucher_campaign_id;
+DROP INDEX IF EXISTS idx_customer_voucher_campaign_id;
+ALTER TABLE customer_voucher DROP COLUMN IF EXISTS campaign_id;
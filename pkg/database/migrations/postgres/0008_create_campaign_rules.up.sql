@@ -0,0 +1,15 @@
+-- eligibility rules of the campaigns
+
+CREATE TABLE IF NOT EXISTS campaign_rules (
+    id BIGSERIAL NOT NULL,
+    campaign_id BIGINT NULL,
+    rule_type VARCHAR(50) NULL,
+    threshold NUMERIC(12,2) NULL,
+    window_days BIGINT NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT pk_campaign_rules PRIMARY KEY (id),
+    CONSTRAINT fk_campaign_rules_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_rules_campaign_id ON campaign_rules (campaign_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_customer_voucher_books_customer_voucher_id;
+DROP INDEX IF EXISTS idx_customer_voucher_books_customer_id;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS created_at;
+DROP INDEX IF EXISTS idx_customer_voucher_books_status;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS status;
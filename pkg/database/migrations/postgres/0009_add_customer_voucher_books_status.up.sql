@@ -0,0 +1,13 @@
+-- lifecycle status of the bookings, existing bookings are booked
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS status VARCHAR(20) DEFAULT 'booked' NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_books_status ON customer_voucher_books (status);
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NULL;
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_books_customer_id ON customer_voucher_books (customer_id);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_books_customer_voucher_id ON customer_voucher_books (customer_voucher_id);
@@ -0,0 +1,17 @@
+-- status history of the bookings
+
+CREATE TABLE IF NOT EXISTS customer_voucher_book_events (
+    id BIGSERIAL NOT NULL,
+    customer_voucher_book_id BIGINT NULL,
+    customer_id BIGINT NULL,
+    from_status VARCHAR(20) NULL,
+    to_status VARCHAR(20) NULL,
+    reason VARCHAR(255) NULL,
+    created_at TIMESTAMPTZ NULL,
+    CONSTRAINT pk_customer_voucher_book_events PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_book_events_customer_voucher_book_id FOREIGN KEY (customer_voucher_book_id) REFERENCES customer_voucher_books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_book_events_customer_voucher_book_id ON customer_voucher_book_events (customer_voucher_book_id);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_book_events_customer_id ON customer_voucher_book_events (customer_id);
@@ -0,0 +1,5 @@
+-- stored verification photo of the bookings
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS photo_hash VARCHAR(64) NULL;
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS photo_path VARCHAR(255) NULL;
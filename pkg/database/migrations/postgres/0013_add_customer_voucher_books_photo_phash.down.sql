@@ -0,0 +1,4 @@
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS flagged;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash;
@@ -0,0 +1,9 @@
+-- perceptual hash of the verification photos and bookings flagged as duplicates
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS photo_phash BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_books_photo_phash ON customer_voucher_books (photo_phash);
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS flagged BOOLEAN DEFAULT FALSE NULL;
+
+ALTER TABLE customer_voucher_books ADD COLUMN IF NOT EXISTS flag_reason VARCHAR(255) NULL;
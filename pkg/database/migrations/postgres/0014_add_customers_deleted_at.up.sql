@@ -0,0 +1,5 @@
+-- soft deleted customers
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customers_deleted_at ON customers (deleted_at);
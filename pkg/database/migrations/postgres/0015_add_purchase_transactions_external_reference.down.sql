@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_purchase_transactions_customer_id;
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS created_at;
+DROP INDEX IF EXISTS idx_purchase_transactions_external_reference;
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS external_reference;
@@ -0,0 +1,9 @@
+-- reference of the ingested purchase transactions in the source system
+
+ALTER TABLE purchase_transactions ADD COLUMN IF NOT EXISTS external_reference VARCHAR(100) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchase_transactions_external_reference ON purchase_transactions (external_reference);
+
+ALTER TABLE purchase_transactions ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_purchase_transactions_customer_id ON purchase_transactions (customer_id);
@@ -0,0 +1,4 @@
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS refunded_amount;
+DROP INDEX IF EXISTS idx_purchase_transactions_status;
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS status;
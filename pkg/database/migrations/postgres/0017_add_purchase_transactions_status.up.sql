@@ -0,0 +1,9 @@
+-- refunded and voided purchase transactions, existing transactions are completed
+
+ALTER TABLE purchase_transactions ADD COLUMN IF NOT EXISTS status VARCHAR(20) DEFAULT 'completed' NULL;
+
+CREATE INDEX IF NOT EXISTS idx_purchase_transactions_status ON purchase_transactions (status);
+
+ALTER TABLE purchase_transactions ADD COLUMN IF NOT EXISTS refunded_amount NUMERIC(10,2) DEFAULT 0 NULL;
+
+ALTER TABLE purchase_transactions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NULL;
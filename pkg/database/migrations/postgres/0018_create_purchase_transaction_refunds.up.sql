@@ -0,0 +1,13 @@
+-- refunds of the purchase transactions
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_refunds (
+    id BIGSERIAL NOT NULL,
+    purchase_transaction_id BIGINT NULL,
+    amount NUMERIC(10,2) NULL,
+    reason VARCHAR(255) NULL,
+    created_at TIMESTAMPTZ NULL,
+    CONSTRAINT pk_purchase_transaction_refunds PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_refunds_purchase_transaction_id FOREIGN KEY (purchase_transaction_id) REFERENCES purchase_transactions (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_transaction_refunds_purchase_transaction_id ON purchase_transaction_refunds (purchase_transaction_id);
@@ -0,0 +1,3 @@
+ALTER TABLE purchase_transactions DROP COLUMN IF EXISTS currency;
+ALTER TABLE campaign_rules DROP COLUMN IF EXISTS currency;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS currency;
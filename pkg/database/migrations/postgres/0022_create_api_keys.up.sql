@@ -0,0 +1,17 @@
+-- hashed api keys of the partner integrations
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL NOT NULL,
+    owner VARCHAR(255) NULL,
+    prefix VARCHAR(16) NULL,
+    key_hash VARCHAR(64) NULL,
+    scopes VARCHAR(1000) NULL,
+    expires_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT pk_api_keys PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,5 @@
+-- campaigns saved with an empty rule set have no rules, campaigns never configured use the default rules
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS rules_configured BOOLEAN DEFAULT FALSE NULL;
+
+UPDATE campaigns SET rules_configured = TRUE WHERE id IN (SELECT campaign_id FROM campaign_rules);
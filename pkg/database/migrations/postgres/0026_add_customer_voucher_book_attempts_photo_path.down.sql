@@ -0,0 +1 @@
+ALTER TABLE customer_voucher_book_attempts DROP COLUMN IF EXISTS photo_path;
@@ -0,0 +1,3 @@
+-- storage key of the photo of every attempt, rejected photos are kept as evidence of disputed redemptions
+
+ALTER TABLE customer_voucher_book_attempts ADD COLUMN IF NOT EXISTS photo_path VARCHAR(255) NULL;
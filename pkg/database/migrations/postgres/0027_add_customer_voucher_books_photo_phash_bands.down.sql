@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band7;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band7;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band6;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band6;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band5;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band5;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band4;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band4;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band3;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band3;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band2;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band2;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band1;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band1;
+DROP INDEX IF EXISTS idx_customer_voucher_books_photo_phash_band0;
+ALTER TABLE customer_voucher_books DROP COLUMN IF EXISTS photo_phash_band0;
@@ -0,0 +1,14 @@
+-- customers of the first release, later columns are added by their own migration
+
+CREATE TABLE IF NOT EXISTS customers (
+    id INTEGER NOT NULL,
+    first_name VARCHAR(255) NULL,
+    last_name VARCHAR(255) NULL,
+    gender VARCHAR(50) NULL,
+    date_of_birth DATE NULL,
+    contact_number VARCHAR(50) NULL,
+    email VARCHAR(255) NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_customers PRIMARY KEY (id)
+);
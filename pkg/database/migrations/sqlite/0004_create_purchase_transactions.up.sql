@@ -0,0 +1,11 @@
+-- purchase transactions of the first release, later columns are added by their own migration
+
+CREATE TABLE IF NOT EXISTS purchase_transactions (
+    id INTEGER NOT NULL,
+    customer_id BIGINT NULL,
+    total_spent DECIMAL(10,2) NULL,
+    total_saving DECIMAL(10,2) NULL,
+    transaction_at DATETIME NULL,
+    CONSTRAINT pk_purchase_transactions PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transactions_customer_id FOREIGN KEY (customer_id) REFERENCES customers (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_customer_voucher_customer_id;
+DROP INDEX IF EXISTS idx_customer_voucher_reserved_until;
+ALTER TABLE customer_voucher DROP COLUMN reserved_until;
@@ -0,0 +1,17 @@
+-- voucher campaigns
+
+CREATE TABLE IF NOT EXISTS campaigns (
+    id INTEGER NOT NULL,
+    name VARCHAR(255) NULL,
+    start_date DATETIME NULL,
+    end_date DATETIME NULL,
+    budget BIGINT NULL,
+    per_customer_limit BIGINT NULL,
+    photo_verification_required BOOLEAN DEFAULT FALSE NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL,
+    CONSTRAINT pk_campaigns PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns (deleted_at);
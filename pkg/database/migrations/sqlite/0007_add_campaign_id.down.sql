@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_customer_voucher_books_campaign_id;
+ALTER TABLE customer_voucher_books DROP COLUMN campaign_id;
+DROP INDEX IF EXISTS idx_customer_voucher_campaign_id;
+ALTER TABLE customer_voucher DROP COLUMN campaign_id;
@@ -0,0 +1,11 @@
+-- campaign of the vouchers and the bookings
+
+-- sqlite cannot drop a column of a foreign key, the column is added without one
+
+ALTER TABLE customer_voucher ADD COLUMN campaign_id BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_campaign_id ON customer_voucher (campaign_id);
+
+ALTER TABLE customer_voucher_books ADD COLUMN campaign_id BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_books_campaign_id ON customer_voucher_books (campaign_id);
@@ -0,0 +1,17 @@
+-- photo verification attempts of the bookings
+
+CREATE TABLE IF NOT EXISTS customer_voucher_book_attempts (
+    id INTEGER NOT NULL,
+    customer_voucher_book_id BIGINT NULL,
+    customer_id BIGINT NULL,
+    outcome VARCHAR(20) NULL,
+    reason_code VARCHAR(50) NULL,
+    photo_hash VARCHAR(64) NULL,
+    created_at DATETIME NULL,
+    CONSTRAINT pk_customer_voucher_book_attempts PRIMARY KEY (id),
+    CONSTRAINT fk_customer_voucher_book_attempts_customer_voucher_book_id FOREIGN KEY (customer_voucher_book_id) REFERENCES customer_voucher_books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_book_attempts_customer_voucher_book_id ON customer_voucher_book_attempts (customer_voucher_book_id);
+
+CREATE INDEX IF NOT EXISTS idx_customer_voucher_book_attempts_customer_id ON customer_voucher_book_attempts (customer_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_customers_deleted_at;
+ALTER TABLE customers DROP COLUMN deleted_at;
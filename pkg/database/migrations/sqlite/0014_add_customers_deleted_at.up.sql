@@ -0,0 +1,5 @@
+-- soft deleted customers
+
+ALTER TABLE customers ADD COLUMN deleted_at DATETIME NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customers_deleted_at ON customers (deleted_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS purchase_transaction_import_errors;
+DROP TABLE IF EXISTS purchase_transaction_imports;
@@ -0,0 +1,31 @@
+-- csv imports of purchase transactions and their failed rows
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_imports (
+    id INTEGER NOT NULL,
+    checksum VARCHAR(64) NULL,
+    file_name VARCHAR(255) NULL,
+    status VARCHAR(20) DEFAULT 'pending' NULL,
+    last_row BIGINT NULL,
+    imported_rows BIGINT NULL,
+    duplicate_rows BIGINT NULL,
+    failed_rows BIGINT NULL,
+    message VARCHAR(255) NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_purchase_transaction_imports PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchase_transaction_imports_checksum ON purchase_transaction_imports (checksum);
+
+CREATE TABLE IF NOT EXISTS purchase_transaction_import_errors (
+    id INTEGER NOT NULL,
+    purchase_transaction_import_id BIGINT NULL,
+    "row" BIGINT NULL,
+    external_reference VARCHAR(100) NULL,
+    message VARCHAR(255) NULL,
+    created_at DATETIME NULL,
+    CONSTRAINT pk_purchase_transaction_import_errors PRIMARY KEY (id),
+    CONSTRAINT fk_purchase_transaction_import_errors_purchase_transaction_import_id FOREIGN KEY (purchase_transaction_import_id) REFERENCES purchase_transaction_imports (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_transaction_import_errors_purchase_transaction_import_id ON purchase_transaction_import_errors (purchase_transaction_import_id);
@@ -0,0 +1,4 @@
+ALTER TABLE purchase_transactions DROP COLUMN updated_at;
+ALTER TABLE purchase_transactions DROP COLUMN refunded_amount;
+DROP INDEX IF EXISTS idx_purchase_transactions_status;
+ALTER TABLE purchase_transactions DROP COLUMN status;
@@ -0,0 +1,13 @@
+-- exchange rates of currency pairs
+
+CREATE TABLE IF NOT EXISTS currency_rates (
+    id INTEGER NOT NULL,
+    base_currency VARCHAR(3) NULL,
+    quote_currency VARCHAR(3) NULL,
+    rate DECIMAL(18,8) NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_currency_rates PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_currency_rates_pair ON currency_rates (base_currency, quote_currency);
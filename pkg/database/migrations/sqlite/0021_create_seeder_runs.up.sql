@@ -0,0 +1,7 @@
+-- seeders already stored in the database
+
+CREATE TABLE IF NOT EXISTS seeder_runs (
+    name VARCHAR(100) NOT NULL,
+    applied_at DATETIME NULL,
+    CONSTRAINT pk_seeder_runs PRIMARY KEY (name)
+);
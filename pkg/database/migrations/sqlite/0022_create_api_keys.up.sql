@@ -0,0 +1,17 @@
+-- hashed api keys of the partner integrations
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER NOT NULL,
+    owner VARCHAR(255) NULL,
+    prefix VARCHAR(16) NULL,
+    key_hash VARCHAR(64) NULL,
+    scopes VARCHAR(1000) NULL,
+    expires_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_api_keys PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,29 @@
+-- roles granting permissions to the api keys
+
+CREATE TABLE IF NOT EXISTS roles (
+    id INTEGER NOT NULL,
+    name VARCHAR(100) NULL,
+    description VARCHAR(255) NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_roles PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    CONSTRAINT pk_role_permissions PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS api_key_roles (
+    api_key_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    CONSTRAINT pk_api_key_roles PRIMARY KEY (api_key_id, role_id),
+    CONSTRAINT fk_api_key_roles_api_key_id FOREIGN KEY (api_key_id) REFERENCES api_keys (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_api_key_roles_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_roles_role_id ON api_key_roles (role_id);
@@ -0,0 +1,15 @@
+-- responses replayed to the retries of requests sent with an Idempotency-Key header
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash VARCHAR(64) NOT NULL,
+    fingerprint VARCHAR(64) NULL,
+    status_code BIGINT NULL,
+    content_type VARCHAR(255) NULL,
+    body BLOB NULL,
+    expires_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,84 @@
+package imagehash
+
+import (
+	"image"
+	"math/bits"
+)
+
+// Bands number of bytes of a hash returned by Split
+const Bands = 8
+
+// samplesPerCell upper bound of pixels sampled on each axis of a cell, keeps large photos cheap to hash
+const samplesPerCell = 16
+
+// DHash difference hash of the image, the image is shrunk to 9x8 grayscale cells and every bit tells
+// whether a cell is brighter than its right neighbour. Similar images give hashes with a small Distance
+func DHash(img image.Image) uint64 {
+	cells := grayCells(img, 9, 8)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance hamming distance of two hashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Split bytes of the hash, most significant first. Two hashes whose Distance is below Bands differ in
+// fewer bytes than there are, so they have at least one equal byte and equal bytes select the candidates
+// of a similarity search
+func Split(hash uint64) [Bands]int {
+	var bands [Bands]int
+	for i := range bands {
+		bands[i] = int(hash >> uint(8*(Bands-1-i)) & 0xff)
+	}
+	return bands
+}
+
+// grayCells average luminance of every cell of the image split in width x height cells
+func grayCells(img image.Image, width, height int) [][]float64 {
+	bounds := img.Bounds()
+	cells := make([][]float64, height)
+
+	for cy := 0; cy < height; cy++ {
+		cells[cy] = make([]float64, width)
+		y0 := bounds.Min.Y + cy*bounds.Dy()/height
+		y1 := bounds.Min.Y + (cy+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for cx := 0; cx < width; cx++ {
+			x0 := bounds.Min.X + cx*bounds.Dx()/width
+			x1 := bounds.Min.X + (cx+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			stepX := (x1-x0)/samplesPerCell + 1
+			stepY := (y1-y0)/samplesPerCell + 1
+
+			total, count := 0.0, 0
+			for y := y0; y < y1 && y < bounds.Max.Y; y += stepY {
+				for x := x0; x < x1 && x < bounds.Max.X; x += stepX {
+					r, g, b, _ := img.At(x, y).RGBA()
+					total += 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+					count++
+				}
+			}
+			if count > 0 {
+				cells[cy][cx] = total / float64(count)
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,138 @@
+package imagevalidator
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	// decoders of the supported formats
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MimeTypeJPEG = "image/jpeg"
+	MimeTypePNG  = "image/png"
+	MimeTypeWebP = "image/webp"
+)
+
+var (
+	ErrPayloadTooLarge    = errors.New("image payload too large")
+	ErrMimeTypeNotAllowed = errors.New("image mime type not allowed")
+	ErrDimensionTooSmall  = errors.New("image dimension too small")
+	ErrDimensionTooLarge  = errors.New("image dimension too large")
+	ErrCorrupt            = errors.New("image data corrupt")
+)
+
+// Config limits of an accepted image, zero value limits are not checked
+type Config struct {
+	MaxBytes     int64
+	MinWidth     int
+	MinHeight    int
+	MaxWidth     int
+	MaxHeight    int
+	AllowedTypes []string
+}
+
+// DefaultConfig face photo limits
+func DefaultConfig() Config {
+	return Config{
+		MaxBytes:     5 << 20,
+		MinWidth:     200,
+		MinHeight:    200,
+		MaxWidth:     6000,
+		MaxHeight:    6000,
+		AllowedTypes: []string{MimeTypeJPEG, MimeTypePNG, MimeTypeWebP},
+	}
+}
+
+// Image decoded image with the raw payload
+type Image struct {
+	MimeType string
+	Width    int
+	Height   int
+	Data     []byte
+	Decoded  image.Image
+}
+
+type Validator struct {
+	config Config
+}
+
+func NewValidator(config Config) *Validator {
+	return &Validator{config: config}
+}
+
+// ValidateFile read the uploaded file and validate it with Validate
+func (v *Validator) ValidateFile(file *multipart.FileHeader) (*Image, error) {
+	if v.config.MaxBytes > 0 && file.Size > v.config.MaxBytes {
+		return nil, ErrPayloadTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return v.Validate(f)
+}
+
+// Validate sniff the content type from the data, not from the file name or header, check the dimension
+// before decoding so huge images are rejected early, then decode the whole image to detect corrupt data
+func (v *Validator) Validate(r io.Reader) (*Image, error) {
+	if v.config.MaxBytes > 0 {
+		r = io.LimitReader(r, v.config.MaxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if v.config.MaxBytes > 0 && int64(len(data)) > v.config.MaxBytes {
+		return nil, ErrPayloadTooLarge
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !v.allowed(mimeType) {
+		return nil, ErrMimeTypeNotAllowed
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if config.Width < v.config.MinWidth || config.Height < v.config.MinHeight {
+		return nil, ErrDimensionTooSmall
+	}
+	if (v.config.MaxWidth > 0 && config.Width > v.config.MaxWidth) ||
+		(v.config.MaxHeight > 0 && config.Height > v.config.MaxHeight) {
+		return nil, ErrDimensionTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	return &Image{
+		MimeType: mimeType,
+		Width:    config.Width,
+		Height:   config.Height,
+		Data:     data,
+		Decoded:  decoded,
+	}, nil
+}
+
+func (v *Validator) allowed(mimeType string) bool {
+	for _, allowed := range v.config.AllowedTypes {
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency currency of amounts stored before currencies were introduced
+const DefaultCurrency = "USD"
+
+// decimal places of Amount and Rate
+const (
+	amountPlaces = 2
+	ratePlaces   = 8
+)
+
+var (
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrUnsupportedCurrency amounts of the currency have more decimals than an Amount
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+// minorUnits decimals of the ISO 4217 currencies whose minor unit is not 2, the other currencies have 2.
+// IDR is listed with 2 decimals by ISO 4217 but its minor unit is no longer in use
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IDR": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits decimals of the amounts of the currency, e.g. 0 for JPY and 3 for KWD
+func MinorUnits(currency string) int {
+	if places, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return places
+	}
+	return amountPlaces
+}
+
+// CheckCurrency ErrUnsupportedCurrency when the amounts of the currency need more decimals than an Amount holds
+func CheckCurrency(currency string) error {
+	if MinorUnits(currency) > amountPlaces {
+		return fmt.Errorf("%w: %s has %d decimals, at most %d are supported", ErrUnsupportedCurrency, currency, MinorUnits(currency), amountPlaces)
+	}
+	return nil
+}
+
+// Amount fixed point money amount with 2 decimals, the value is the number of cents.
+// Amounts of currencies with fewer decimals are whole multiples of their minor unit, see MinorUnits.
+// It is stored in decimal columns and encoded to json as a number
+type Amount int64
+
+// FromFloat amount of the value rounded to the cent
+func FromFloat(value float64) Amount {
+	return Amount(math.Round(value * 100))
+}
+
+// ParseAmount parse a decimal string, more than 2 decimals is an error
+func ParseAmount(value string) (Amount, error) {
+	result, err := parseFixed(value, amountPlaces)
+	return Amount(result), err
+}
+
+// ParseAmountIn parse a decimal string of the currency, more decimals than the minor unit of the currency is an error
+func ParseAmountIn(value, currency string) (Amount, error) {
+	amount, err := ParseAmount(value)
+	if err != nil {
+		return 0, err
+	}
+	if err := amount.CheckIn(currency); err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// CheckIn ErrInvalidDecimal when the amount has more decimals than the minor unit of the currency,
+// ErrUnsupportedCurrency when the currency has more decimals than an Amount
+func (a Amount) CheckIn(currency string) error {
+	if err := CheckCurrency(currency); err != nil {
+		return err
+	}
+	if a.Round(currency) != a {
+		return fmt.Errorf("%w: %s has more than %d decimals of %s", ErrInvalidDecimal, a, MinorUnits(currency), currency)
+	}
+	return nil
+}
+
+// Round amount rounded half away from zero to the minor unit of the currency
+func (a Amount) Round(currency string) Amount {
+	places := MinorUnits(currency)
+	if places >= amountPlaces {
+		return a
+	}
+	unit := big.NewInt(int64(math.Pow10(amountPlaces - places)))
+	return Amount(new(big.Int).Mul(divRound(big.NewInt(int64(a)), unit), unit).Int64())
+}
+
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+func (a Amount) String() string {
+	return formatFixed(int64(a), amountPlaces)
+}
+
+// Convert amount multiplied by the rate, rounded half away from zero to the cent
+func (a Amount) Convert(rate Rate) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(rate)))
+	return Amount(divRound(product, big.NewInt(rateScale)).Int64())
+}
+
+// ConvertInverse amount divided by the rate, rounded half away from zero to the cent
+func (a Amount) ConvertInverse(rate Rate) Amount {
+	if rate == 0 {
+		return 0
+	}
+	dividend := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(rateScale))
+	return Amount(divRound(dividend, big.NewInt(int64(rate))).Int64())
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a *Amount) Scan(src interface{}) error {
+	result, err := scanFixed(src, amountPlaces)
+	*a = Amount(result)
+	return err
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	result, err := unmarshalFixed(data, amountPlaces)
+	if err != nil {
+		return err
+	}
+	*a = Amount(result)
+	return nil
+}
+
+// rateScale 10^ratePlaces
+const rateScale = 100000000
+
+// Rate fixed point exchange rate with 8 decimals, one unit of the base currency is Rate units of the quote currency
+type Rate int64
+
+// ParseRate parse a decimal string, more than 8 decimals is an error
+func ParseRate(value string) (Rate, error) {
+	result, err := parseFixed(value, ratePlaces)
+	return Rate(result), err
+}
+
+func (r Rate) Float64() float64 {
+	return float64(r) / rateScale
+}
+
+func (r Rate) String() string {
+	return formatFixed(int64(r), ratePlaces)
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (r *Rate) Scan(src interface{}) error {
+	result, err := scanFixed(src, ratePlaces)
+	*r = Rate(result)
+	return err
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	result, err := unmarshalFixed(data, ratePlaces)
+	if err != nil {
+		return err
+	}
+	*r = Rate(result)
+	return nil
+}
+
+// parseFixed decimal string as an integer of 10^-places units, the value must be exact
+func parseFixed(value string, places int) (int64, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	rat.Mul(rat, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)))
+	if !rat.IsInt() {
+		return 0, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidDecimal, value, places)
+	}
+	if !rat.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidDecimal, value)
+	}
+	return rat.Num().Int64(), nil
+}
+
+func formatFixed(value int64, places int) string {
+	sign := ""
+	abs := new(big.Int).SetInt64(value)
+	if value < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+	digits := abs.String()
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-places] + "." + digits[len(digits)-places:]
+}
+
+func scanFixed(src interface{}, places int) (int64, error) {
+	switch value := src.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		return parseFixed(string(value), places)
+	case string:
+		return parseFixed(value, places)
+	case int64:
+		return parseFixed(fmt.Sprint(value), places)
+	case float64:
+		return int64(math.Round(value * math.Pow10(places))), nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported type %T", ErrInvalidDecimal, src)
+	}
+}
+
+// unmarshalFixed json number or quoted decimal string
+func unmarshalFixed(data []byte, places int) (int64, error) {
+	value := strings.TrimSpace(string(data))
+	if value == "null" {
+		return 0, nil
+	}
+	return parseFixed(strings.Trim(value, `"`), places)
+}
+
+// divRound quotient rounded half away from zero
+func divRound(dividend, divisor *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(dividend, divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(new(big.Int).Abs(divisor)) >= 0 {
+		if (dividend.Sign() < 0) != (divisor.Sign() < 0) {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
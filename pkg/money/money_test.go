@@ -0,0 +1,363 @@
+package money_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
+	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    money.Amount
+		wantErr bool
+	}{
+		{value: "10", want: 1000},
+		{value: "10.5", want: 1050},
+		{value: "10.50", want: 1050},
+		{value: " 0.01 ", want: 1},
+		{value: "-12.34", want: -1234},
+		{value: "-0.5", want: -50},
+		{value: "99999999.99", want: 9999999999},
+		{value: "10.500", want: 1050},
+		{value: "10.505", wantErr: true},
+		{value: "0.001", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "", wantErr: true},
+		{value: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := money.ParseAmount(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, money.ErrInvalidDecimal) {
+					t.Errorf("ParseAmount(%q) error = %v, want %v", tt.value, err, money.ErrInvalidDecimal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAmount(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		amount money.Amount
+		want   string
+	}{
+		{amount: 0, want: "0.00"},
+		{amount: 5, want: "0.05"},
+		{amount: 1050, want: "10.50"},
+		{amount: -5, want: "-0.05"},
+		{amount: -1234, want: "-12.34"},
+		{amount: 9999999999, want: "99999999.99"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %s, want %s", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  money.Amount
+	}{
+		{value: 10.5, want: 1050},
+		{value: 0.1 + 0.2, want: 30},
+		// 1.005 is stored as 1.00499999999999989...
+		{value: 1.005, want: 100},
+		{value: 0.125, want: 13},
+		{value: -0.125, want: -13},
+		{value: 99999999.99, want: 9999999999},
+	}
+
+	for _, tt := range tests {
+		if got := money.FromFloat(tt.value); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAmountScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    money.Amount
+		wantErr bool
+	}{
+		{name: "decimal column as bytes", src: []byte("12.34"), want: 1234},
+		{name: "decimal column as string", src: "-0.50", want: -50},
+		{name: "integer", src: int64(12), want: 1200},
+		{name: "float", src: 12.345, want: 1235},
+		{name: "null", src: nil, want: 0},
+		{name: "more than 2 decimals", src: "1.234", wantErr: true},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Amount
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				if !errors.Is(err, money.ErrInvalidDecimal) {
+					t.Errorf("Scan(%v) error = %v, want %v", tt.src, err, money.ErrInvalidDecimal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Scan(%v) = %d, want %d", tt.src, got, tt.want)
+			}
+		})
+	}
+
+	value, err := money.Amount(-1234).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "-12.34" {
+		t.Errorf("Value() = %v, want -12.34", value)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var payload struct {
+		Amount money.Amount `json:"amount"`
+	}
+	for _, body := range []string{`{"amount":12.34}`, `{"amount":"12.34"}`} {
+		if err := json.Unmarshal([]byte(body), &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Amount != 1234 {
+			t.Errorf("unmarshal %s = %d, want 1234", body, payload.Amount)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"amount":12.345}`), &payload); !errors.Is(err, money.ErrInvalidDecimal) {
+		t.Errorf("unmarshal 12.345 error = %v, want %v", err, money.ErrInvalidDecimal)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":12.34}` {
+		t.Errorf("marshal = %s, want {\"amount\":12.34}", data)
+	}
+}
+
+func TestAmountMaxValidation(t *testing.T) {
+	type request struct {
+		TotalSpent money.Amount `validate:"gt=0,max=99999999.99"`
+	}
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "0.01"},
+		{value: "99999999.99"},
+		{value: "100000000.00", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "-0.01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			amount, err := money.ParseAmount(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = validator.Validate.ValidateStruct(request{TotalSpent: amount})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate %s error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	rate, err := money.ParseRate("15432.12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 1543212345678 || rate.String() != "15432.12345678" {
+		t.Errorf("ParseRate = %d %s, want 1543212345678", rate, rate)
+	}
+	if _, err := money.ParseRate("1.123456789"); !errors.Is(err, money.ErrInvalidDecimal) {
+		t.Errorf("ParseRate with 9 decimals error = %v, want %v", err, money.ErrInvalidDecimal)
+	}
+
+	var scanned money.Rate
+	if err := scanned.Scan([]byte("0.00006480")); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != 6480 {
+		t.Errorf("Scan = %d, want 6480", scanned)
+	}
+}
+
+func TestAmountConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		rate    string
+		want    string
+		inverse string
+	}{
+		{name: "usd to idr", amount: "10.00", rate: "15432.12345678", want: "154321.23", inverse: "10.00"},
+		{name: "idr to usd", amount: "154321.23", rate: "0.00006480", want: "10.00", inverse: "154320.99"},
+		{name: "half cent rounded away from zero", amount: "0.01", rate: "0.50000000", want: "0.01", inverse: "0.02"},
+		{name: "negative half cent rounded away from zero", amount: "-0.01", rate: "0.50000000", want: "-0.01", inverse: "-0.02"},
+		{name: "identity", amount: "12.34", rate: "1", want: "12.34", inverse: "12.34"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := money.ParseAmount(tt.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rate, err := money.ParseRate(tt.rate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			converted := amount.Convert(rate)
+			if converted.String() != tt.want {
+				t.Errorf("%s * %s = %s, want %s", tt.amount, tt.rate, converted, tt.want)
+			}
+			if back := converted.ConvertInverse(rate); back.String() != tt.inverse {
+				t.Errorf("%s / %s = %s, want %s", converted, tt.rate, back, tt.inverse)
+			}
+		})
+	}
+
+	if got := money.Amount(1234).ConvertInverse(0); got != 0 {
+		t.Errorf("ConvertInverse(0) = %d, want 0", got)
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int
+		wantErr  bool
+	}{
+		{currency: "USD", want: 2},
+		{currency: "EUR", want: 2},
+		{currency: "JPY", want: 0},
+		{currency: "jpy", want: 0},
+		{currency: "IDR", want: 0},
+		{currency: "KRW", want: 0},
+		{currency: "KWD", want: 3, wantErr: true},
+		{currency: "BHD", want: 3, wantErr: true},
+		{currency: "CLF", want: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := money.MinorUnits(tt.currency); got != tt.want {
+				t.Errorf("MinorUnits(%s) = %d, want %d", tt.currency, got, tt.want)
+			}
+			err := money.CheckCurrency(tt.currency)
+			if tt.wantErr != errors.Is(err, money.ErrUnsupportedCurrency) {
+				t.Errorf("CheckCurrency(%s) error = %v, want error %v", tt.currency, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAmountRound(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		{amount: "10.49", currency: "USD", want: "10.49"},
+		{amount: "10.49", currency: "JPY", want: "10.00"},
+		{amount: "10.50", currency: "JPY", want: "11.00"},
+		{amount: "-10.50", currency: "JPY", want: "-11.00"},
+		{amount: "154321.23", currency: "IDR", want: "154321.00"},
+		{amount: "0.49", currency: "IDR", want: "0.00"},
+	}
+
+	for _, tt := range tests {
+		amount, err := money.ParseAmount(tt.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := amount.Round(tt.currency); got.String() != tt.want {
+			t.Errorf("%s rounded in %s = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountIn(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     money.Amount
+		wantErr  error
+	}{
+		{value: "10.50", currency: "USD", want: 1050},
+		{value: "1500", currency: "JPY", want: 150000},
+		{value: "1500.00", currency: "JPY", want: 150000},
+		{value: "1500.5", currency: "JPY", wantErr: money.ErrInvalidDecimal},
+		{value: "154321", currency: "IDR", want: 15432100},
+		{value: "154321.23", currency: "IDR", wantErr: money.ErrInvalidDecimal},
+		{value: "10.505", currency: "USD", wantErr: money.ErrInvalidDecimal},
+		{value: "1.500", currency: "KWD", wantErr: money.ErrUnsupportedCurrency},
+		{value: "1", currency: "KWD", wantErr: money.ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.value, func(t *testing.T) {
+			got, err := money.ParseAmountIn(tt.value, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseAmountIn(%q, %s) error = %v, want %v", tt.value, tt.currency, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAmountIn(%q, %s) = %d, want %d", tt.value, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyValidation(t *testing.T) {
+	type request struct {
+		Currency string `validate:"omitempty,iso4217,currency"`
+	}
+
+	tests := []struct {
+		currency string
+		wantErr  bool
+	}{
+		{currency: ""},
+		{currency: "USD"},
+		{currency: "JPY"},
+		{currency: "IDR"},
+		{currency: "KWD", wantErr: true},
+		{currency: "XYZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := validator.Validate.ValidateStruct(request{Currency: tt.currency})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validate %q error = %v, want error %v", tt.currency, err, tt.wantErr)
+		}
+	}
+}
@@ -60,6 +60,37 @@ const (
 	CustomerNotYetBookVoucher         = "ERROR-API-033"
 	CustomerBookVoucherExpired        = "ERROR-API-034"
 	CustomerVerifyImage               = "ERROR-API-035"
+	CampaignNotActive                 = "ERROR-API-036"
+	CampaignBudgetExhausted           = "ERROR-API-037"
+	CustomerAgeNotEligible            = "ERROR-API-038"
+	AccountAgeNotEligible             = "ERROR-API-039"
+	PreviousRedemptionNotEligible     = "ERROR-API-040"
+	InvalidBookStatusTransition       = "ERROR-API-041"
+	ImagePayloadTooLarge              = "ERROR-API-042"
+	ImageMimeTypeNotAllowed           = "ERROR-API-043"
+	ImageDimensionTooSmall            = "ERROR-API-044"
+	ImageDimensionTooLarge            = "ERROR-API-045"
+	ImageCorrupt                      = "ERROR-API-046"
+	FaceVerificationInconclusive      = "ERROR-API-047"
+	VerificationAttemptsExceeded      = "ERROR-API-048"
+	DuplicatePhoto                    = "ERROR-API-049"
+	BookingPendingReview              = "ERROR-API-050"
+	PurchaseReferenceConflict         = "ERROR-API-051"
+	TransactionAtInFuture             = "ERROR-API-052"
+	ImportInProgress                  = "ERROR-API-053"
+	ImportInvalidFile                 = "ERROR-API-054"
+	PurchaseTransactionStatusInvalid  = "ERROR-API-055"
+	RefundAmountExceeded              = "ERROR-API-056"
+	CurrencyRateNotFound              = "ERROR-API-057"
+	ApiKeyRevoked                     = "ERROR-API-058"
+	InvalidApiKeyExpiresAt            = "ERROR-API-059"
+	UnknownPermission                 = "ERROR-API-060"
+	TooManyRequestsCodeError          = "ERROR-API-061"
+	IdempotencyKeyInvalid             = "ERROR-API-062"
+	IdempotencyKeyMismatch            = "ERROR-API-063"
+	IdempotencyKeyInProgress          = "ERROR-API-064"
+	RequestBodyTooLarge               = "ERROR-API-065"
+	CurrencyAmountInvalid             = "ERROR-API-066"
 )
 
 var (
@@ -71,6 +102,32 @@ var (
 	ErrCustomerNotYetBookVoucher         = errors.New("customer not have voucher")
 	ErrCustomerBookVoucherExpired        = errors.New("voucher customer expired")
 	ErrCustomerVerifyImage               = errors.New("invalid verify image ,is not face")
+	ErrInvalidActiveEndDate              = errors.New("start date can't be more than end date")
+	ErrCampaignNotActive                 = errors.New("campaign is not active")
+	ErrCampaignBudgetExhausted           = errors.New("campaign budget exhausted")
+	ErrInvalidBookStatusTransition       = errors.New("invalid voucher booking status transition")
+	ErrImagePayloadTooLarge              = errors.New("image payload too large")
+	ErrImageMimeTypeNotAllowed           = errors.New("image mime type not allowed")
+	ErrImageDimensionTooSmall            = errors.New("image dimension too small")
+	ErrImageDimensionTooLarge            = errors.New("image dimension too large")
+	ErrImageCorrupt                      = errors.New("image data corrupt")
+	ErrFaceVerificationInconclusive      = errors.New("face verification inconclusive")
+	ErrVerificationAttemptsExceeded      = errors.New("verification attempts exceeded")
+	ErrDuplicatePhoto                    = errors.New("photo already used by another customer")
+	ErrBookingPendingReview              = errors.New("booking is waiting for manual review")
+	ErrPurchaseReferenceConflict         = errors.New("external reference already used by a different transaction")
+	ErrTransactionAtInFuture             = errors.New("transaction time is in the future")
+	ErrImportInProgress                  = errors.New("import of the file is already running")
+	ErrImportInvalidFile                 = errors.New("import file is not a valid purchase transaction csv")
+	ErrPurchaseTransactionStatusInvalid  = errors.New("purchase transaction can not be changed in its current status")
+	ErrRefundAmountExceeded              = errors.New("refund amount exceeds the remaining amount of the purchase transaction")
+	ErrCurrencyRateNotFound              = errors.New("currency rate not found")
+	ErrApiKeyRevoked                     = errors.New("api key is revoked")
+	ErrInvalidApiKeyExpiresAt            = errors.New("api key expiry is not in the future")
+	ErrApiKeyNotRegistered               = errors.New("api key is not registered")
+	ErrApiKeyExpired                     = errors.New("api key is expired")
+	ErrUnknownPermission                 = errors.New("unknown permission")
+	ErrCurrencyAmountInvalid             = errors.New("amount has more decimals than the currency")
 )
 
 func ErrorCodeText(code, locale string, args ...interface{}) string {
@@ -153,6 +210,68 @@ func ErrorCodeText(code, locale string, args ...interface{}) string {
 		return i18n.Tr(locale, "message.errorCustomerBookVoucherExpired", args)
 	case CustomerVerifyImage:
 		return i18n.Tr(locale, "message.errorCustomerVerifyImage", args)
+	case CampaignNotActive:
+		return i18n.Tr(locale, "message.errorCampaignNotActive", args)
+	case CampaignBudgetExhausted:
+		return i18n.Tr(locale, "message.errorCampaignBudgetExhausted", args)
+	case CustomerAgeNotEligible:
+		return i18n.Tr(locale, "message.errorCustomerAgeNotEligible", args)
+	case AccountAgeNotEligible:
+		return i18n.Tr(locale, "message.errorAccountAgeNotEligible", args)
+	case PreviousRedemptionNotEligible:
+		return i18n.Tr(locale, "message.errorPreviousRedemptionNotEligible", args)
+	case InvalidBookStatusTransition:
+		return i18n.Tr(locale, "message.errorInvalidBookStatusTransition", args)
+	case ImagePayloadTooLarge:
+		return i18n.Tr(locale, "message.errorImagePayloadTooLarge", args)
+	case ImageMimeTypeNotAllowed:
+		return i18n.Tr(locale, "message.errorImageMimeTypeNotAllowed", args)
+	case ImageDimensionTooSmall:
+		return i18n.Tr(locale, "message.errorImageDimensionTooSmall", args)
+	case ImageDimensionTooLarge:
+		return i18n.Tr(locale, "message.errorImageDimensionTooLarge", args)
+	case ImageCorrupt:
+		return i18n.Tr(locale, "message.errorImageCorrupt", args)
+	case FaceVerificationInconclusive:
+		return i18n.Tr(locale, "message.errorFaceVerificationInconclusive", args)
+	case VerificationAttemptsExceeded:
+		return i18n.Tr(locale, "message.errorVerificationAttemptsExceeded", args)
+	case DuplicatePhoto:
+		return i18n.Tr(locale, "message.errorDuplicatePhoto", args)
+	case BookingPendingReview:
+		return i18n.Tr(locale, "message.errorBookingPendingReview", args)
+	case PurchaseReferenceConflict:
+		return i18n.Tr(locale, "message.errorPurchaseReferenceConflict", args)
+	case TransactionAtInFuture:
+		return i18n.Tr(locale, "message.errorTransactionAtInFuture", args)
+	case ImportInProgress:
+		return i18n.Tr(locale, "message.errorImportInProgress", args)
+	case ImportInvalidFile:
+		return i18n.Tr(locale, "message.errorImportInvalidFile", args)
+	case PurchaseTransactionStatusInvalid:
+		return i18n.Tr(locale, "message.errorPurchaseTransactionStatusInvalid", args)
+	case RefundAmountExceeded:
+		return i18n.Tr(locale, "message.errorRefundAmountExceeded", args)
+	case CurrencyRateNotFound:
+		return i18n.Tr(locale, "message.errorCurrencyRateNotFound", args)
+	case ApiKeyRevoked:
+		return i18n.Tr(locale, "message.errorApiKeyRevoked", args)
+	case InvalidApiKeyExpiresAt:
+		return i18n.Tr(locale, "message.errorInvalidApiKeyExpiresAt", args)
+	case UnknownPermission:
+		return i18n.Tr(locale, "message.errorUnknownPermission", args)
+	case TooManyRequestsCodeError:
+		return i18n.Tr(locale, "message.errorTooManyRequests", args)
+	case IdempotencyKeyInvalid:
+		return i18n.Tr(locale, "message.errorIdempotencyKeyInvalid", args)
+	case IdempotencyKeyMismatch:
+		return i18n.Tr(locale, "message.errorIdempotencyKeyMismatch", args)
+	case IdempotencyKeyInProgress:
+		return i18n.Tr(locale, "message.errorIdempotencyKeyInProgress", args)
+	case RequestBodyTooLarge:
+		return i18n.Tr(locale, "message.errorRequestBodyTooLarge", args)
+	case CurrencyAmountInvalid:
+		return i18n.Tr(locale, "message.errorCurrencyAmountInvalid", args)
 	default:
 		return ""
 	}
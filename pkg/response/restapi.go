@@ -13,6 +13,7 @@ import (
 	beego "github.com/beego/beego/v2/server/web"
 	"github.com/beego/beego/v2/server/web/context"
 	validatorGo "github.com/go-playground/validator/v10"
+	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
 	"github.com/radyatamaa/technical-test-aichat/pkg/validator"
 )
 
@@ -36,6 +37,7 @@ type ApiResponse struct {
 
 type Errors struct {
 	Field       string `json:"field"`
+	Code        string `json:"code,omitempty"`
 	Description string `json:"message"`
 }
 
@@ -52,12 +54,27 @@ func (r ApiResponse) Ok(ctx *context.Context, message string, data interface{})
 }
 
 func (r ApiResponse) ResponseError(ctx *context.Context, httpStatus int, errorCode string, message string, err error) error {
+	return r.ResponseErrorWithData(ctx, httpStatus, errorCode, message, nil, err)
+}
+
+// ResponseErrorWithData error response carrying data, e.g. the state the client needs to retry the request
+func (r ApiResponse) ResponseErrorWithData(ctx *context.Context, httpStatus int, errorCode string, message string, data interface{}, err error) error {
 	var apiResponse ApiResponse
 	var errorValidations []Errors = nil
 
 	ctx.Output.SetStatus(httpStatus)
 
-	if err != nil {
+	var ruleErrors RuleErrors
+	if err != nil && errors.As(err, &ruleErrors) {
+		lang := helper.GetLangVersion(ctx)
+		for _, v := range ruleErrors {
+			errorValidations = append(errorValidations, Errors{
+				Field:       v.Rule,
+				Code:        v.Code,
+				Description: ErrorCodeText(v.Code, lang),
+			})
+		}
+	} else if err != nil {
 		if ctx.Input.RequestBody != nil {
 			validateJsonError := checkJsonRequest(err)
 			if len(validateJsonError) > 0 {
@@ -99,6 +116,7 @@ func (r ApiResponse) ResponseError(ctx *context.Context, httpStatus int, errorCo
 	apiResponse.Message = message
 	apiResponse.Timestamp = time.Now().Format("2006-01-02 15:04:05")
 	apiResponse.Errors = errorValidations
+	apiResponse.Data = data
 
 	return ctx.Output.JSON(apiResponse, beego.BConfig.RunMode != "prod", false)
 }
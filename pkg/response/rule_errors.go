@@ -0,0 +1,20 @@
+package response
+
+import "strings"
+
+// RuleError failed rule with the error code used to translate it
+type RuleError struct {
+	Rule string
+	Code string
+}
+
+// RuleErrors every failed rule of a request, each one is rendered in ApiResponse.Errors
+type RuleErrors []RuleError
+
+func (e RuleErrors) Error() string {
+	rules := make([]string, 0, len(e))
+	for _, v := range e {
+		rules = append(rules, v.Rule)
+	}
+	return "rules not passed: " + strings.Join(rules, ",")
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/radyatamaa/technical-test-aichat/pkg/zaplogger"
+)
+
+// Job background work executed every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler run registered jobs in their own goroutine until Stop is called
+type Scheduler struct {
+	zapLogger zaplogger.Logger
+	jobs      []Job
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewScheduler(zapLogger zaplogger.Logger) *Scheduler {
+	return &Scheduler{
+		zapLogger: zapLogger,
+	}
+}
+
+// Register add a job, jobs without interval are disabled
+func (s *Scheduler) Register(job Job) {
+	if job.Interval <= 0 {
+		s.zapLogger.Infof("job %s disabled", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start run every registered job, the first execution happens after one interval
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+		s.zapLogger.Infof("job %s started, interval %s", job.Name, job.Interval)
+	}
+}
+
+// Stop cancel the running jobs and wait until they return or ctx is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.zapLogger.Infof("jobs stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.zapLogger.Errorf("job %s panic: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		s.zapLogger.Errorf("job %s failed: %v", job.Name, err)
+	}
+}
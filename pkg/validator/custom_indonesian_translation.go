@@ -100,6 +100,21 @@ func registerCustomIndonesianTranslator(v *validatorGo.Validate, trans ut.Transl
 		panic(err)
 	}
 
+	if err := v.RegisterTranslation("currency", trans, func(ut ut.Translator) error {
+		if err := ut.Add("currency", "{0} memiliki desimal lebih banyak dari yang didukung nominal.", false); err != nil {
+			return err
+		}
+		return nil
+	}, func(ut ut.Translator, fe validatorGo.FieldError) string {
+		t, err := ut.T(fe.Tag(), fe.Field(), fe.Param())
+		if err != nil {
+			return fe.(error).Error()
+		}
+		return t
+	}); err != nil {
+		panic(err)
+	}
+
 	if err := v.RegisterTranslation("check_fk", trans, func(ut ut.Translator) error {
 		if err := ut.Add("check_fk", "{0} tidak ditemukan.", false); err != nil {
 			return err
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/radyatamaa/technical-test-aichat/pkg/helper"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
 	"gorm.io/gorm"
 
 	validatorGo "github.com/go-playground/validator/v10"
@@ -29,6 +30,9 @@ func registerCustomValidation(db *gorm.DB, v *validatorGo.Validate) {
 	if err := v.RegisterValidation("no_space", ValidateNoSpace); err != nil {
 		panic(err)
 	}
+	if err := v.RegisterValidation("currency", ValidateCurrency); err != nil {
+		panic(err)
+	}
 	if err := v.RegisterValidation("check_fk", func(fl validatorGo.FieldLevel) bool {
 		param := strings.Split(fl.Param(), `:`)
 		paramFieldValue := param[0]
@@ -181,6 +185,11 @@ func hasValue(fl validatorGo.FieldLevel) bool {
 	return requireCheckFieldKind(fl, "")
 }
 
+// ValidateCurrency the amounts of the currency fit in a money.Amount, e.g. not KWD with 3 decimals
+func ValidateCurrency(field validatorGo.FieldLevel) bool {
+	return money.CheckCurrency(field.Field().String()) == nil
+}
+
 func ValidateNoSpace(field validatorGo.FieldLevel) bool {
 	value := field.Field().String()
 
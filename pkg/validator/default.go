@@ -14,6 +14,7 @@ import (
 	validatorGo "github.com/go-playground/validator/v10"
 	enTranslations "github.com/go-playground/validator/v10/translations/en"
 	idTranslator "github.com/go-playground/validator/v10/translations/id"
+	"github.com/radyatamaa/technical-test-aichat/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -152,6 +153,13 @@ func (v *defaultValidator) lazyInit() {
 
 		// add any custom validations etc. here
 		registerCustomValidation(v.db, v.validate)
+		// money amounts are validated as decimal numbers e.g. max=99999999.99
+		v.validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			if amount, ok := field.Interface().(money.Amount); ok {
+				return amount.Float64()
+			}
+			return nil
+		}, money.Amount(0))
 
 		englishTranslate := en.New()
 		v.translator = ut.New(englishTranslate, englishTranslate, id.New())